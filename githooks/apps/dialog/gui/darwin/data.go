@@ -96,3 +96,13 @@ type NotifyData struct {
 	Text string
 	Opts NotifyOpts
 }
+
+// TextData holds all data for a text dialog.
+type TextData struct {
+	Title       string
+	Text        string
+	OkLabel     string
+	CancelLabel string
+	Width       uint
+	Height      uint
+}
@@ -0,0 +1,53 @@
+//go:build darwin
+
+package gui
+
+import (
+	"context"
+	"os/exec"
+
+	gmac "github.com/gabyx/githooks/githooks/apps/dialog/gui/darwin"
+	res "github.com/gabyx/githooks/githooks/apps/dialog/result"
+	sets "github.com/gabyx/githooks/githooks/apps/dialog/settings"
+	strs "github.com/gabyx/githooks/githooks/strings"
+)
+
+func translateText(t *sets.Text) gmac.TextData {
+	d := gmac.TextData{
+		Title:       t.Title,
+		Text:        t.Text,
+		OkLabel:     t.OkLabel,
+		CancelLabel: t.CancelLabel,
+		Width:       t.Width,
+		Height:      t.Height}
+
+	if strs.IsEmpty(d.OkLabel) {
+		d.OkLabel = "OK"
+	}
+
+	if d.Width == 0 {
+		d.Width = 600 // nolint: gomnd
+	}
+
+	if d.Height == 0 {
+		d.Height = 400 // nolint: gomnd
+	}
+
+	return d
+}
+
+// ShowText shows a text dialog with a scrollable text view.
+func ShowText(ctx context.Context, t *sets.Text) (res.Text, error) {
+	_, err := gmac.RunOSAScript(ctx, "text", translateText(t), "")
+	if err == nil {
+		return res.Text{General: res.OkResult()}, nil
+	}
+
+	if err, ok := err.(*exec.ExitError); ok {
+		if err.ExitCode() == gmac.ExitCodeCancel {
+			return res.Text{General: res.CancelResult()}, nil
+		}
+	}
+
+	return res.Text{}, err
+}
@@ -0,0 +1,21 @@
+//go:build !windows && !darwin
+
+package gui
+
+import (
+	"context"
+
+	gunix "github.com/gabyx/githooks/githooks/apps/dialog/gui/unix"
+	res "github.com/gabyx/githooks/githooks/apps/dialog/result"
+	set "github.com/gabyx/githooks/githooks/apps/dialog/settings"
+)
+
+// ShowText shows a text dialog with a scrollable text view.
+func ShowText(ctx context.Context, t *set.Text) (r res.Text, err error) {
+	zenity, err := gunix.GetZenityExecutable()
+	if err != nil {
+		return
+	}
+
+	return ShowTextZenity(ctx, zenity, t)
+}
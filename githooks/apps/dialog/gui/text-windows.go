@@ -0,0 +1,16 @@
+//go:build windows
+
+package gui
+
+import (
+	"context"
+
+	gwin "github.com/gabyx/githooks/githooks/apps/dialog/gui/windows"
+	res "github.com/gabyx/githooks/githooks/apps/dialog/result"
+	set "github.com/gabyx/githooks/githooks/apps/dialog/settings"
+)
+
+// ShowText shows a text dialog with a scrollable text view.
+func ShowText(ctx context.Context, t *set.Text) (res.Text, error) {
+	return gwin.ShowText(ctx, t)
+}
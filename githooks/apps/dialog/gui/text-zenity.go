@@ -0,0 +1,78 @@
+//go:build !windows
+
+package gui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	gunix "github.com/gabyx/githooks/githooks/apps/dialog/gui/unix"
+	res "github.com/gabyx/githooks/githooks/apps/dialog/result"
+	set "github.com/gabyx/githooks/githooks/apps/dialog/settings"
+	cm "github.com/gabyx/githooks/githooks/common"
+	strs "github.com/gabyx/githooks/githooks/strings"
+)
+
+// ShowTextZenity shows a text dialog with `zenity`.
+func ShowTextZenity(ctx context.Context, zenity string, t *set.Text) (r res.Text, err error) {
+
+	// Zenity reads the text from a file.
+	file, err := os.CreateTemp("", "githooks-text-*.txt")
+	if err != nil {
+		return
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString(t.Text)
+	err = cm.CombineErrors(err, file.Close())
+	if err != nil {
+		return
+	}
+
+	args := []string{"--text-info", "--filename", file.Name(), "--font", "monospace"}
+
+	// Zenity prints default title and text if not set.
+	args = append(args, "--title", t.Title)
+
+	if t.Width > 0 {
+		args = append(args, "--width", fmt.Sprintf("%d", t.Width))
+	}
+
+	if t.Height > 0 {
+		args = append(args, "--height", fmt.Sprintf("%d", t.Height))
+	}
+
+	switch t.WindowIcon {
+	case set.ErrorIcon:
+		args = append(args, "--window-icon=error")
+	case set.WarningIcon:
+		args = append(args, "--window-icon=warning")
+	case set.InfoIcon:
+		args = append(args, "--window-icon=info")
+	case set.QuestionIcon:
+		args = append(args, "--window-icon=question")
+	}
+
+	if strs.IsNotEmpty(t.OkLabel) {
+		args = append(args, "--ok-label", t.OkLabel)
+	}
+
+	if strs.IsNotEmpty(t.CancelLabel) {
+		args = append(args, "--cancel-label", t.CancelLabel)
+	}
+
+	_, err = gunix.RunZenity(ctx, zenity, args, "")
+	if err == nil {
+		return res.Text{General: res.OkResult()}, nil
+	}
+
+	if err, ok := err.(*exec.ExitError); ok {
+		if err.ExitCode() == 1 {
+			return res.Text{General: res.CancelResult()}, nil
+		}
+	}
+
+	return res.Text{}, err
+}
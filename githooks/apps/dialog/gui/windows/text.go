@@ -0,0 +1,135 @@
+//go:build windows
+
+package gui
+
+import (
+	"context"
+	"strings"
+
+	res "github.com/gabyx/githooks/githooks/apps/dialog/result"
+	sets "github.com/gabyx/githooks/githooks/apps/dialog/settings"
+	strs "github.com/gabyx/githooks/githooks/strings"
+
+	"github.com/lxn/walk"
+
+	. "github.com/lxn/walk/declarative"
+)
+
+type TextApp struct {
+	*walk.Dialog
+
+	acceptPB *walk.PushButton
+	cancelPB *walk.PushButton
+}
+
+func defineTextButtons(app *TextApp, text *sets.Text, r *res.Text) []Widget {
+	ok := "OK"
+	if strs.IsNotEmpty(text.OkLabel) {
+		ok = text.OkLabel
+	}
+
+	okCallback := func() {
+		*r = res.Text{General: res.OkResult()}
+		app.Accept()
+	}
+
+	var cancelCallback func()
+	if strs.IsNotEmpty(text.CancelLabel) {
+		cancelCallback = func() {
+			*r = res.Text{General: res.CancelResult()}
+			app.Cancel()
+		}
+	}
+
+	return defineOkCancelButtons(
+		ok, text.CancelLabel, nil,
+		&app.acceptPB, &app.cancelPB,
+		okCallback, cancelCallback, nil)
+}
+
+// Shows a text dialog with a scrollable text view.
+// nolint: gomnd
+func ShowText(ctx context.Context, text *sets.Text) (r res.Text, err error) {
+
+	app := &TextApp{}
+
+	minSize := Size{Width: 400, Height: 300}
+	size := walk.Size{Width: 700, Height: 500}
+
+	if text.Width != 0 {
+		size.Width = int(text.Width)
+	}
+
+	if text.Height != 0 {
+		size.Height = int(text.Height)
+	}
+
+	// The edit control needs Windows line endings.
+	content := strings.ReplaceAll(strings.ReplaceAll(text.Text, "\r\n", "\n"), "\n", "\r\n")
+
+	// nolint: gomnd
+	m := Dialog{
+		AssignTo:      &app.Dialog,
+		Title:         text.Title,
+		MinSize:       minSize,
+		DefaultButton: &app.acceptPB,
+		CancelButton:  &app.cancelPB,
+
+		Layout: VBox{
+			Spacing: 8,
+			Margins: Margins{
+				Left:   12,
+				Top:    12,
+				Bottom: 12,
+				Right:  12}},
+
+		Children: []Widget{
+			TextEdit{
+				Text:     content,
+				ReadOnly: true,
+				VScroll:  true,
+				HScroll:  true,
+				Font:     Font{Family: "Consolas", PointSize: 9}},
+			Composite{
+				Layout:   HBox{SpacingZero: true, MarginsZero: true},
+				Children: defineTextButtons(app, text, &r),
+			},
+		},
+	}
+
+	if err = m.Create(nil); err != nil {
+		return
+	}
+
+	app.Disposing().Once(func() {
+		// The dialog was closed -> Make it canceled if
+		// not yet set (ok or cancel pressed).
+		if r.IsUnset() {
+			r = res.Text{General: res.CancelResult()}
+		}
+	})
+
+	centerAndSetSize(app.Dialog, size)
+
+	if text.ForceTopMost {
+		forceTopMost(app.Dialog)
+	}
+
+	if ctx != nil {
+		watchTimeout(ctx, app.Dialog)
+	}
+
+	ret := app.Run()
+
+	if ret == walk.DlgCmdCancel || ret == walk.DlgCmdClose {
+		r = res.Text{General: res.CancelResult()}
+
+		return
+	}
+
+	if ctx != nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+
+	return
+}
@@ -71,6 +71,11 @@ type Entry struct {
 	Text string
 }
 
+// Text is the result type for text dialogs.
+type Text struct {
+	General `yaml:",inline"`
+}
+
 // File is the result type for file dialogs.
 type File struct {
 	General `yaml:",inline"`
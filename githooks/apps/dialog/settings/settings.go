@@ -102,6 +102,17 @@ type Entry struct {
 	ForceTopMost bool // Forces the window to be always on top. Only on Windows supported.
 }
 
+// Text are options for the text dialog which shows
+// a long text (e.g. a diff) in a scrollable view.
+type Text struct {
+	General
+	GeneralButton
+
+	Text string
+
+	ForceTopMost bool // Forces the window to be always on top. Only on Windows supported.
+}
+
 // Notification are options for the notification.
 type Notification struct {
 	General
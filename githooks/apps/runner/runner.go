@@ -17,6 +17,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mitchellh/go-homedir"
@@ -36,6 +37,13 @@ func mainRun() (exitCode int) {
 
 	log.DebugF("Githooks Runner [version: %s]", build.BuildVersion)
 
+	if len(os.Args) == 2 && os.Args[1] == "--version" { // nolint: gomnd
+		// Used by the installer to check the installed runner.
+		log.InfoF("Githooks Runner [version: %s]", build.BuildVersion)
+
+		return
+	}
+
 	if cm.IsBenchmark {
 		startTime := cm.GetStartTime()
 		defer func() {
@@ -63,6 +71,10 @@ func mainRun() (exitCode int) {
 	log.AssertNoErrorF(err, "Errors while loading checksum store.")
 	log.DebugF("%s", checksums.Summary())
 
+	expiry, err := hooks.GetEffectiveTrustExpiry(settings.GitX, &settings.Policy)
+	log.AssertNoErrorF(err, "Could not get trust expiry.")
+	checksums.SetExpiry(expiry)
+
 	// Set this repositories hook namespace.
 	ns, err := hooks.GetHooksNamespace(settings.RepositoryHooksDir)
 	log.AssertNoErrorF(err, "Errors while loading hook namespace.")
@@ -76,15 +88,24 @@ func mainRun() (exitCode int) {
 		[]string{settings.HookName},
 		settings.HookNamespace)
 	log.AssertNoErrorF(err, "Errors while loading ignore patterns.")
+
+	settings.IgnoreConditions = hooks.NewIgnoreConditionContext(settings.GitX, settings.Args, true)
+	ignores.EvaluateConditions(settings.IgnoreConditions)
+
 	log.DebugF("User ignore patterns: '%+q'.", ignores.User)
 	log.DebugF("Accumuldated repository ignore patterns: '%q'.", ignores.HooksDir)
 
+	settings.SkipPatterns, err = hooks.GetEnvSkipPatterns(settings.HookNamespace)
+	log.AssertNoErrorF(err, "Errors while loading patterns in '%s'.", hooks.EnvVariableSkip)
+	settings.SkipReported = strs.NewStringSet(0)
+
 	defer storePendingData(&settings, &uiSettings, &ignores, &checksums)
 
 	if settings.Disabled {
 		// Githooks is disabled, run minimal stuff.
 		executeLFSHooks(&settings)
 		executeOldHook(&settings, &uiSettings, &ignores, &checksums)
+		executeExternalHooks(&settings, &uiSettings, &ignores, &checksums)
 
 		return
 	}
@@ -92,8 +113,10 @@ func mainRun() (exitCode int) {
 	exportGeneralVars(&settings)
 	exportStagedFiles(&settings)
 	updateGithooks(&settings, &uiSettings)
+	checkVersionRequirement(&settings, &uiSettings)
 	executeLFSHooks(&settings)
 	executeOldHook(&settings, &uiSettings, &ignores, &checksums)
+	executeExternalHooks(&settings, &uiSettings, &ignores, &checksums)
 	updateLocalHookImages(&settings)
 
 	hooks := collectHooks(&settings, &uiSettings, &ignores, &checksums)
@@ -159,13 +182,33 @@ func setupSettings(repoPath string) (HookSettings, UISettings) {
 	nonInteractive := hooks.IsRunnerNonInteractive(gitx, git.Traverse)
 	skipNonExistingSharedHooks := hooks.SkipNonExistingSharedHooks(gitx, git.Traverse)
 	skipUntrustedHooks, _ := hooks.SkipUntrustedHooks(gitx, git.Traverse)
+	trustSharedRevisions := hooks.IsTrustSharedRevisions(gitx, git.Traverse)
+
+	policy, err := hooks.LoadTrustPolicy(gitx)
+	log.AssertNoErrorPanicF(err, "Could not load trust policy.")
 
 	isTrusted, hasTrustFile, trustAllSet := hooks.IsRepoTrusted(gitx, repoPath)
-	if !isTrusted && hasTrustFile && !trustAllSet && !nonInteractive && !isGithooksDisabled {
-		isTrusted = showTrustRepoPrompt(gitx, promptx, repoPath)
+	if forbidden, remoteURL := policy.IsTrustAllForbidden(gitx); forbidden && hasTrustFile {
+		log.WarnF("Trusting all hooks of this repository is forbidden\n"+
+			"by the trust policy '%s' for remote '%s'.", policy.File, remoteURL)
+		isTrusted = false
+	} else if !isTrusted && hasTrustFile && !trustAllSet && !nonInteractive && !isGithooksDisabled {
+		isTrusted = showTrustRepoPrompt(gitx, promptx, repoPath, installDir)
+	}
+
+	// Only a trusted repository can require a Githooks version.
+	var versionReq *hooks.RepoVersionRequirement
+	if isTrusted {
+		req, hasVersionReq, err := hooks.LoadRepoVersionRequirement(repoPath)
+		log.AssertNoErrorF(err, "Could not load the required Githooks version.")
+		if hasVersionReq {
+			versionReq = &req
+		}
 	}
 
 	runContainerized := hooks.IsContainerizedHooksEnabled(gitx, true)
+	sharedVerify := hooks.GetSharedRepoVerifyConfig(gitx, git.Traverse)
+	offline := hooks.IsOfflineMode(gitx, git.Traverse, true)
 
 	s := HookSettings{
 		Args:               os.Args[2:],
@@ -184,9 +227,14 @@ func setupSettings(repoPath string) (HookSettings, UISettings) {
 
 		SkipNonExistingSharedHooks: skipNonExistingSharedHooks,
 		SkipUntrustedHooks:         skipUntrustedHooks,
+		TrustSharedRevisions:       trustSharedRevisions,
 		NonInteractive:             nonInteractive,
 		ContainerizedHooksEnabled:  runContainerized,
-		Disabled:                   isGithooksDisabled}
+		Disabled:                   isGithooksDisabled,
+		Offline:                    offline,
+		SharedVerify:               sharedVerify,
+		Policy:                     policy,
+		VersionRequirement:         versionReq}
 
 	logInvocation(&s)
 
@@ -248,7 +296,11 @@ func assertRegistered(gitx *git.Context, installDir string) {
 	}
 }
 
-func showTrustRepoPrompt(gitx *git.Context, promptx prompt.IContext, repoPath string) (isTrusted bool) {
+func showTrustRepoPrompt(
+	gitx *git.Context,
+	promptx prompt.IContext,
+	repoPath string,
+	installDir string) (isTrusted bool) {
 	question := strs.Fmt(
 		`This repository '%s'
 wants you to trust all current and future hooks without prompting.
@@ -261,15 +313,21 @@ Do you want to allow running every current and future hooks?`, repoPath)
 		return
 	}
 
+	decision := hooks.TrustDecisionDeclined
 	if answer == "y" {
 		err := hooks.SetTrustAllSetting(gitx, true, false)
 		log.AssertNoErrorF(err, "Could not store trust setting.")
 		isTrusted = true
+		decision = hooks.TrustDecisionTrusted
 	} else {
 		err := hooks.SetTrustAllSetting(gitx, false, false)
 		log.AssertNoErrorF(err, "Could not store trust setting.")
 	}
 
+	err = hooks.AppendTrustAuditEntries(installDir,
+		hooks.NewTrustAuditEntry(repoPath, "", "", decision))
+	log.AssertNoErrorF(err, "Could not write trust audit log.")
+
 	return
 }
 
@@ -314,7 +372,7 @@ func exportStagedFiles(settings *HookSettings) {
 
 func updateGithooks(settings *HookSettings, uiSettings *UISettings) {
 
-	if !shouldRunUpdateCheck(settings) {
+	if !shouldRunUpdateCheck(settings) || skipOffline(settings, "Githooks update check") {
 		return
 	}
 
@@ -329,9 +387,25 @@ func updateGithooks(settings *HookSettings, uiSettings *UISettings) {
 		opts = append(opts, "--use-pre-release")
 	}
 
+	acceptUpdate := updates.DefaultAcceptUpdateCallback(log, uiSettings.PromptCtx, updates.AcceptNonInteractiveNone)
+	if req := settings.VersionRequirement; req != nil && settings.IsRepoTrusted {
+		defaultAccept := acceptUpdate
+		acceptUpdate = func(status *updates.ReleaseStatus) bool {
+			if !req.IsFulfilled(status.UpdateVersion) {
+				log.InfoF("Skipping update to version '%s' since this repository\n"+
+					"requires version '%s' (see '%s').",
+					status.UpdateVersion.String(), req.Required, hooks.GetRepoVersionFileRel())
+
+				return false
+			}
+
+			return defaultAccept(status)
+		}
+	}
+
 	updateAvailable, accepted, err := updates.RunUpdate(
 		settings.InstallDir,
-		updates.DefaultAcceptUpdateCallback(log, uiSettings.PromptCtx, updates.AcceptNonInteractiveNone),
+		acceptUpdate,
 		usePreRelease,
 		func() error {
 			return updates.RunUpdateOverExecutable(settings.InstallDir,
@@ -370,13 +444,115 @@ func updateGithooks(settings *HookSettings, uiSettings *UISettings) {
 		"  $ git hooks update disable")
 }
 
-func shouldRunUpdateCheck(settings *HookSettings) bool {
-	if settings.HookName != "post-commit" {
-		return false
+// checkVersionRequirement checks if the installed Githooks version fulfills
+// the version required by a trusted repository and offers to install a matching version
+// (at most once a day on `post-commit` like the update check).
+// Fails if the repository enforces the required version.
+func checkVersionRequirement(settings *HookSettings, uiSettings *UISettings) {
+	req := settings.VersionRequirement
+	if req == nil || req.IsFulfilled(build.GetBuildVersion()) {
+		return
+	}
+
+	mess := strs.Fmt(
+		"Githooks version '%s' does not match the required version '%s'\n"+
+			"of this repository (see '%s').",
+		build.GetBuildVersion().String(), req.Required, hooks.GetRepoVersionFileRel())
+
+	tag := ""
+	if !settings.NonInteractive && isUpdateCheckDue(settings) &&
+		!skipOffline(settings, "required version check") {
+		var installed bool
+		tag, installed = installRequiredVersion(settings, uiSettings, req, mess)
+
+		if installed {
+			return
+		}
+	}
+
+	hint := "Install a matching version with:\n" +
+		"  $ git hooks installer --update-to <version-tag>"
+	if strs.IsNotEmpty(tag) {
+		hint = strs.Fmt("Install the matching version '%s' with:\n"+
+			"  $ git hooks installer --update-to '%s'", tag, tag)
 	}
 
+	if req.Enforce {
+		log.PanicF("%s\n%s", mess, hint)
+	}
+
+	log.WarnF("%s\n%s", mess, hint)
+}
+
+// installRequiredVersion fetches the release clone and offers to install the latest
+// version matching the required version `req` of the repository.
+// Returns the matching version tag and if it has been installed.
+func installRequiredVersion(
+	settings *HookSettings,
+	uiSettings *UISettings,
+	req *hooks.RepoVersionRequirement,
+	mess string) (tag string, installed bool) {
+
+	usePreRelease := settings.GitX.GetConfig(hooks.GitCKAutoUpdateUsePrerelease, git.GlobalScope) == git.GitCVTrue
+
+	err := updates.RecordUpdateCheckTimestamp()
+	log.AssertNoErrorF(err, "Could not record update check timestamp.")
+
+	cloneDir := hooks.GetReleaseCloneDir(settings.InstallDir)
+	_, err = updates.FetchUpdates(cloneDir, "", "", build.BuildTag, true, updates.ErrorOnWrongRemote, usePreRelease, nil)
+	if err != nil {
+		log.AssertNoErrorF(err, "Could not fetch updates.")
+
+		return
+	}
+
+	tag, ver, err := updates.FindVersionTag(cloneDir, req.GetConstraints(), usePreRelease)
+	if err != nil || ver == nil {
+		log.AssertNoErrorF(err, "Could not find a version tag in '%s'.", cloneDir)
+
+		return
+	}
+
+	question := strs.Fmt("%s\nWould you like to install version '%s' now?", mess, tag)
+	answer, err := uiSettings.PromptCtx.ShowOptions(question, "(Yes/no)", "Y/n", "Yes", "No")
+	log.AssertNoErrorF(err, "Could not show prompt.")
+
+	if answer != "y" {
+		return
+	}
+
+	opts := []string{"--internal-auto-update", "--update-to", tag}
+	if usePreRelease {
+		opts = append(opts, "--use-pre-release")
+	}
+
+	err = updates.RunUpdateOverExecutable(settings.InstallDir,
+		&settings.ExecX,
+		cm.UseStreams(nil, os.Stderr, os.Stderr), // Must not use stdout, because Git hooks.
+		opts...)
+
+	if err != nil {
+		log.AssertNoErrorF(err, "Installing version '%s' failed. See latest log '%s' !",
+			tag, path.Join(os.TempDir(), "githooks-installer-*.log"))
+
+		return
+	}
+
+	log.InfoF("Version '%s' successfully installed.", tag)
+
+	return tag, true
+}
+
+func shouldRunUpdateCheck(settings *HookSettings) bool {
 	enabled, _ := updates.GetAutomaticUpdateCheckSettings(settings.GitX)
-	if !enabled {
+
+	return enabled && isUpdateCheckDue(settings)
+}
+
+// isUpdateCheckDue reports if a check over the network for new versions is due,
+// which is the case at most once a day on `post-commit`.
+func isUpdateCheckDue(settings *HookSettings) bool {
+	if settings.HookName != "post-commit" {
 		return false
 	}
 
@@ -386,6 +562,27 @@ func shouldRunUpdateCheck(settings *HookSettings) bool {
 	return time.Since(lastUpdateCheck).Hours() > 24.0 //nolint: gomnd
 }
 
+var offlineNotice sync.Once
+
+// skipOffline reports if the network operation `what` needs to be skipped
+// due to the offline mode. The notice is only shown once.
+func skipOffline(settings *HookSettings, what string) bool {
+	if !settings.Offline {
+		return false
+	}
+
+	offlineNotice.Do(func() {
+		log.Info("Githooks is in offline mode: skipping all network operations\n" +
+			"(shared hooks updates, image pulls, update checks).\n" +
+			"To disable it, run:\n" +
+			"  $ git hooks config offline --disable")
+	})
+
+	log.DebugF("Offline mode: skipped %s.", what)
+
+	return true
+}
+
 func executeLFSHooks(settings *HookSettings) {
 
 	if !strs.Includes(hooks.LFSHookNames[:], settings.HookName) {
@@ -418,6 +615,30 @@ func executeLFSHooks(settings *HookSettings) {
 	}
 }
 
+// isSkippedByEnv reports if the hook with namespace path `namespacePath`
+// is skipped by `GITHOOKS_SKIP` and is allowed to be skipped by the trust policy.
+// Each namespace path is only reported once.
+func isSkippedByEnv(settings *HookSettings, namespacePath string) bool {
+	if !settings.SkipPatterns.Matches(namespacePath) {
+		return false
+	}
+
+	report := !settings.SkipReported.Exists(namespacePath)
+	settings.SkipReported.Insert(namespacePath)
+
+	if settings.Policy.IsSkipForbidden(namespacePath) {
+		log.WarnIfF(report, "Hook '%s' cannot be skipped by '%s':\n"+
+			"Skipping is forbidden by the trust policy '%s'.",
+			namespacePath, hooks.EnvVariableSkip, settings.Policy.File)
+
+		return false
+	}
+
+	log.InfoIfF(report, "Hook '%s' skipped by env '%s'.", namespacePath, hooks.EnvVariableSkip)
+
+	return true
+}
+
 func failOrWarnOnActiveUntrusted(skipUntrustedHooks bool, hook *hooks.Hook) {
 	if hook.Active && !hook.Trusted {
 		if skipUntrustedHooks {
@@ -448,7 +669,7 @@ func executeOldHook(
 	isIgnored := func(namespacePath string) bool {
 		ignored, byUser := ignores.IsIgnored(namespacePath)
 
-		return ignored && byUser
+		return (ignored && byUser) || isSkippedByEnv(settings, namespacePath)
 	}
 
 	isTrusted := func(hookPath string) (bool, string) {
@@ -476,7 +697,61 @@ func executeOldHook(
 		return
 	}
 
-	hook := &hooks[0]
+	executeTrustedHook(settings, uiSettings, checksums, &hooks[0], settings.SkipNonExistingSharedHooks)
+}
+
+// executeExternalHooks executes the hooks of all chained
+// external hook managers (namespace 'gh-external').
+func executeExternalHooks(
+	settings *HookSettings,
+	uiSettings *UISettings,
+	ignores *hooks.RepoIgnorePatterns,
+	checksums *hooks.ChecksumStore) {
+
+	managers := hooks.GetExternalHookManagers(settings.GitX, settings.RepositoryDir)
+
+	// External hooks can only be ignored by user ignores...
+	isIgnored := func(namespacePath string) bool {
+		ignored, byUser := ignores.IsIgnored(namespacePath)
+
+		return (ignored && byUser) || isSkippedByEnv(settings, namespacePath)
+	}
+
+	isTrusted := func(hookPath string) (bool, string) {
+		if settings.IsRepoTrusted {
+			return true, ""
+		}
+
+		trusted, sha, e := checksums.IsTrusted(hookPath)
+		log.AssertNoErrorPanicF(e, "Could not check trust status '%s'.", hookPath)
+
+		return trusted, sha
+	}
+
+	for i := range managers {
+		hook, err := managers[i].GetHook(
+			settings.GitX, settings.RepositoryDir, settings.HookName, isIgnored, isTrusted)
+		log.AssertNoErrorPanicF(err, "Could not get hook of '%s'.", managers[i].Name)
+
+		if hook == nil {
+			log.DebugF("External hook manager '%s' has no hook '%s'. -> Skip!",
+				managers[i].Name, settings.HookName)
+
+			continue
+		}
+
+		executeTrustedHook(settings, uiSettings, checksums, hook, settings.SkipUntrustedHooks, settings.Args...)
+	}
+}
+
+// executeTrustedHook executes the single hook `hook` if its active and trusted.
+func executeTrustedHook(
+	settings *HookSettings,
+	uiSettings *UISettings,
+	checksums *hooks.ChecksumStore,
+	hook *hooks.Hook,
+	skipUntrusted bool,
+	args ...string) {
 
 	if hook.Active && !hook.Trusted {
 		if !settings.NonInteractive {
@@ -485,7 +760,7 @@ func executeOldHook(
 			showTrustPrompt(uiSettings, checksums, hook)
 		}
 
-		failOrWarnOnActiveUntrusted(settings.SkipNonExistingSharedHooks, hook)
+		failOrWarnOnActiveUntrusted(skipUntrusted, hook)
 	}
 
 	if !hook.Active || !hook.Trusted {
@@ -496,7 +771,7 @@ func executeOldHook(
 	}
 
 	log.DebugF("Executing hook: '%s'.", hook.Path)
-	err = cm.RunExecutable(&settings.ExecX, hook, cm.UseStdStreams(true, true, true))
+	err := cm.RunExecutable(&settings.ExecX, hook, cm.UseStdStreams(true, true, true), args...)
 
 	log.AssertNoErrorPanicF(err, "Hook launch failed: '%q'.", hook)
 }
@@ -515,7 +790,7 @@ func collectHooks(
 	// No parsing of local includes because already happened.
 	h.LocalHooks = getHooksIn(
 		settings, uiSettings, settings.RepositoryDir, settings.RepositoryHooksDir,
-		false, settings.HookNamespace, namespaceEnvs, false, ignores, checksums)
+		false, settings.HookNamespace, namespaceEnvs, false, ignores, checksums, nil)
 
 	// All shared hooks
 	var allAddedShared = make([]string, 0)
@@ -545,7 +820,8 @@ func collectHooks(
 }
 
 func updateLocalHookImages(settings *HookSettings) {
-	if !settings.ContainerizedHooksEnabled || settings.HookName != "post-merge" {
+	if !settings.ContainerizedHooksEnabled || settings.HookName != "post-merge" ||
+		skipOffline(settings, "container images update") {
 		return
 	}
 
@@ -571,7 +847,7 @@ func updateSharedHooks(settings *HookSettings, sharedHooks []hooks.SharedRepo, s
 	triggered := settings.HookName == "post-merge" || updateOnCloneNeeded ||
 		strs.Includes(updateTriggers, settings.HookName)
 
-	if disableUpdate || !triggered {
+	if disableUpdate || !triggered || skipOffline(settings, "shared hooks update") {
 		log.Debug("Shared hooks not updated.")
 
 		return
@@ -755,6 +1031,30 @@ func checkSharedHook(
 		}
 	}
 
+	// Check that the checked out revision is signed
+	// by an allowed key if requested.
+	for _, verify := range hook.GetVerify(settings.SharedVerify) {
+		err := hook.VerifySignature(verify)
+
+		if err != nil {
+			mess := "Refusing to execute shared hooks in '%s'\n" +
+				"The checked out revision could not be verified\n" +
+				"[signature: '%s', allowed keys: '%q']."
+
+			if settings.SkipUntrustedHooks {
+				mess += "\nContinuing..."
+			}
+
+			log.ErrorOrPanicF(isFatal && !settings.SkipUntrustedHooks,
+				err, mess, hook.OriginalURL, verify.Signature, verify.AllowedKeys)
+
+			return false
+		}
+
+		log.DebugF("Shared hooks in '%s' verified [signature: '%s'].",
+			hook.OriginalURL, verify.Signature)
+	}
+
 	return true
 }
 
@@ -768,12 +1068,41 @@ func getHooksIn(
 	namespaceEnvs hooks.NamespaceEnvs,
 	readNamespace bool,
 	ignores *hooks.RepoIgnorePatterns,
-	checksums *hooks.ChecksumStore) (batches hooks.HookPrioList) {
+	checksums *hooks.ChecksumStore,
+	shRepo *hooks.SharedRepo) (batches hooks.HookPrioList) {
 
 	log.DebugF("Getting hooks in '%s'", hooksDir)
 
+	// Determine namespace
+	if readNamespace {
+		ns, err := hooks.GetHooksNamespace(hooksDir)
+		log.AssertNoErrorPanicF(err, "Could not get hook namespace in '%s'", hooksDir)
+		if strs.IsNotEmpty(ns) {
+			hookNamespace = ns
+		}
+	}
+
+	// Check if the shared repository is trusted by the trust policy
+	// or its checked out revision is trusted.
+	isRevisionTrusted := false
+	if shRepo != nil && !settings.IsRepoTrusted {
+		if settings.Policy.IsSharedTrusted(shRepo.OriginalURL, hookNamespace) {
+			isRevisionTrusted = true
+			log.DebugF("Shared hooks in '%s' are trusted by the trust policy '%s'.",
+				shRepo.OriginalURL, settings.Policy.File)
+		} else {
+			var rev hooks.TrustedRevision
+			var e error
+
+			isRevisionTrusted, rev, e = checksums.IsRevisionTrusted(shRepo)
+			log.AssertNoErrorF(e, "Could not check trusted revisions of '%s'.", shRepo.OriginalURL)
+			log.DebugIfF(isRevisionTrusted, "Shared hooks in '%s' are trusted by %s.",
+				shRepo.OriginalURL, rev.Description())
+		}
+	}
+
 	isTrusted := func(hookPath string) (bool, string) {
-		if settings.IsRepoTrusted {
+		if settings.IsRepoTrusted || isRevisionTrusted {
 			return true, ""
 		}
 
@@ -783,26 +1112,24 @@ func getHooksIn(
 		return trusted, sha
 	}
 
-	// Determine namespace
-	if readNamespace {
-		ns, err := hooks.GetHooksNamespace(hooksDir)
-		log.AssertNoErrorPanicF(err, "Could not get hook namespace in '%s'", hooksDir)
-		if strs.IsNotEmpty(ns) {
-			hookNamespace = ns
-		}
-	}
-
 	var internalIgnores hooks.HookPatterns
 	if addInternalIgnores {
 		var e error
 		internalIgnores, e = hooks.GetHookPatternsHooksDir(hooksDir, []string{settings.HookName}, hookNamespace)
 		log.AssertNoErrorPanicF(e, "Could not get worktree ignores in '%s'.", hooksDir)
+		internalIgnores.EvaluateConditions(settings.IgnoreConditions)
 	}
 
 	isIgnored := func(namespacePath string) bool {
-		ignored, _ := ignores.IsIgnored(namespacePath)
+		ignored, _, cond := ignores.IsIgnoredCondition(namespacePath)
+		if !ignored {
+			ignored, cond = internalIgnores.MatchesCondition(namespacePath)
+		}
 
-		return ignored || internalIgnores.Matches(namespacePath)
+		log.DebugIfF(ignored && cond != nil,
+			"Hook '%s' is ignored by condition [%s].", namespacePath, cond)
+
+		return ignored || isSkippedByEnv(settings, namespacePath)
 	}
 
 	allHooks, maxBatches, err := hooks.GetAllHooksIn(
@@ -824,6 +1151,11 @@ func getHooksIn(
 		})
 	}
 
+	if shRepo != nil && settings.TrustSharedRevisions && !settings.NonInteractive {
+		// Let the user trust all hooks in this revision at once.
+		showTrustRevisionPrompt(uiSettings, shRepo, allHooks)
+	}
+
 	// Split all hooks (sorted by the batch names)
 	// into batches.
 	batches = make(hooks.HookPrioList, 1, maxBatches)
@@ -831,6 +1163,12 @@ func getHooksIn(
 	curBatchIdx := 0
 	curBatchName := &allHooks[0].BatchName
 
+	var builtImages []string
+	if settings.ContainerizedHooksEnabled && len(settings.Policy.AllowedRegistries) != 0 {
+		builtImages, err = hooks.GetLocallyBuiltImageReferences(settings.GitX, hooksDir)
+		log.AssertNoErrorPanicF(err, "Could not get built images in '%s'.", hooksDir)
+	}
+
 	for i := range allHooks {
 
 		hook := &allHooks[i]
@@ -853,6 +1191,10 @@ func getHooksIn(
 			continue
 		}
 
+		if settings.ContainerizedHooksEnabled {
+			assertImageAllowed(&settings.Policy, hook, builtImages)
+		}
+
 		if *curBatchName != hook.BatchName {
 			// Batch name changed, add another batch...
 			batches = append(batches, []hooks.Hook{})
@@ -866,6 +1208,27 @@ func getHooksIn(
 	return
 }
 
+// assertImageAllowed fails if the containerized hook `hook`
+// uses an image from a registry not allowed by the trust policy.
+// Images in `builtImages` exist locally as built by Githooks, their base images
+// have already been checked when building them and they are never pulled.
+func assertImageAllowed(policy *hooks.TrustPolicy, hook *hooks.Hook, builtImages []string) {
+	imageRef, err := hooks.GetHookImageReference(hook.Path, hook.Namespace)
+	log.AssertNoErrorPanicF(err, "Could not get image reference of hook '%s'.", hook.Path)
+
+	if strs.IsEmpty(imageRef) || strs.Includes(builtImages, imageRef) {
+		return
+	}
+
+	allowed, registry, err := policy.IsImageAllowed(imageRef)
+	log.AssertNoErrorPanicF(err, "Could not check image of hook '%s'.", hook.Path)
+
+	log.PanicIfF(!allowed,
+		"Hook '%s' runs containerized with image '%s'\n"+
+			"from registry '%s' which is not allowed by the trust policy '%s'.",
+		hook.NamespacePath, imageRef, registry, policy.File)
+}
+
 func getHooksInShared(settings *HookSettings,
 	uiSettings *UISettings,
 	namespaceEnvs hooks.NamespaceEnvs,
@@ -880,7 +1243,7 @@ func getHooksInShared(settings *HookSettings,
 	return getHooksIn(
 		settings, uiSettings,
 		shRepo.RepositoryDir, dir, true, hookNamespace,
-		namespaceEnvs, true, ignores, checksums)
+		namespaceEnvs, true, ignores, checksums, shRepo)
 }
 
 func logBatches(title string, hooks hooks.HookPrioList) {
@@ -908,13 +1271,30 @@ func showTrustPrompt(
 		return
 	}
 
-	mess := strs.Fmt("New or changed hook found:\n'%s'", hook.Path)
+	err := hook.AssertChecksum()
+	log.AssertNoError(err, "Could not compute SHA256 hash of '%s'.", hook.Path)
+
+	mess := strs.Fmt("New or changed hook found:\n'%s'\n[sha256: '%s']", hook.Path, hook.Checksum)
+	if expired, e := checksums.IsExpired(hook.Checksum); e == nil && expired {
+		mess = strs.Fmt("Trust of hook expired:\n'%s'\n[sha256: '%s']", hook.Path, hook.Checksum)
+	}
+
+	change, err := checksums.GetHookChange(hook.Path)
+	log.AssertNoErrorF(err, "Could not get changes of hook '%s'.", hook.Path)
+	mess += formatHookChange(&change)
 
 	acceptHook := uiSettings.AcceptAllChanges
 	disableHook := false
 
 	if !acceptHook {
 
+		if strs.IsNotEmpty(change.Diff) {
+			// Show the full diff in a pager or the dialog's text view.
+			err = uiSettings.PromptCtx.ShowText(
+				strs.Fmt("Changes of hook '%s':\n%s", hook.Path, change.Diff))
+			log.AssertNoErrorF(err, "Could not show changes of hook '%s'.", hook.Path)
+		}
+
 		question := mess + "\nDo you accept the changes?"
 
 		answer, err := uiSettings.PromptCtx.ShowOptions(question,
@@ -939,21 +1319,16 @@ func showTrustPrompt(
 		log.Info("-> Already accepted.")
 	}
 
-	if acceptHook || disableHook {
-		err := hook.AssertSHA1()
-		log.AssertNoError(err, "Could not compute SHA1 hash of '%s'.", hook.Path)
-	}
-
 	if acceptHook {
 		hook.Trusted = true
 
 		uiSettings.AppendTrustedHook(
 			hooks.ChecksumResult{
-				SHA1:          hook.SHA1,
+				Checksum:      hook.Checksum,
 				Path:          hook.Path,
 				NamespacePath: hook.NamespacePath})
 
-		checksums.AddChecksum(hook.SHA1, hook.Path)
+		checksums.AddChecksum(hook.Checksum, hook.Path)
 
 	} else if disableHook {
 		log.InfoF("-> Adding hook\n'%s'\nto disabled list.", hook.Path)
@@ -962,12 +1337,89 @@ func showTrustPrompt(
 
 		uiSettings.AppendDisabledHook(
 			hooks.ChecksumResult{
-				SHA1:          hook.SHA1,
+				Checksum:      hook.Checksum,
 				Path:          hook.Path,
 				NamespacePath: hook.NamespacePath})
 	}
 }
 
+func formatHookChange(change *hooks.HookChange) (s string) {
+	if change.IsNew() {
+		return
+	}
+
+	s = strs.Fmt("\nChanges since last trusted [sha256: '%s'].", change.PreviousChecksum)
+
+	if change.IsImageChanged() {
+		s += strs.Fmt("\nImage reference changed: '%s' -> '%s'", change.PreviousImage, change.Image)
+	}
+
+	return
+}
+
+func showTrustRevisionPrompt(
+	uiSettings *UISettings,
+	shRepo *hooks.SharedRepo,
+	allHooks []hooks.Hook) {
+
+	var untrusted []*hooks.Hook
+	for i := range allHooks {
+		if allHooks[i].Active && !allHooks[i].Trusted {
+			untrusted = append(untrusted, &allHooks[i])
+		}
+	}
+
+	if len(untrusted) == 0 {
+		return
+	}
+
+	commit, modified, err := hooks.GetSharedRevision(shRepo)
+	if err != nil || len(modified) != 0 {
+		log.DebugF("Revision of shared hooks in '%s' cannot be trusted "+
+			"[modified files: '%v', error: '%v'].", shRepo.OriginalURL, len(modified), err)
+
+		return
+	}
+
+	var sb strings.Builder
+	for _, hook := range untrusted {
+		_, _ = strs.FmtW(&sb, "\n %s '%s'", cm.ListItemLiteral, hook.NamespacePath)
+	}
+
+	acceptRevision := uiSettings.AcceptAllChanges
+
+	if !acceptRevision {
+		question := strs.Fmt(
+			"Shared hooks in '%s'\n"+
+				"at commit '%s' contain new or changed hooks:%s\n"+
+				"Do you trust all hooks at this revision?",
+			shRepo.OriginalURL, commit, sb.String())
+
+		answer, err := uiSettings.PromptCtx.ShowOptions(question,
+			"(yes, no)", "y/n", "Yes", "No")
+		log.AssertNoError(err, "Could not get trust prompt answer.")
+
+		acceptRevision = answer == "y"
+	}
+
+	if !acceptRevision {
+		return
+	}
+
+	for _, hook := range untrusted {
+		hook.Trusted = true
+
+		uiSettings.AppendTrustedRevisionHook(
+			hooks.ChecksumResult{
+				Checksum:      hook.Checksum,
+				Path:          hook.Path,
+				NamespacePath: hook.NamespacePath})
+	}
+
+	uiSettings.AppendTrustedRevision(
+		hooks.TrustedRevision{URL: shRepo.OriginalURL, Commit: commit})
+}
+
 func applyEnvToArgs(hs *hooks.Hooks, env []string) {
 	hs.Map(func(h *hooks.Hook) {
 		h.ApplyEnvironmentToArgs(env)
@@ -1110,4 +1562,27 @@ func storePendingData(
 		err := checksums.SyncChecksumAdd(uiSettings.TrustedHooks...)
 		log.AssertNoErrorF(err, "Could not store checksum for hook")
 	}
+
+	// Store all trusted revisions if there are any new ones.
+	if len(uiSettings.TrustedRevisions) != 0 {
+		err := checksums.SyncTrustedRevisionAdd(uiSettings.TrustedRevisions...)
+		log.AssertNoErrorF(err, "Could not store trusted revisions of shared hooks.")
+	}
+
+	// Record all trust decisions in the audit log.
+	var entries []hooks.TrustAuditEntry
+	audit := func(results []hooks.ChecksumResult, decision string) {
+		for i := range results {
+			entries = append(entries,
+				hooks.NewTrustAuditEntry(settings.RepositoryDir,
+					results[i].NamespacePath, results[i].Checksum, decision))
+		}
+	}
+
+	audit(uiSettings.TrustedHooks, hooks.TrustDecisionTrusted)
+	audit(uiSettings.TrustedRevisionHooks, hooks.TrustDecisionTrusted)
+	audit(uiSettings.DisabledHooks, hooks.TrustDecisionDisabled)
+
+	err := hooks.AppendTrustAuditEntries(settings.InstallDir, entries...)
+	log.AssertNoErrorF(err, "Could not write trust audit log.")
 }
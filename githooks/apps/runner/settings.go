@@ -3,6 +3,7 @@ package main
 import (
 	cm "github.com/gabyx/githooks/githooks/common"
 	"github.com/gabyx/githooks/githooks/git"
+	"github.com/gabyx/githooks/githooks/hooks"
 	strs "github.com/gabyx/githooks/githooks/strings"
 )
 
@@ -24,9 +25,20 @@ type HookSettings struct {
 	IsRepoTrusted              bool // If the repository is a trusted repository.
 	SkipNonExistingSharedHooks bool // If Githooks should skip non-existing shared hooks.
 	SkipUntrustedHooks         bool // If Githooks should skip active untrusted hooks.
+	TrustSharedRevisions       bool // If shared repositories are trusted per revision instead of per hook.
 	NonInteractive             bool // If all non-fatal prompts should be default answered.
 	ContainerizedHooksEnabled  bool // If all hooks should run containerized (if they are setup for it).
 	Disabled                   bool // If Githooks has been disabled.
+	Offline                    bool // If all network operations should be skipped.
+
+	SharedVerify *hooks.SharedRepoVerify // Signature verification settings for all shared repositories.
+	Policy       hooks.TrustPolicy       // The organization-wide trust policy.
+
+	VersionRequirement *hooks.RepoVersionRequirement // The Githooks version required by the trusted repository, `nil` if none.
+
+	IgnoreConditions *hooks.IgnoreConditionContext // The state to evaluate conditional ignore patterns.
+	SkipPatterns     hooks.HookPatterns            // Patterns of hooks to skip given by `GITHOOKS_SKIP`.
+	SkipReported     strs.StringSet                // Namespace paths already reported as (not) skipped by `GITHOOKS_SKIP`.
 }
 
 func (s HookSettings) toString() string {
@@ -39,9 +51,11 @@ func (s HookSettings) toString() string {
 			" • Hook Path: '%s'\n"+
 			" • Hook Name: '%s'\n"+
 			" • Trusted: '%v'\n"+
-			" • ContainerizedEnabled: '%v'",
+			" • ContainerizedEnabled: '%v'\n"+
+			" • Offline: '%v'\n"+
+			" • Trust Policy: '%s'",
 		s.Args, s.RepositoryDir,
 		s.RepositoryHooksDir, s.GitDirWorktree,
 		s.InstallDir, s.HookPath, s.HookName, s.IsRepoTrusted,
-		s.ContainerizedHooksEnabled)
+		s.ContainerizedHooksEnabled, s.Offline, s.Policy.File)
 }
@@ -19,6 +19,12 @@ type UISettings struct {
 
 	// All hooks which were newly disabled and need to be recored back
 	DisabledHooks []hooks.ChecksumResult
+
+	// All shared repository revisions which were newly trusted and need to be recorded back
+	TrustedRevisions []hooks.TrustedRevision
+
+	// All hooks which were trusted by newly trusted revisions and need to be audited
+	TrustedRevisionHooks []hooks.ChecksumResult
 }
 
 // AppendTrustedHook appends trusted hooks.
@@ -30,3 +36,13 @@ func (s *UISettings) AppendTrustedHook(checksum ...hooks.ChecksumResult) {
 func (s *UISettings) AppendDisabledHook(checksum ...hooks.ChecksumResult) {
 	s.DisabledHooks = append(s.DisabledHooks, checksum...)
 }
+
+// AppendTrustedRevision appends trusted shared repository revisions.
+func (s *UISettings) AppendTrustedRevision(rev ...hooks.TrustedRevision) {
+	s.TrustedRevisions = append(s.TrustedRevisions, rev...)
+}
+
+// AppendTrustedRevisionHook appends hooks trusted by a trusted shared repository revision.
+func (s *UISettings) AppendTrustedRevisionHook(checksum ...hooks.ChecksumResult) {
+	s.TrustedRevisionHooks = append(s.TrustedRevisionHooks, checksum...)
+}
@@ -1,11 +1,38 @@
 package build
 
-import "embed"
+import (
+	"embed"
+	"io/fs"
+	"strings"
+)
 
-//go:embed embedded/.deploy-pgp embedded/README.md embedded/run-wrapper.sh
+//go:embed embedded/.deploy-pgp embedded/README.md embedded/run-wrapper.sh all:embedded/shared-template
 var embedded embed.FS
 
 // Asset reads the embedded file and returns it.
 func Asset(file string) ([]byte, error) {
 	return embedded.ReadFile(file)
 }
+
+// AssetDir reads all embedded files in the directory `dir` (recursively)
+// and returns them keyed by their path relative to `dir`.
+func AssetDir(dir string) (files map[string][]byte, err error) {
+	files = make(map[string][]byte)
+
+	err = fs.WalkDir(embedded, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		data, err := embedded.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		files[strings.TrimPrefix(p, dir+"/")] = data
+
+		return nil
+	})
+
+	return
+}
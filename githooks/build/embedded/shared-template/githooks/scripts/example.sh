@@ -0,0 +1,10 @@
+#!/usr/bin/env bash
+# An example hook which lists all staged files.
+
+set -e
+set -u
+
+echo "Staged files:"
+for file in ${STAGED_FILES:-}; do
+    echo " - $file"
+done
@@ -0,0 +1,249 @@
+package install
+
+import (
+	"os"
+	"path"
+	"strings"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
+	"github.com/gabyx/githooks/githooks/hooks"
+	strs "github.com/gabyx/githooks/githooks/strings"
+)
+
+// Modes how detected external hook managers (husky, pre-commit, lefthook)
+// are handled when installing into a repository.
+const (
+	// ExternalHooksChain runs them by Githooks in namespace 'gh-external'.
+	ExternalHooksChain = "chain"
+	// ExternalHooksImport writes runner configs for them into the repository's hooks directory.
+	ExternalHooksImport = "import"
+	// ExternalHooksSkip leaves them untouched.
+	ExternalHooksSkip = "skip"
+)
+
+// GetExternalHooksModes gets all modes for handling external hook managers.
+func GetExternalHooksModes() []string {
+	return []string{ExternalHooksChain, ExternalHooksImport, ExternalHooksSkip}
+}
+
+func getExternalHooksMode(
+	log cm.ILogContext,
+	names []string,
+	mainWorktree string,
+	mode string,
+	nonInteractive bool,
+	uiSettings *UISettings) string {
+
+	if strs.IsNotEmpty(mode) {
+		return mode
+	} else if nonInteractive {
+		return ExternalHooksSkip
+	}
+
+	list := strings.Join(strs.Map(names, func(s string) string {
+		return strs.Fmt(" %s '%s'", cm.ListItemLiteral, s)
+	}), "\n")
+
+	answer, err := uiSettings.PromptCtx.ShowOptions(
+		strs.Fmt("Repository '%s' uses other hook managers:\n%s\n"+
+			"Do you want Githooks to run them (chain), import\n"+
+			"them as Githooks runner configs (import) or skip them?",
+			mainWorktree, list),
+		"(Chain, import, skip)",
+		"C/i/s",
+		"Chain", "Import", "Skip")
+	log.AssertNoErrorF(err, "Could not show prompt.")
+
+	switch answer {
+	case "i":
+		return ExternalHooksImport
+	case "s":
+		return ExternalHooksSkip
+	default:
+		return ExternalHooksChain
+	}
+}
+
+// backupGeneratedHooks moves hooks in `hookDir` which have been generated
+// by the external hook manager `m` (also replaced ones) to a backup file
+// which is not run by Githooks. It gets restored by `UninstallRunWrappers`.
+func backupGeneratedHooks(log cm.ILogContext, m *hooks.ExternalHookManager, hookDir string, dryRun bool) {
+	for _, hookName := range hooks.ManagedHookNames {
+		for _, file := range []string{
+			path.Join(hookDir, hookName),
+			path.Join(hookDir, hooks.GetHookReplacementFileName(hookName))} {
+
+			if !cm.IsFile(file) || !m.IsGeneratedHook(file) {
+				continue
+			}
+
+			backup := path.Join(hookDir, hooks.GetHookExternalBackupFileName(hookName))
+
+			if dryRun {
+				log.InfoF("[dry run] Would move hook '%s' generated by '%s' to '%s'.", file, m.Name, backup)
+
+				continue
+			}
+
+			log.InfoF("Moving hook '%s' generated by '%s' to '%s'.\n"+
+				"It is restored on 'git hooks uninstall'.", file, m.Name, backup)
+			log.AssertNoErrorF(os.Rename(file, backup), "Could not move '%s' to '%s'.", file, backup)
+		}
+	}
+}
+
+// unsetExternalHooksPath saves and unsets the local `core.hooksPath`
+// set by one of the external hook managers `managers`.
+// It gets restored by `UninstallFromRepo`.
+func unsetExternalHooksPath(
+	log cm.ILogContext,
+	gitx *git.Context,
+	managers []hooks.ExternalHookManager,
+	dryRun bool) {
+
+	hooksPath, exists := gitx.LookupConfig(git.GitCKCoreHooksPath, git.LocalScope)
+	if !exists {
+		return
+	}
+
+	for i := range managers {
+		if !managers[i].IsHooksPath(hooksPath) {
+			continue
+		}
+
+		if dryRun {
+			log.InfoF("[dry run] Would unset local '%s' = '%s' of '%s'.",
+				git.GitCKCoreHooksPath, hooksPath, managers[i].Name)
+
+			return
+		}
+
+		log.InfoF("Unsetting local '%s' = '%s' of '%s'.\n"+
+			"The value is restored on 'git hooks uninstall'.",
+			git.GitCKCoreHooksPath, hooksPath, managers[i].Name)
+
+		err := gitx.SetConfig(hooks.GitCKPreviousCoreHooksPath, hooksPath, git.LocalScope)
+		log.AssertNoErrorPanicF(err, "Could not save local '%s'.", git.GitCKCoreHooksPath)
+		err = gitx.UnsetConfig(git.GitCKCoreHooksPath, git.LocalScope)
+		log.AssertNoErrorPanicF(err, "Could not unset local '%s'.", git.GitCKCoreHooksPath)
+
+		return
+	}
+}
+
+// SetupExternalHookManagers detects other hook managers (husky, pre-commit, lefthook)
+// in the repository `repoGitDir` and handles them according to `mode`:
+// Either they get chained (run by Githooks in namespace 'gh-external')
+// or their hooks get imported as runner configs into the repository's hooks directory.
+// If `mode` is empty, the user is asked (or they are skipped if `nonInteractive`).
+// If `unsetHooksPath` is set, their generated hooks in `.git/hooks` are backed up
+// and their local `core.hooksPath` is unset.
+func SetupExternalHookManagers(
+	log cm.ILogContext,
+	repoGitDir string,
+	mode string,
+	unsetHooksPath bool,
+	nonInteractive bool,
+	dryRun bool,
+	uiSettings *UISettings) {
+
+	gitx := git.NewCtxAt(repoGitDir)
+	if gitx.IsBareRepo() {
+		return
+	}
+
+	mainWorktree, err := gitx.GetMainWorktree()
+	if err != nil {
+		return
+	}
+
+	managers := hooks.DetectExternalHookManagers(mainWorktree)
+	if len(managers) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(managers))
+	for i := range managers {
+		names = append(names, managers[i].Name)
+	}
+
+	mode = getExternalHooksMode(log, names, mainWorktree, mode, nonInteractive, uiSettings)
+
+	switch mode {
+	case ExternalHooksChain:
+		if dryRun {
+			log.InfoF("[dry run] Would chain hook managers '%q' in '%s'.", names, mainWorktree)
+		} else {
+			log.AssertNoErrorPanicF(hooks.SetExternalHookManagers(gitx, names),
+				"Could not set Git config '%s'.", hooks.GitCKExternalHookManagers)
+			log.InfoF("Chained hook managers '%q' in namespace '%s'.", names, hooks.NamespaceExternalHook)
+		}
+
+	case ExternalHooksImport:
+		repoHooksDir := hooks.GetGithooksDir(mainWorktree)
+
+		for i := range managers {
+			if dryRun {
+				log.InfoF("[dry run] Would import hooks of '%s' into '%s'.", managers[i].Name, repoHooksDir)
+
+				continue
+			}
+
+			files, err := managers[i].ImportHooks(repoHooksDir)
+			log.AssertNoErrorF(err, "Could not import hooks of '%s'.", managers[i].Name)
+
+			for _, f := range files {
+				log.InfoF("Imported hook of '%s' into '%s'.", managers[i].Name, f)
+			}
+		}
+
+		if !dryRun {
+			// Imported hooks must not run twice.
+			log.AssertNoErrorF(hooks.SetExternalHookManagers(gitx, nil),
+				"Could not unset Git config '%s'.", hooks.GitCKExternalHookManagers)
+		}
+
+	default:
+		log.InfoF("Skipping hook managers '%q' in '%s'.\n"+
+			"To run them with Githooks, use 'git hooks install --external-hooks chain'.",
+			names, mainWorktree)
+
+		hooksPath, exists := gitx.LookupConfig(git.GitCKCoreHooksPath, git.LocalScope)
+		log.WarnIfF(unsetHooksPath && exists && IsExternalHooksPath(repoGitDir, hooksPath),
+			"Local '%s' = '%s' is set by another hook manager.\n"+
+				"Githooks run-wrappers in '%s' have no effect.",
+			git.GitCKCoreHooksPath, hooksPath, repoGitDir)
+
+		return
+	}
+
+	if !unsetHooksPath {
+		// Hooks in `.git/hooks` are not run with a local `core.hooksPath`.
+		return
+	}
+
+	for i := range managers {
+		backupGeneratedHooks(log, &managers[i], path.Join(repoGitDir, "hooks"), dryRun)
+	}
+
+	unsetExternalHooksPath(log, gitx, managers, dryRun)
+}
+
+// IsExternalHooksPath checks if the local `core.hooksPath` value `hooksPath`
+// in the repository `repoGitDir` has been set by an external hook manager.
+func IsExternalHooksPath(repoGitDir string, hooksPath string) bool {
+	mainWorktree, err := git.NewCtxAt(repoGitDir).GetMainWorktree()
+	if err != nil {
+		return false
+	}
+
+	managers := hooks.DetectExternalHookManagers(mainWorktree)
+	for i := range managers {
+		if managers[i].IsHooksPath(hooksPath) {
+			return true
+		}
+	}
+
+	return false
+}
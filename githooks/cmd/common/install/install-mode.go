@@ -11,19 +11,23 @@ type installModeType struct {
 	TemplateDir   InstallModeType
 	CoreHooksPath InstallModeType
 	Manual        InstallModeType
+	RepoHooksPath InstallModeType
 }
 
 // InstallModeTypeV enumerates all types of install modes.
-var InstallModeTypeV = &installModeType{TemplateDir: 0, CoreHooksPath: 1, Manual: 2, None: 3} // nolint:gomnd
+var InstallModeTypeV = &installModeType{TemplateDir: 0, CoreHooksPath: 1, Manual: 2, None: 3, RepoHooksPath: 4} // nolint:gomnd
 
 // GetInstallMode returns the current set install mode of Githooks.
 func GetInstallMode(gitx *git.Context) InstallModeType {
 	useManual := gitx.GetConfig(hooks.GitCKUseManual, git.GlobalScope) == git.GitCVTrue
+	useRepoHooksPath := gitx.GetConfig(hooks.GitCKUseRepoHooksPath, git.GlobalScope) == git.GitCVTrue
 	useCoreHooksPathValue := gitx.GetConfig(hooks.GitCKUseCoreHooksPath, git.GlobalScope)
 
 	switch {
 	case useManual:
 		return InstallModeTypeV.Manual
+	case useRepoHooksPath:
+		return InstallModeTypeV.RepoHooksPath
 	case useCoreHooksPathValue == git.GitCVTrue:
 		return InstallModeTypeV.CoreHooksPath
 	case useCoreHooksPathValue == git.GitCVFalse:
@@ -43,6 +47,8 @@ func GetInstallModeName(installMode InstallModeType) string {
 		return "template-dir"
 	case InstallModeTypeV.CoreHooksPath:
 		return "core-hooks-path"
+	case InstallModeTypeV.RepoHooksPath:
+		return "repo-hooks-path"
 	default:
 		return "none"
 	}
@@ -51,11 +57,14 @@ func GetInstallModeName(installMode InstallModeType) string {
 // MapInstallerArgsToInstallMode maps installer arguments to install modes.
 func MapInstallerArgsToInstallMode(
 	useCoreHooksPath bool,
-	useManual bool) InstallModeType {
+	useManual bool,
+	useRepoHooksPath bool) InstallModeType {
 
 	switch {
 	case useManual:
 		return InstallModeTypeV.Manual
+	case useRepoHooksPath:
+		return InstallModeTypeV.RepoHooksPath
 	case useCoreHooksPath:
 		return InstallModeTypeV.CoreHooksPath
 	default:
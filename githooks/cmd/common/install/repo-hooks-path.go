@@ -0,0 +1,111 @@
+package install
+
+import (
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
+	"github.com/gabyx/githooks/githooks/hooks"
+	strs "github.com/gabyx/githooks/githooks/strings"
+)
+
+// InstallRepoHooksPath sets the local `core.hooksPath` of the repository
+// `gitDir` to the Githooks hooks directory `hooksDir` (install mode 'repo-hooks-path').
+// A previously set local value is saved and restored by `UninstallFromRepo`.
+func InstallRepoHooksPath(
+	log cm.ILogContext,
+	gitDir string,
+	hooksDir string,
+	dryRun bool) bool {
+
+	gitx := git.NewCtxAt(gitDir)
+
+	previous, exists := gitx.LookupConfig(git.GitCKCoreHooksPath, git.LocalScope)
+	if exists && hooks.IsRepoHooksPath(gitx, previous) {
+		log.InfoF("Local '%s' in '%s' is already set to '%s'.",
+			git.GitCKCoreHooksPath, gitDir, previous)
+
+		return true
+	}
+
+	if dryRun {
+		log.InfoF("[dry run] Would set local '%s' in '%s' to '%s'.",
+			git.GitCKCoreHooksPath, gitDir, hooksDir)
+
+		return false
+	}
+
+	if exists {
+		log.WarnF("Local '%s' in '%s' is set to\n'%s'.\n"+
+			"Hooks in this directory are not run by Githooks.\n"+
+			"The value is restored on 'git hooks uninstall'.",
+			git.GitCKCoreHooksPath, gitDir, previous)
+
+		err := gitx.SetConfig(hooks.GitCKPreviousCoreHooksPath, previous, git.LocalScope)
+		log.AssertNoErrorPanicF(err, "Could not save local '%s' in '%s'.", git.GitCKCoreHooksPath, gitDir)
+	}
+
+	err := gitx.SetConfig(git.GitCKCoreHooksPath, hooksDir, git.LocalScope)
+	log.AssertNoErrorPanicF(err, "Could not set local '%s' in '%s'.", git.GitCKCoreHooksPath, gitDir)
+
+	log.InfoF("Set local '%s' in '%s' to '%s'.", git.GitCKCoreHooksPath, gitDir, hooksDir)
+
+	return true
+}
+
+// getRepoHooksPathRestore returns if the local `core.hooksPath` of
+// the repository `gitDir` was set by `InstallRepoHooksPath` or unset by
+// `SetupExternalHookManagers` and the saved previous value to restore
+// (empty if it gets unset).
+func getRepoHooksPathRestore(gitx *git.Context) (isSet bool, previous string) {
+	hooksPath, exists := gitx.LookupConfig(git.GitCKCoreHooksPath, git.LocalScope)
+	previous = gitx.GetConfig(hooks.GitCKPreviousCoreHooksPath, git.LocalScope)
+
+	switch {
+	case exists && hooks.IsRepoHooksPath(gitx, hooksPath):
+		return true, previous
+	case !exists && strs.IsNotEmpty(previous):
+		return true, previous
+	}
+
+	return false, ""
+}
+
+// reportRestoreRepoHooksPath reports what `restoreRepoHooksPath` would do.
+func reportRestoreRepoHooksPath(log cm.ILogContext, gitDir string) {
+	isSet, previous := getRepoHooksPathRestore(git.NewCtxAt(gitDir))
+
+	switch {
+	case !isSet:
+		return
+	case strs.IsEmpty(previous):
+		log.InfoF("[dry run] Would unset local '%s' in '%s'.", git.GitCKCoreHooksPath, gitDir)
+	default:
+		log.InfoF("[dry run] Would restore local '%s' in '%s' to '%s'.",
+			git.GitCKCoreHooksPath, gitDir, previous)
+	}
+}
+
+// restoreRepoHooksPath restores the local `core.hooksPath` of the
+// repository `gitDir` set by `InstallRepoHooksPath`.
+func restoreRepoHooksPath(log cm.ILogContext, gitDir string) {
+	gitx := git.NewCtxAt(gitDir)
+
+	isSet, previous := getRepoHooksPathRestore(gitx)
+	if !isSet {
+		return
+	}
+
+	if strs.IsEmpty(previous) {
+		log.AssertNoErrorF(gitx.UnsetConfig(git.GitCKCoreHooksPath, git.LocalScope),
+			"Could not unset local '%s' in '%s'.", git.GitCKCoreHooksPath, gitDir)
+
+		return
+	}
+
+	log.InfoF("Restoring local '%s' in '%s' to '%s'.", git.GitCKCoreHooksPath, gitDir, previous)
+
+	if log.AssertNoErrorF(gitx.SetConfig(git.GitCKCoreHooksPath, previous, git.LocalScope),
+		"Could not restore local '%s' in '%s'.", git.GitCKCoreHooksPath, gitDir) {
+		log.AssertNoErrorF(gitx.UnsetConfig(hooks.GitCKPreviousCoreHooksPath, git.LocalScope),
+			"Could not unset Git config '%s' in '%s'.", hooks.GitCKPreviousCoreHooksPath, gitDir)
+	}
+}
@@ -45,6 +45,11 @@ func FindHookTemplateDir(gitx *git.Context, installMode InstallModeType) (hooksT
 		hooksTemplateDir, err = CheckTemplateDir(
 			gitx.GetConfig(git.GitCKCoreHooksPath, git.GlobalScope), "")
 
+	case InstallModeTypeV.RepoHooksPath:
+
+		hooksTemplateDir, err = CheckTemplateDir(
+			gitx.GetConfig(hooks.GitCKPathForUseRepoHooksPath, git.GlobalScope), "")
+
 	case InstallModeTypeV.None:
 		fallthrough
 	case InstallModeTypeV.TemplateDir:
@@ -3,6 +3,7 @@ package install
 import (
 	"os"
 	"path"
+	"strings"
 
 	cm "github.com/gabyx/githooks/githooks/common"
 	"github.com/gabyx/githooks/githooks/git"
@@ -13,6 +14,8 @@ import (
 // InstallIntoRepo installs run-wrappers into a repositories
 // It prompts for disabling detected LFS hooks and offers to
 // setup a README file.
+// Detected external hook managers are handled according to `externalHooks`
+// (see `SetupExternalHookManagers`).
 //nolint
 func InstallIntoRepo(
 	log cm.ILogContext,
@@ -23,6 +26,7 @@ func InstallIntoRepo(
 	nonInteractive bool,
 	dryRun bool,
 	skipReadme bool,
+	externalHooks string,
 	uiSettings *UISettings) bool {
 
 	hookDir := path.Join(repoGitDir, "hooks")
@@ -47,6 +51,8 @@ func InstallIntoRepo(
 		lfsHooksCache = nil
 	}
 
+	SetupExternalHookManagers(log, repoGitDir, externalHooks, true, nonInteractive, dryRun, uiSettings)
+
 	if dryRun {
 		log.InfoF("[dry run] Hooks would have been installed into\n'%s'.",
 			repoGitDir)
@@ -68,6 +74,8 @@ func InstallIntoRepo(
 			log.InfoF("Installed '%v' Githooks run-wrapper(s) into '%s'",
 				len(hookNames), hookDir)
 		}
+
+		migrateChecksums(log, repoGitDir)
 	}
 
 	// Offer to setup the intro README if running in interactive mode
@@ -80,26 +88,64 @@ func InstallIntoRepo(
 	return !dryRun
 }
 
-func cleanArtefactsInRepo(log cm.ILogContext, gitDir string) {
+// migrateChecksums migrates all legacy SHA1 checksums of trusted hooks
+// in the repository to SHA256 checksums.
+func migrateChecksums(log cm.ILogContext, gitDir string) {
+	store, err := hooks.GetChecksumStorage(gitDir)
+	if !log.AssertNoErrorF(err, "Could not load checksum store in '%s'.", gitDir) {
+		return
+	}
+
+	migrated, _, err := store.MigrateChecksums()
+	log.AssertNoErrorF(err, "Could not migrate trusted checksums in '%s'.", gitDir)
+
+	if migrated != 0 {
+		log.InfoF("Migrated '%v' trusted SHA1 checksums to SHA256 in '%s'.", migrated, gitDir)
+	}
+}
 
-	// Remove checksum files...
-	cacheDir := hooks.GetChecksumDirectoryGitDir(gitDir)
-	if cm.IsDirectory(cacheDir) {
-		log.AssertNoErrorF(os.RemoveAll(cacheDir),
-			"Could not delete checksum cache dir '%s'.", cacheDir)
+// getArtefactsInRepo gets all existing Githooks files in the Git directory.
+func getArtefactsInRepo(gitDir string, keepTrust bool) (files []string) {
+	artefacts := []string{hooks.GetHookIgnoreFileGitDir(gitDir)}
+	if !keepTrust {
+		artefacts = append(artefacts, hooks.GetChecksumDirectoryGitDir(gitDir))
 	}
 
-	ignoreFile := hooks.GetHookIgnoreFileGitDir(gitDir)
-	if cm.IsDirectory(ignoreFile) {
-		log.AssertNoErrorF(os.RemoveAll(ignoreFile),
-			"Could not delete ignore file '%s'.", ignoreFile)
+	for _, f := range artefacts {
+		if exists, _ := cm.IsPathExisting(f); exists {
+			files = append(files, f)
+		}
 	}
+
+	return
 }
 
-func cleanGitConfigInRepo(log cm.ILogContext, gitDir string) {
+// getGitConfigInRepo gets all Githooks Git config keys set in the Git directory.
+func getGitConfigInRepo(gitx *git.Context, keepTrust bool) (keys []string) {
+	for _, k := range hooks.GetLocalGitConfigKeys() {
+		if keepTrust && strs.Includes(hooks.GetTrustGitConfigKeys(), k) {
+			continue
+		}
+
+		if len(gitx.GetConfigAll(k, git.LocalScope)) != 0 {
+			keys = append(keys, k)
+		}
+	}
+
+	return
+}
+
+func cleanArtefactsInRepo(log cm.ILogContext, gitDir string, keepTrust bool) {
+	for _, f := range getArtefactsInRepo(gitDir, keepTrust) {
+		log.AssertNoErrorF(os.RemoveAll(f),
+			"Could not delete '%s'.", f)
+	}
+}
+
+func cleanGitConfigInRepo(log cm.ILogContext, gitDir string, keepTrust bool) {
 	gitx := git.NewCtxAt(gitDir)
 
-	for _, k := range hooks.GetLocalGitConfigKeys() {
+	for _, k := range getGitConfigInRepo(gitx, keepTrust) {
 
 		log.AssertNoErrorF(gitx.UnsetConfig(k, git.LocalScope),
 			"Could not unset Git config '%s' in '%s'.", k, gitDir)
@@ -114,13 +160,75 @@ func unregisterRepo(log cm.ILogContext, gitDir string) {
 		"Could not unregister Git repo '%s'.", gitDir)
 }
 
+// reportUninstallFromRepo reports all files and Git config keys
+// which `UninstallFromRepo` would remove.
+func reportUninstallFromRepo(
+	log cm.ILogContext,
+	gitDir string,
+	cleanArtefacts bool,
+	keepTrust bool) {
+
+	gitx := git.NewCtxAt(gitDir)
+
+	files, err := hooks.GetRunWrappers(path.Join(gitDir, "hooks"))
+	log.AssertNoErrorF(err, "Could not get Githooks run-wrappers in '%s'.", gitDir)
+
+	var keys []string
+	if cleanArtefacts {
+		files = append(files, getArtefactsInRepo(gitDir, keepTrust)...)
+		keys = getGitConfigInRepo(gitx, keepTrust)
+	} else if gitx.IsConfigSet(hooks.GitCKRegistered, git.LocalScope) {
+		keys = []string{hooks.GitCKRegistered}
+	}
+
+	ReportDryRunRemovals(log, strs.Fmt("Githooks in '%s'", gitDir), files, keys)
+	reportRestoreRepoHooksPath(log, gitDir)
+}
+
+// ReportDryRunRemovals reports the files and Git config keys which
+// would be removed by uninstalling `what`.
+func ReportDryRunRemovals(log cm.ILogContext, what string, files []string, keys []string) {
+	if len(files) == 0 && len(keys) == 0 {
+		log.InfoF("[dry run] Would uninstall %s: nothing to remove.", what)
+
+		return
+	}
+
+	list := func(items []string) string {
+		return strings.Join(strs.Map(items, func(s string) string {
+			return strs.Fmt(" %s '%s'", cm.ListItemLiteral, s)
+		}), "\n")
+	}
+
+	msg := strs.Fmt("[dry run] Would uninstall %s and remove:", what)
+	if len(files) != 0 {
+		msg += "\n" + list(files)
+	}
+	if len(keys) != 0 {
+		msg += "\nGit config:\n" + list(keys)
+	}
+
+	log.Info(msg)
+}
+
 // UninstallFromRepo uninstalls run-wrappers from the repositories Git directory.
 // LFS hooks will be reinstalled if available.
+// A local `core.hooksPath` set by `InstallRepoHooksPath` gets restored.
+// The trusted checksums and trust settings are kept if `keepTrust` is set.
+// With `dryRun` nothing is changed and only reported.
 func UninstallFromRepo(
 	log cm.ILogContext,
 	gitDir string,
 	lfsHooksCache hooks.LFSHooksCache,
-	cleanArtefacts bool) bool {
+	cleanArtefacts bool,
+	keepTrust bool,
+	dryRun bool) bool {
+
+	if dryRun {
+		reportUninstallFromRepo(log, gitDir, cleanArtefacts, keepTrust)
+
+		return true
+	}
 
 	hookDir := path.Join(gitDir, "hooks")
 
@@ -136,12 +244,13 @@ func UninstallFromRepo(
 			hookDir)
 	}
 
-	// Always unregister repo.
+	// Always restore a local `core.hooksPath` and unregister repo.
+	restoreRepoHooksPath(log, gitDir)
 	unregisterRepo(log, gitDir)
 
 	if cleanArtefacts {
-		cleanArtefactsInRepo(log, gitDir)
-		cleanGitConfigInRepo(log, gitDir)
+		cleanArtefactsInRepo(log, gitDir, keepTrust)
+		cleanGitConfigInRepo(log, gitDir, keepTrust)
 	}
 
 	if nLfsCount != 0 {
@@ -11,6 +11,7 @@ import (
 	"github.com/gabyx/githooks/githooks/hooks"
 	strs "github.com/gabyx/githooks/githooks/strings"
 	"github.com/gabyx/githooks/githooks/updates"
+	"github.com/gabyx/githooks/githooks/updates/download"
 
 	"github.com/pkg/math"
 	"github.com/spf13/cobra"
@@ -297,6 +298,42 @@ func runSharedRepos(ctx *ccm.CmdContext, opts *SetOptions, gitOpts *GitOptions)
 	}
 }
 
+func runSharedVerifySignature(ctx *ccm.CmdContext, opts *SetOptions, gitOpts *GitOptions) {
+	localOrGlobal := "locally" // nolint: goconst
+	if gitOpts.Global {
+		localOrGlobal = "globally" // nolint: goconst
+	}
+
+	const text = "Signature verification of shared repositories"
+	scope := wrapToGitScope(ctx.Log, gitOpts)
+
+	switch {
+	case opts.Set:
+		verify := hooks.SharedRepoVerify{Signature: opts.Values[0], AllowedKeys: opts.Values[1:]}
+		err := hooks.SetSharedRepoVerifyConfig(ctx.GitX, &verify, scope)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not set %s %s.", strings.ToLower(text), localOrGlobal)
+		ctx.Log.InfoF("%s is now required %s\n[signature: '%s', allowed keys: '%q'].",
+			text, localOrGlobal, verify.Signature, verify.AllowedKeys)
+
+	case opts.Reset:
+		err := hooks.SetSharedRepoVerifyConfig(ctx.GitX, nil, scope)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not reset %s %s.", strings.ToLower(text), localOrGlobal)
+		ctx.Log.InfoF("%s is now not required %s.", text, localOrGlobal)
+
+	case opts.Print:
+		verify := hooks.GetSharedRepoVerifyConfig(ctx.GitX, scope)
+		if verify == nil {
+			ctx.Log.InfoF("%s is not required %s.", text, localOrGlobal)
+		} else {
+			ctx.Log.InfoF("%s is required %s\n[signature: '%s', allowed keys: '%q'].",
+				text, localOrGlobal, verify.Signature, verify.AllowedKeys)
+		}
+
+	default:
+		cm.Panic("Wrong arguments.")
+	}
+}
+
 func runCloneURL(ctx *ccm.CmdContext, opts *SetOptions) {
 	switch {
 	case opts.Set:
@@ -341,6 +378,37 @@ func runCloneBranch(ctx *ccm.CmdContext, opts *SetOptions) {
 	}
 }
 
+func runUpdateChannel(ctx *ccm.CmdContext, opts *SetOptions) {
+	deploySettingsFile := download.GetDeploySettingsFile(ctx.InstallDir)
+
+	switch {
+	case opts.Set:
+		_, err := updates.LoadUpdateChannel(ctx.GitX, deploySettingsFile, opts.Values[0])
+		ctx.Log.AssertNoErrorPanicF(err, "Could not set update channel '%s'.", opts.Values[0])
+
+		err = updates.SetUpdateChannelName(opts.Values[0])
+		ctx.Log.AssertNoErrorPanic(err, "Could not set Githooks update channel.")
+		ctx.Log.InfoF("Set Githooks update channel to '%s'.", opts.Values[0])
+
+	case opts.Reset:
+		err := updates.ResetUpdateChannelName()
+		ctx.Log.AssertNoErrorPanic(err, "Could not unset Githooks update channel.")
+		ctx.Log.Info("Unset Githooks update channel.")
+
+	case opts.Print:
+		channel, err := updates.LoadUpdateChannel(ctx.GitX, deploySettingsFile, "")
+		ctx.Log.AssertNoErrorPanic(err, "Could not load Githooks update channel.")
+
+		if channel != nil {
+			ctx.Log.InfoF("Githooks update channel is set to '%s'.", channel.Name)
+		} else {
+			ctx.Log.Info("Githooks update channel is not set.")
+		}
+	default:
+		cm.Panic("Wrong arguments.")
+	}
+}
+
 func runUpdateTime(ctx *ccm.CmdContext, opts *SetOptions) {
 	const text = "Githooks update check timestamp"
 
@@ -479,6 +547,43 @@ func runRunnerNonInteractive(ctx *ccm.CmdContext, opts *SetOptions, gitOpts *Git
 	}
 }
 
+func runOffline(ctx *ccm.CmdContext, opts *SetOptions, gitOpts *GitOptions) {
+	scope := wrapToGitScope(ctx.Log, gitOpts)
+
+	localOrGlobal := "locally" //nolint: goconst
+	if gitOpts.Global {
+		localOrGlobal = "globally" //nolint: goconst
+	}
+
+	const text = "offline mode"
+	switch {
+	case opts.Set:
+		err := hooks.SetOfflineMode(ctx.GitX, true, false, scope)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not enable %s %s.", text, localOrGlobal)
+		ctx.Log.InfoF("Enabled %s %s.", text, localOrGlobal)
+
+	case opts.Unset:
+		err := hooks.SetOfflineMode(ctx.GitX, false, false, scope)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not disable %s %s.", text, localOrGlobal)
+		ctx.Log.InfoF("Disabled %s %s.", text, localOrGlobal)
+
+	case opts.Reset:
+		err := hooks.SetOfflineMode(ctx.GitX, false, true, scope)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not reset %s %s.", text, localOrGlobal)
+		ctx.Log.InfoF("Reset %s %s.", text, localOrGlobal)
+
+	case opts.Print:
+		if hooks.IsOfflineMode(ctx.GitX, scope, false) {
+			ctx.Log.InfoF("Offline mode is enabled %s.", localOrGlobal)
+		} else {
+			ctx.Log.InfoF("Offline mode is disabled %s.", localOrGlobal)
+		}
+
+	default:
+		cm.Panic("Wrong arguments.")
+	}
+}
+
 func runSkipNonExistingSharedHooks(ctx *ccm.CmdContext, opts *SetOptions, gitOpts *GitOptions) {
 	scope := wrapToGitScope(ctx.Log, gitOpts)
 
@@ -612,6 +717,82 @@ func runSkipUntrustedHooks(ctx *ccm.CmdContext, opts *SetOptions, gitOpts *GitOp
 	}
 }
 
+func runTrustSharedRevisions(ctx *ccm.CmdContext, opts *SetOptions, gitOpts *GitOptions) {
+	scope := wrapToGitScope(ctx.Log, gitOpts)
+
+	localOrGlobal := "locally" //nolint: goconst
+	if gitOpts.Global {
+		localOrGlobal = "globally" //nolint: goconst
+	}
+
+	const text = "trusting shared hooks per revision"
+	switch {
+	case opts.Set:
+		err := hooks.SetTrustSharedRevisions(ctx.GitX, true, false, scope)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not enable %s %s.", text, localOrGlobal)
+		ctx.Log.InfoF("Enabled %s %s.", text, localOrGlobal)
+
+	case opts.Unset:
+		err := hooks.SetTrustSharedRevisions(ctx.GitX, false, false, scope)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not disable %s %s.", text, localOrGlobal)
+		ctx.Log.InfoF("Disabled %s %s.", text, localOrGlobal)
+
+	case opts.Reset:
+		err := hooks.SetTrustSharedRevisions(ctx.GitX, false, true, scope)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not reset %s %s.", text, localOrGlobal)
+		ctx.Log.InfoF("Reset %s %s.", text, localOrGlobal)
+
+	case opts.Print:
+		if hooks.IsTrustSharedRevisions(ctx.GitX, scope) {
+			ctx.Log.InfoF("Trusting shared hooks per revision is enabled %s.", localOrGlobal)
+		} else {
+			ctx.Log.InfoF("Trusting shared hooks per revision is disabled %s.", localOrGlobal)
+		}
+
+	default:
+		cm.Panic("Wrong arguments.")
+	}
+}
+
+func runTrustExpiry(ctx *ccm.CmdContext, opts *SetOptions, gitOpts *GitOptions) {
+	scope := wrapToGitScope(ctx.Log, gitOpts)
+
+	localOrGlobal := "locally" //nolint: goconst
+	if gitOpts.Global {
+		localOrGlobal = "globally" //nolint: goconst
+	}
+
+	const text = "trust expiry"
+	switch {
+	case opts.Set:
+		expiry, err := time.ParseDuration(opts.Values[0])
+		ctx.Log.AssertNoErrorPanicF(err, "Could not parse duration '%s'.", opts.Values[0])
+		ctx.Log.PanicIfF(expiry <= 0, "The %s must be positive.", text)
+
+		err = hooks.SetTrustExpiry(ctx.GitX, expiry, false, scope)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not set %s %s.", text, localOrGlobal)
+		ctx.Log.InfoF("Trusted hooks expire after '%v' %s.", expiry, localOrGlobal)
+
+	case opts.Reset:
+		err := hooks.SetTrustExpiry(ctx.GitX, 0, true, scope)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not reset %s %s.", text, localOrGlobal)
+		ctx.Log.InfoF("Reset %s %s.", text, localOrGlobal)
+
+	case opts.Print:
+		expiry, err := hooks.GetTrustExpiry(ctx.GitX, scope)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not get %s %s.", text, localOrGlobal)
+
+		if expiry > 0 {
+			ctx.Log.InfoF("Trusted hooks expire after '%v' %s.", expiry, localOrGlobal)
+		} else {
+			ctx.Log.InfoF("Trusted hooks do not expire %s.", localOrGlobal)
+		}
+
+	default:
+		cm.Panic("Wrong arguments.")
+	}
+}
+
 func runDeleteDetectedLFSHooks(ctx *ccm.CmdContext, opts *SetOptions) {
 	opt := hooks.GitCKDeleteDetectedLFSHooksAnswer
 
@@ -742,6 +923,26 @@ used during installation.`,
 	configCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, searchDirCmd))
 }
 
+func configUpdateChannelCmd(ctx *ccm.CmdContext, configCmd *cobra.Command, setOpts *SetOptions) {
+
+	updateChannelCmd := &cobra.Command{
+		Use:   "update-channel [flags] [<name>]",
+		Short: "Changes the Githooks update channel used for any update.",
+		Long: `Changes the Githooks update channel used for any update.
+
+The channels 'stable' and 'beta' (including pre-release versions)
+are always available. Further channels and the default channel are
+defined in the deploy settings '<installDir>/deploy.yaml'.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			runUpdateChannel(ctx, setOpts)
+		}}
+
+	optsPSR := createOptionMap(true, false, true)
+
+	configSetOptions(updateChannelCmd, setOpts, &optsPSR, ctx.Log, 1, 1)
+	configCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, updateChannelCmd))
+}
+
 func configCloneURLCmd(ctx *ccm.CmdContext, configCmd *cobra.Command, setOpts *SetOptions) {
 
 	cloneURLCmd := &cobra.Command{
@@ -871,6 +1072,49 @@ each containing a clone URL of a shared hook repository which gets added.`,
 	configCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, sharedCmd))
 }
 
+func configSharedVerifySignatureCmd(
+	ctx *ccm.CmdContext,
+	configCmd *cobra.Command,
+	setOpts *SetOptions,
+	gitOpts *GitOptions) {
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify-shared-signature [flags] [<type> <key>...]",
+		Short: "Require signed revisions in shared hook repositories.",
+		Long: strs.Fmt(`Require that the checked out revision of all shared hook repositories
+is signed by one of the allowed keys '<key>'.
+The signature '<type>' is either '%[1]s' (the checked out commit is signed)
+or '%[2]s' (a tag pointing to the checked out commit is signed).
+Keys are GPG key ids or fingerprints or SSH key fingerprints or principals.
+Shared hooks from unverified revisions are refused to run.
+
+Settings for individual shared repositories in '%[3]s' take precedence.`,
+			hooks.SharedSignatureCommit, hooks.SharedSignatureTag, hooks.GetRepoSharedFileRel()),
+		Run: func(cmd *cobra.Command, args []string) {
+			if !gitOpts.Local && !gitOpts.Global {
+				gitOpts.Local = true
+			}
+
+			if gitOpts.Local {
+				ccm.AssertRepoRoot(ctx)
+			}
+
+			runSharedVerifySignature(ctx, setOpts, gitOpts)
+		}}
+
+	optsPSR := createOptionMap(true, false, true)
+	optsPSR.SetDesc = "Require signature '<type>' by any of the keys '<key>'."
+
+	configSetOptions(verifyCmd, setOpts, &optsPSR, ctx.Log, 2, -1) // nolint: gomnd
+
+	verifyCmd.Flags().BoolVar(&gitOpts.Local, "local", false,
+		"Use the local Git configuration (default).")
+	verifyCmd.Flags().BoolVar(&gitOpts.Global,
+		"global", false, "Use the global Git configuration.")
+
+	configCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, verifyCmd))
+}
+
 func configSkipNonExistingSharedHooks(
 	ctx *ccm.CmdContext,
 	configCmd *cobra.Command,
@@ -989,6 +1233,90 @@ Mostly wanted if all hooks must be executed.`,
 	configCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, nonExistSharedCmd))
 }
 
+func configTrustSharedRevisions(
+	ctx *ccm.CmdContext,
+	configCmd *cobra.Command,
+	setOpts *SetOptions,
+	gitOpts *GitOptions) {
+
+	trustRevisionsCmd := &cobra.Command{
+		Use:   "trust-shared-revisions [flags]",
+		Short: "Enable/disable trusting shared hooks per revision.",
+		Long: `Enable or disable trusting shared hooks per revision.
+
+If enabled, the trust prompt for new or changed hooks in a shared
+repository is shown once for the checked out commit instead of for each hook.
+Accepting it trusts all hooks of the shared repository at this commit.
+A revision of a shared repository with local modifications is never trusted.
+See also 'git hooks trust shared'.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if !gitOpts.Local && !gitOpts.Global {
+				gitOpts.Local = true
+			}
+
+			if gitOpts.Local {
+				ccm.AssertRepoRoot(ctx)
+			}
+
+			runTrustSharedRevisions(ctx, setOpts, gitOpts)
+		}}
+
+	optsPSUR := createOptionMap(true, true, true)
+	wrapToEnableDisable(&optsPSUR)
+	optsPSUR.SetDesc = "Enable trusting shared hooks per revision."
+	optsPSUR.UnsetDesc = "Disable trusting shared hooks per revision."
+	optsPSUR.ResetDesc = "Reset trusting shared hooks per revision."
+
+	configSetOptions(trustRevisionsCmd, setOpts, &optsPSUR, ctx.Log, 0, 0)
+
+	trustRevisionsCmd.Flags().BoolVar(&gitOpts.Local, "local", false,
+		"Use the local Git configuration (default).")
+	trustRevisionsCmd.Flags().BoolVar(&gitOpts.Global,
+		"global", false, "Use the global Git configuration.")
+
+	configCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, trustRevisionsCmd))
+}
+
+func configTrustExpiry(
+	ctx *ccm.CmdContext,
+	configCmd *cobra.Command,
+	setOpts *SetOptions,
+	gitOpts *GitOptions) {
+
+	trustExpiryCmd := &cobra.Command{
+		Use:   "trust-expiry [flags] [<duration>]",
+		Short: "Set the duration after which trusted hooks expire.",
+		Long: `Set the duration (e.g. '720h') after which trusted hooks expire.
+
+Once the trust of a hook has expired, the trust prompt is shown again.
+The organization-wide trust policy can enforce a shorter duration.
+See also 'git hooks trust hooks --expire'.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if !gitOpts.Local && !gitOpts.Global {
+				gitOpts.Local = true
+			}
+
+			if gitOpts.Local {
+				ccm.AssertRepoRoot(ctx)
+			}
+
+			runTrustExpiry(ctx, setOpts, gitOpts)
+		}}
+
+	optsPSR := createOptionMap(true, false, true)
+	optsPSR.SetDesc = "Set the duration after which trusted hooks expire."
+	optsPSR.ResetDesc = "Reset the duration, trusted hooks do not expire."
+
+	configSetOptions(trustExpiryCmd, setOpts, &optsPSR, ctx.Log, 1, 1)
+
+	trustExpiryCmd.Flags().BoolVar(&gitOpts.Local, "local", false,
+		"Use the local Git configuration (default).")
+	trustExpiryCmd.Flags().BoolVar(&gitOpts.Global,
+		"global", false, "Use the global Git configuration.")
+
+	configCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, trustExpiryCmd))
+}
+
 func configNonInteractiveRunner(
 	ctx *ccm.CmdContext,
 	configCmd *cobra.Command,
@@ -1028,6 +1356,49 @@ See 'git hooks config trust-all --help'.`,
 	configCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, nonInteracticeRunner))
 }
 
+func configOffline(
+	ctx *ccm.CmdContext,
+	configCmd *cobra.Command,
+	setOpts *SetOptions,
+	gitOpts *GitOptions) {
+
+	offlineCmd := &cobra.Command{
+		Use:   "offline [flags]",
+		Short: "Enables/disables the offline mode.",
+		Long: `Enable or disable the offline mode.
+
+In offline mode all network operations are skipped, namely
+shared hooks updates, container image pulls and Githooks update checks.
+The offline mode can also be enabled with the environment variable
+'GITHOOKS_OFFLINE=1'.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if !gitOpts.Local && !gitOpts.Global {
+				gitOpts.Local = true
+			}
+
+			if gitOpts.Local {
+				ccm.AssertRepoRoot(ctx)
+			}
+
+			runOffline(ctx, setOpts, gitOpts)
+		}}
+
+	optsPSUR := createOptionMap(true, true, true)
+	wrapToEnableDisable(&optsPSUR)
+	optsPSUR.SetDesc = "Enables the offline mode."
+	optsPSUR.UnsetDesc = "Disables the offline mode."
+	optsPSUR.ResetDesc = "Reset the offline mode."
+
+	configSetOptions(offlineCmd, setOpts, &optsPSUR, ctx.Log, 0, 0)
+
+	offlineCmd.Flags().BoolVar(&gitOpts.Local, "local", false,
+		"Use the local Git configuration (default).")
+	offlineCmd.Flags().BoolVar(&gitOpts.Global, "global", false,
+		"Use the global Git configuration.")
+
+	configCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, offlineCmd))
+}
+
 func configDetectedLFSCmd(ctx *ccm.CmdContext, configCmd *cobra.Command, setOpts *SetOptions, gitOpts *GitOptions) {
 
 	deleteDetectedLFSCmd := &cobra.Command{
@@ -1072,6 +1443,7 @@ func NewCmd(ctx *ccm.CmdContext) *cobra.Command {
 	configSearchDirCmd(ctx, configCmd, &setOpts)
 	configUpdateCmd(ctx, configCmd, &setOpts)
 	configUpdateTimeCmd(ctx, configCmd, &setOpts)
+	configUpdateChannelCmd(ctx, configCmd, &setOpts)
 	configCloneURLCmd(ctx, configCmd, &setOpts)
 	configCloneBranchCmd(ctx, configCmd, &setOpts)
 
@@ -1079,11 +1451,15 @@ func NewCmd(ctx *ccm.CmdContext) *cobra.Command {
 
 	configSharedCmd(ctx, configCmd, &setOpts, &gitOpts)
 	configDisableSharedHooksUpdate(ctx, configCmd, &setOpts, &gitOpts)
+	configSharedVerifySignatureCmd(ctx, configCmd, &setOpts, &gitOpts)
 
 	configSkipNonExistingSharedHooks(ctx, configCmd, &setOpts, &gitOpts)
 	configFailUntrustedHooks(ctx, configCmd, &setOpts, &gitOpts)
+	configTrustSharedRevisions(ctx, configCmd, &setOpts, &gitOpts)
+	configTrustExpiry(ctx, configCmd, &setOpts, &gitOpts)
 
 	configNonInteractiveRunner(ctx, configCmd, &setOpts, &gitOpts)
+	configOffline(ctx, configCmd, &setOpts, &gitOpts)
 
 	configDetectedLFSCmd(ctx, configCmd, &setOpts, &gitOpts)
 
@@ -0,0 +1,460 @@
+package doctor
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	ccm "github.com/gabyx/githooks/githooks/cmd/common"
+	"github.com/gabyx/githooks/githooks/cmd/common/install"
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/container"
+	"github.com/gabyx/githooks/githooks/git"
+	"github.com/gabyx/githooks/githooks/hooks"
+	strs "github.com/gabyx/githooks/githooks/strings"
+
+	"github.com/spf13/cobra"
+)
+
+// finding is a problem found by a health check.
+type finding struct {
+	Problem    string       // The description of the problem.
+	Suggestion string       // The suggested fix.
+	Fix        func() error // The fix if it is safe to apply automatically, otherwise `nil`.
+}
+
+// repository is the repository the health checks run in.
+type repository struct {
+	RepoDir string
+	GitDir  string
+}
+
+// check is a health check.
+type check struct {
+	Name string
+	Run  func(ctx *ccm.CmdContext, repo *repository) []finding
+}
+
+var checks = []check{
+	{"Install directory", checkInstallDir},
+	{"Run-wrappers", checkRunWrappers},
+	{"Git config 'core.hooksPath'", checkCoreHooksPath},
+	{"Registered repositories", checkRegisteredRepos},
+	{"Shared hook repositories", checkSharedRepos},
+	{"Container manager", checkContainerManager},
+	{"LFS hooks", checkLFSHooks},
+	{"Legacy Git config", checkLegacyGitConfig},
+}
+
+func checkInstallDir(ctx *ccm.CmdContext, repo *repository) (findings []finding) {
+	installDir := hooks.GetInstallDir(ctx.GitX)
+
+	if strs.IsEmpty(installDir) {
+		return []finding{{
+			Problem:    strs.Fmt("Git config '%s' is not set.", hooks.GitCKInstallDir),
+			Suggestion: "Install Githooks with 'git hooks installer'."}}
+	} else if !cm.IsDirectory(installDir) {
+		return []finding{{
+			Problem:    strs.Fmt("Install directory '%s' does not exist.", installDir),
+			Suggestion: "Reinstall Githooks with 'git hooks installer'."}}
+	}
+
+	cli := hooks.GetInstallerExecutable(installDir)
+	for _, exe := range []string{cli.Cmd, hooks.GetRunnerExecutable(installDir)} {
+		if !cm.IsFile(exe) {
+			findings = append(findings, finding{
+				Problem:    strs.Fmt("Executable '%s' does not exist.", exe),
+				Suggestion: "Reinstall Githooks with 'git hooks installer'."})
+		}
+	}
+
+	return
+}
+
+func checkRunWrappers(ctx *ccm.CmdContext, repo *repository) (findings []finding) {
+	var dirs []string
+
+	installMode := install.GetInstallMode(ctx.GitX)
+	if installMode != install.InstallModeTypeV.None {
+		templateDir, err := install.FindHookTemplateDir(ctx.GitX, installMode)
+		if err != nil || strs.IsEmpty(templateDir) {
+			findings = append(findings, finding{
+				Problem: strs.Fmt("Hook template directory for install mode '%s' not found.",
+					install.GetInstallModeName(installMode)),
+				Suggestion: "Reinstall Githooks with 'git hooks installer'."})
+		} else {
+			dirs = append(dirs, templateDir)
+		}
+	}
+
+	if repo != nil {
+		dirs = append(dirs, path.Join(repo.GitDir, "hooks"))
+	}
+
+	for _, dir := range dirs {
+		files, err := hooks.GetRunWrappers(dir)
+		ctx.Log.AssertNoErrorF(err, "Could not get run-wrappers in '%s'.", dir)
+
+		for _, file := range files {
+			file := file
+
+			upToDate, err := hooks.IsRunWrapperUpToDate(file)
+			if err == nil && upToDate {
+				continue
+			}
+
+			findings = append(findings, finding{
+				Problem:    strs.Fmt("Run-wrapper '%s' is outdated.", file),
+				Suggestion: "Reinstall the run-wrapper.",
+				Fix:        func() error { return hooks.WriteRunWrapper(file) }})
+		}
+	}
+
+	return
+}
+
+func checkCoreHooksPath(ctx *ccm.CmdContext, repo *repository) (findings []finding) {
+	gitx := ctx.GitX
+
+	if install.GetInstallMode(gitx) == install.InstallModeTypeV.CoreHooksPath {
+		expected := gitx.GetConfig(hooks.GitCKPathForUseCoreHooksPath, git.GlobalScope)
+		coreHooksPath := gitx.GetConfig(git.GitCKCoreHooksPath, git.GlobalScope)
+
+		if strs.IsEmpty(expected) {
+			return []finding{{
+				Problem: strs.Fmt("Githooks uses 'core.hooksPath' but Git config '%s' is not set.",
+					hooks.GitCKPathForUseCoreHooksPath),
+				Suggestion: "Reinstall Githooks with 'git hooks installer --use-core-hookspath'."}}
+		}
+
+		if coreHooksPath != expected {
+			findings = append(findings, finding{
+				Problem: strs.Fmt("Global Git config 'core.hooksPath' is '%s' but should be '%s'.",
+					coreHooksPath, expected),
+				Suggestion: strs.Fmt("Set it with 'git config --global core.hooksPath \"%s\"'.", expected),
+				Fix: func() error {
+					return gitx.SetConfig(git.GitCKCoreHooksPath, expected, git.GlobalScope)
+				}})
+		}
+
+		if repo != nil {
+			if local, set := gitx.LookupConfig(git.GitCKCoreHooksPath, git.LocalScope); set && local != expected {
+				findings = append(findings, finding{
+					Problem: strs.Fmt("Local Git config 'core.hooksPath' is '%s' and overrides the global one.",
+						local),
+					Suggestion: "Unset it with 'git config --local --unset core.hooksPath'\n" +
+						"if the repository should run Githooks."})
+			}
+		}
+
+		return
+	}
+
+	if install.GetInstallMode(gitx) == install.InstallModeTypeV.RepoHooksPath &&
+		repo != nil && gitx.IsConfigSet(hooks.GitCKRegistered, git.LocalScope) {
+		expected := gitx.GetConfig(hooks.GitCKPathForUseRepoHooksPath, git.GlobalScope)
+		local := gitx.GetConfig(git.GitCKCoreHooksPath, git.LocalScope)
+
+		if strs.IsNotEmpty(expected) && !hooks.IsRepoHooksPath(gitx, local) {
+			findings = append(findings, finding{
+				Problem: strs.Fmt("Local Git config 'core.hooksPath' is '%s' but should be '%s'.",
+					local, expected),
+				Suggestion: "Run 'git hooks install' in the repository.",
+				Fix: func() error {
+					return gitx.SetConfig(git.GitCKCoreHooksPath, expected, git.LocalScope)
+				}})
+
+			return
+		}
+	}
+
+	if err := hooks.CheckGithooksSetup(gitx); err != nil {
+		findings = append(findings, finding{
+			Problem:    err.Error(),
+			Suggestion: "Unset 'core.hooksPath' or reinstall Githooks with 'git hooks installer'."})
+	}
+
+	return
+}
+
+func checkRegisteredRepos(ctx *ccm.CmdContext, repo *repository) (findings []finding) {
+	installDir := ctx.InstallDir
+
+	var registered hooks.RegisterRepos
+	err := registered.Load(installDir, false, false)
+	if err != nil {
+		return []finding{{
+			Problem:    strs.Fmt("Register file '%s' cannot be loaded.", hooks.GetRegisterFile(installDir)),
+			Suggestion: "Delete the file. Repositories register again when running hooks."}}
+	}
+
+	var nonExisting []string
+	for _, gitDir := range registered.GitDirs {
+		if !cm.IsDirectory(gitDir) {
+			nonExisting = append(nonExisting, gitDir)
+		}
+	}
+
+	if len(nonExisting) != 0 {
+		findings = append(findings, finding{
+			Problem: strs.Fmt("Registered repositories do not exist anymore:\n%s",
+				formatList(nonExisting)),
+			Suggestion: "Remove them from the register file.",
+			Fix: func() error {
+				var repos hooks.RegisterRepos
+				if e := repos.Load(installDir, true, false); e != nil {
+					return e
+				}
+
+				return repos.Store(installDir)
+			}})
+	}
+
+	if repo == nil || ctx.GitX.IsConfigSet(git.GitCKCoreHooksPath, git.Traverse) {
+		return
+	}
+
+	wrappers, _ := hooks.GetRunWrappers(path.Join(repo.GitDir, "hooks"))
+	if len(wrappers) == 0 {
+		return
+	}
+
+	if !strs.Includes(registered.GitDirs, repo.GitDir) ||
+		!ctx.GitX.IsConfigSet(hooks.GitCKRegistered, git.LocalScope) {
+		findings = append(findings, finding{
+			Problem:    strs.Fmt("Repository '%s' has run-wrappers but is not registered.", repo.RepoDir),
+			Suggestion: "Register the repository such that it receives run-wrapper updates.",
+			Fix: func() error {
+				return cm.CombineErrors(
+					hooks.RegisterRepo(repo.GitDir, installDir, true, false),
+					hooks.MarkRepoRegistered(ctx.GitX))
+			}})
+	}
+
+	return
+}
+
+func checkSharedRepos(ctx *ccm.CmdContext, repo *repository) (findings []finding) {
+	var all []hooks.SharedRepo
+
+	if repo != nil {
+		shared, err := hooks.LoadRepoSharedHooks(ctx.InstallDir, repo.RepoDir)
+		if err != nil {
+			findings = append(findings, finding{
+				Problem:    strs.Fmt("Shared hooks file '%s' cannot be loaded.", hooks.GetRepoSharedFileRel()),
+				Suggestion: "Fix the file."})
+		}
+		all = append(all, shared...)
+
+		shared, err = hooks.LoadConfigSharedHooks(ctx.InstallDir, ctx.GitX, git.LocalScope)
+		ctx.Log.AssertNoErrorF(err, "Could not load local shared hooks.")
+		all = append(all, shared...)
+	}
+
+	shared, err := hooks.LoadConfigSharedHooks(ctx.InstallDir, ctx.GitX, git.GlobalScope)
+	ctx.Log.AssertNoErrorF(err, "Could not load global shared hooks.")
+	all = append(all, shared...)
+
+	for i := range all {
+		s := &all[i]
+
+		switch {
+		case !s.IsCloned:
+			if !cm.IsDirectory(s.RepositoryDir) {
+				findings = append(findings, finding{
+					Problem:    strs.Fmt("Local shared repository '%s' does not exist.", s.OriginalURL),
+					Suggestion: "Remove it with 'git hooks shared remove'."})
+			}
+		case !cm.IsDirectory(s.RepositoryDir):
+			findings = append(findings, finding{
+				Problem:    strs.Fmt("Shared repository '%s' is not cloned yet.", s.OriginalURL),
+				Suggestion: "Clone it with 'git hooks shared update'."})
+		case !s.IsCloneValid():
+			dir := s.RepositoryDir
+			findings = append(findings, finding{
+				Problem: strs.Fmt("Clone '%s' of shared repository '%s'\nhas a wrong remote url.",
+					dir, s.OriginalURL),
+				Suggestion: "Delete the clone and clone it again with 'git hooks shared update'.",
+				Fix:        func() error { return os.RemoveAll(dir) }})
+		}
+	}
+
+	return
+}
+
+func checkContainerManager(ctx *ccm.CmdContext, repo *repository) []finding {
+	if !hooks.IsContainerizedHooksEnabled(ctx.GitX, true) {
+		return nil
+	}
+
+	_, err := container.NewManager(ctx.GitX.GetConfig(hooks.GitCKContainerManager, git.Traverse))
+	if err != nil {
+		return []finding{{
+			Problem: strs.Fmt("Containerized hooks are enabled but the container manager\n"+
+				"is not available: %s", err.Error()),
+			Suggestion: "Install the container manager or disable containerized hooks with\n" +
+				"'git hooks config enable-containerized-hooks --reset'."}}
+	}
+
+	return nil
+}
+
+var lfsAttributeRe = regexp.MustCompile(`filter=lfs`)
+
+func checkLFSHooks(ctx *ccm.CmdContext, repo *repository) (findings []finding) {
+	if repo == nil || ctx.GitX.IsConfigSet(git.GitCKCoreHooksPath, git.Traverse) {
+		return
+	}
+
+	_, lfsRequired := hooks.GetLFSRequiredFile(repo.RepoDir)
+	usesLFS, _ := cm.MatchLineRegexInFile(path.Join(repo.RepoDir, ".gitattributes"), lfsAttributeRe)
+
+	if !lfsRequired && !usesLFS {
+		return
+	}
+
+	if !git.IsLFSAvailable() {
+		return []finding{{
+			Problem:    strs.Fmt("Repository '%s' uses Git LFS but 'git-lfs' is not available.", repo.RepoDir),
+			Suggestion: "Install Git LFS."}}
+	}
+
+	var missing []string
+	for _, hookName := range hooks.LFSHookNames {
+		file := path.Join(repo.GitDir, "hooks", hookName)
+
+		if !cm.IsFile(file) {
+			missing = append(missing, file)
+		}
+	}
+
+	if len(missing) != 0 {
+		gitx := git.NewCtxAt(repo.RepoDir)
+		findings = append(findings, finding{
+			Problem: strs.Fmt("Repository '%s' uses Git LFS but LFS hooks are missing:\n%s",
+				repo.RepoDir, formatList(missing)),
+			Suggestion: "Install the LFS hooks with 'git lfs update'.",
+			Fix:        func() error { return gitx.Check("lfs", "update") }})
+	}
+
+	return
+}
+
+func checkLegacyGitConfig(ctx *ccm.CmdContext, repo *repository) (findings []finding) {
+	scopes := []git.ConfigScope{git.GlobalScope}
+	if repo != nil {
+		scopes = append(scopes, git.LocalScope)
+	}
+
+	for _, scope := range scopes {
+		for _, k := range hooks.GetLegacyGitConfigKeys() {
+			if len(ctx.GitX.GetConfigAll(k, scope)) == 0 {
+				continue
+			}
+
+			k, scope := k, scope
+			findings = append(findings, finding{
+				Problem:    strs.Fmt("Legacy Git config '%s' is set.", k),
+				Suggestion: "Unset it, it is not used anymore.",
+				Fix:        func() error { return ctx.GitX.UnsetConfig(k, scope) }})
+		}
+	}
+
+	return
+}
+
+func formatList(items []string) string {
+	return strings.Join(strs.Map(items, func(s string) string {
+		return strs.Fmt(" %s '%s'", cm.ListItemLiteral, s)
+	}), "\n")
+}
+
+func indent(s string) string {
+	return strings.ReplaceAll(s, "\n", "\n     ")
+}
+
+func runDoctor(ctx *ccm.CmdContext, fix bool) error {
+	var repo *repository
+	if repoDir, gitDir, _, err := ctx.GitX.GetRepoRoot(); err == nil {
+		repo = &repository{RepoDir: repoDir, GitDir: gitDir}
+	}
+
+	var sb strings.Builder
+	problems := 0
+	fixed := 0
+
+	for _, c := range checks {
+		findings := c.Run(ctx, repo)
+
+		if len(findings) == 0 {
+			sb.WriteString(strs.Fmt(" ✓ %s\n", c.Name))
+
+			continue
+		}
+
+		sb.WriteString(strs.Fmt(" ✗ %s\n", c.Name))
+
+		for _, f := range findings {
+			status := ""
+
+			switch {
+			case fix && f.Fix != nil:
+				if err := f.Fix(); err != nil {
+					status = strs.Fmt(" [fix failed: %s]", err.Error())
+					problems++
+				} else {
+					status = " [fixed]"
+					fixed++
+				}
+			case f.Fix != nil:
+				status = " [fixable with '--fix']"
+				problems++
+			default:
+				problems++
+			}
+
+			sb.WriteString(strs.Fmt("   %s %s%s\n     Fix: %s\n",
+				cm.ListItemLiteral, indent(f.Problem), status, indent(f.Suggestion)))
+		}
+	}
+
+	ctx.Log.InfoF("Githooks health check:\n%s", strings.TrimSuffix(sb.String(), "\n"))
+
+	if fixed != 0 {
+		ctx.Log.InfoF("Fixed '%v' problems.", fixed)
+	}
+
+	if problems != 0 {
+		return ctx.NewCmdExit(1, "Found '%v' problems.", problems)
+	}
+
+	ctx.Log.Info("No problems found.")
+
+	return nil
+}
+
+// NewCmd creates this new command.
+func NewCmd(ctx *ccm.CmdContext) *cobra.Command {
+	fix := false
+
+	doctorCmd := &cobra.Command{
+		Use:   "doctor [flags]",
+		Short: "Checks the health of the Githooks installation.",
+		Long: `Checks the health of the Githooks installation and the current repository.
+
+It reports a missing install directory and executables, outdated run-wrappers,
+a mismatched 'core.hooksPath', unregistered repositories, broken shared
+repository clones, a missing container manager, missing LFS hooks and
+legacy Git config keys together with suggested fixes.
+
+With '--fix' all problems which are safe to fix are fixed.`,
+		PreRun: ccm.PanicIfAnyArgs(ctx.Log),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(ctx, fix)
+		}}
+
+	doctorCmd.Flags().BoolVar(&fix, "fix", false, "Fix all problems which are safe to fix.")
+
+	return ccm.SetCommandDefaults(ctx.Log, doctorCmd)
+}
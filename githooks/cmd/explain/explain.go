@@ -0,0 +1,416 @@
+package explain
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	ccm "github.com/gabyx/githooks/githooks/cmd/common"
+	"github.com/gabyx/githooks/githooks/cmd/list"
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
+	"github.com/gabyx/githooks/githooks/hooks"
+	strs "github.com/gabyx/githooks/githooks/strings"
+
+	"github.com/spf13/cobra"
+)
+
+// hookSource is a hook together with where it has been found.
+type hookSource struct {
+	Hook     *hooks.Hook
+	HookName string
+	HooksDir string
+
+	IsReplaced bool
+	Shared     *list.SharedHooks // Set if the hook is in a shared repository.
+}
+
+// ignoreFile is an ignore file together with the
+// namespace and root path its relative patterns are resolved with.
+type ignoreFile struct {
+	File      string
+	Namespace string
+	RootPath  string
+}
+
+func findHook(
+	ctx *ccm.CmdContext,
+	repoDir string,
+	gitDir string,
+	repoHooksDir string,
+	shared hooks.SharedRepos,
+	state *list.ListingState,
+	nsPathOrFile string) *hookSource {
+
+	isNamespacePath := strings.HasPrefix(nsPathOrFile, hooks.NamespacePrefix)
+	if !isNamespacePath {
+		file, err := filepath.Abs(nsPathOrFile)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not get absolute path of '%s'.", nsPathOrFile)
+		nsPathOrFile = filepath.ToSlash(file)
+	}
+
+	find := func(hs []hooks.Hook) *hooks.Hook {
+		for i := range hs {
+			if (isNamespacePath && hs[i].NamespacePath == nsPathOrFile) ||
+				(!isNamespacePath && hs[i].Path == nsPathOrFile) {
+				return &hs[i]
+			}
+		}
+
+		return nil
+	}
+
+	for _, hookName := range hooks.ManagedHookNames {
+
+		replacedHooksDir := path.Join(gitDir, "hooks")
+		replacedHooks := list.GetAllHooksIn(
+			ctx.Log, ctx.GitX, repoDir, replacedHooksDir, hookName,
+			hooks.NamespaceReplacedHook, state, false, true, false)
+
+		if h := find(replacedHooks); h != nil {
+			return &hookSource{Hook: h, HookName: hookName, HooksDir: replacedHooksDir, IsReplaced: true}
+		}
+
+		repoHooks := list.GetAllHooksIn(ctx.Log, ctx.GitX, repoDir, repoHooksDir, hookName,
+			hooks.NamespaceRepositoryHook, state, false, false, false)
+
+		if h := find(repoHooks); h != nil {
+			return &hookSource{Hook: h, HookName: hookName, HooksDir: repoHooksDir}
+		}
+
+		for idx, sharedRepos := range shared {
+			coll, _ := list.GetAllHooksInShared(ctx.Log, ctx.GitX,
+				hookName, state, sharedRepos, hooks.SharedHookType(idx))
+
+			for i := range coll {
+				if h := find(coll[i].Hooks); h != nil {
+					return &hookSource{
+						Hook:     h,
+						HookName: hookName,
+						HooksDir: hooks.GetSharedGithooksDir(coll[i].Repo.RepositoryDir),
+						Shared:   &coll[i]}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// explainIgnores explains if the hook is ignored by the patterns in the files `files`
+// which are evaluated together (in this order).
+func explainIgnores(
+	ctx *ccm.CmdContext,
+	sb *strings.Builder,
+	title string,
+	files []ignoreFile,
+	conditions *hooks.IgnoreConditionContext,
+	namespacePath string) (ignored bool) {
+
+	var patterns hooks.HookPatterns
+	origins := make(map[string]string)
+	var existing []string
+
+	for _, f := range files {
+		if !cm.IsFile(f.File) {
+			continue
+		}
+
+		ps, err := hooks.LoadIgnorePatterns(f.File)
+		ctx.Log.AssertNoErrorF(err, "Could not load ignore file '%s'.", f.File)
+		ps.MakeRelativePatternsAbsolute(f.Namespace, f.RootPath)
+
+		for _, p := range ps.Patterns {
+			origins[p] = f.File
+		}
+		for _, p := range ps.NamespacePaths {
+			origins[p] = f.File
+		}
+		for i := range ps.Conditional {
+			for _, p := range ps.Conditional[i].Patterns {
+				origins[p] = f.File
+			}
+			for _, p := range ps.Conditional[i].NamespacePaths {
+				origins[p] = f.File
+			}
+		}
+
+		patterns.Add(&ps)
+		existing = append(existing, f.File)
+	}
+
+	if len(existing) == 0 {
+		_, _ = strs.FmtW(sb, " %s %s: no ignore files.\n", cm.ListItemLiteral, title)
+
+		return false
+	}
+
+	patterns.EvaluateConditions(conditions)
+	ignored, matches := patterns.ExplainMatches(namespacePath)
+
+	_, _ = strs.FmtW(sb, " %s %s: ignored: '%v', files: '%q'\n", cm.ListItemLiteral, title, ignored, existing)
+
+	for i := range matches {
+		m := &matches[i]
+
+		kind := "pattern"
+		if m.IsPath {
+			kind = "namespace path"
+		}
+
+		effect := "ignores"
+		if m.Inverted {
+			effect = "activates"
+		}
+
+		_, _ = strs.FmtW(sb, "   - %s '%s' in '%s' %s the hook", kind, m.Pattern, origins[m.Pattern], effect)
+		if m.Condition != nil {
+			_, _ = strs.FmtW(sb, " [condition: %s]", m.Condition.String())
+		}
+		sb.WriteString(".\n")
+	}
+
+	return
+}
+
+func explainTrust(
+	ctx *ccm.CmdContext,
+	sb *strings.Builder,
+	repoDir string,
+	state *list.ListingState,
+	src *hookSource) (trusted bool) {
+
+	hook := src.Hook
+
+	err := hook.AssertChecksum()
+	ctx.Log.AssertNoErrorPanicF(err, "Could not compute SHA256 hash for hook '%s'.", hook.Path)
+
+	isRepoTrusted, _, _ := hooks.IsRepoTrusted(ctx.GitX, repoDir)
+	if isRepoTrusted {
+		if forbidden, remoteURL := state.Policy.IsTrustAllForbidden(ctx.GitX); forbidden {
+			_, _ = strs.FmtW(sb, " %s All hooks of the repository are trusted ('trust-all'), "+
+				"but this is forbidden by the trust policy '%s' for remote '%s'.\n",
+				cm.ListItemLiteral, state.Policy.File, remoteURL)
+		} else {
+			_, _ = strs.FmtW(sb, " %s Trusted: all hooks of the repository are trusted ('trust-all').\n",
+				cm.ListItemLiteral)
+
+			return true
+		}
+	}
+
+	if src.Shared != nil {
+		if src.Shared.TrustedByPolicy {
+			_, _ = strs.FmtW(sb, " %s Trusted: shared repository '%s' is trusted by the trust policy '%s'.\n",
+				cm.ListItemLiteral, src.Shared.Repo.OriginalURL, state.Policy.File)
+
+			return true
+		} else if src.Shared.TrustedRevision != nil {
+			_, _ = strs.FmtW(sb, " %s Trusted: shared repository '%s' is trusted at %s.\n",
+				cm.ListItemLiteral, src.Shared.Repo.OriginalURL, src.Shared.TrustedRevision.Description())
+
+			return true
+		}
+	}
+
+	trusted, _, err = state.Checksums.IsTrusted(hook.Path)
+	ctx.Log.AssertNoErrorF(err, "Could not check trust status '%s'.", hook.Path)
+
+	if trusted {
+		_, _ = strs.FmtW(sb, " %s Trusted: checksum [sha256: '%s'] is in the checksum store.\n",
+			cm.ListItemLiteral, hook.Checksum)
+
+		return true
+	}
+
+	if expired, _ := state.Checksums.IsExpired(hook.Checksum); expired {
+		_, _ = strs.FmtW(sb, " %s Untrusted: trust of checksum [sha256: '%s'] expired.\n",
+			cm.ListItemLiteral, hook.Checksum)
+
+		return false
+	}
+
+	change, err := state.Checksums.GetHookChange(hook.Path)
+	ctx.Log.AssertNoErrorF(err, "Could not get changes of hook '%s'.", hook.Path)
+
+	if change.IsNew() {
+		_, _ = strs.FmtW(sb, " %s Untrusted: checksum [sha256: '%s'] is not in the checksum store.\n",
+			cm.ListItemLiteral, hook.Checksum)
+	} else {
+		_, _ = strs.FmtW(sb, " %s Untrusted: hook changed since it was last trusted [sha256: '%s'].\n",
+			cm.ListItemLiteral, change.PreviousChecksum)
+	}
+
+	return false
+}
+
+func explainRun(ctx *ccm.CmdContext, sb *strings.Builder, state *list.ListingState, src *hookSource) {
+	hook := src.Hook
+
+	_, _ = strs.FmtW(sb, " %s Command: '%s', args: '%q'\n",
+		cm.ListItemLiteral, hook.GetCommand(), hook.GetArgs())
+
+	if strs.IsNotEmpty(hook.BatchName) {
+		_, _ = strs.FmtW(sb, " %s Parallel batch: '%s'\n", cm.ListItemLiteral, hook.BatchName)
+	}
+
+	if !hooks.IsContainerizedHooksEnabled(ctx.GitX, true) {
+		return
+	}
+
+	imageRef, err := hooks.GetHookImageReference(hook.Path, hook.Namespace)
+	ctx.Log.AssertNoErrorF(err, "Could not get image reference of hook '%s'.", hook.Path)
+
+	if strs.IsEmpty(imageRef) {
+		return
+	}
+
+	builtImages, err := hooks.GetLocallyBuiltImageReferences(ctx.GitX, src.HooksDir)
+	ctx.Log.AssertNoErrorF(err, "Could not get built images in '%s'.", src.HooksDir)
+
+	if strs.Includes(builtImages, imageRef) {
+		_, _ = strs.FmtW(sb, " %s Runs containerized in locally built image '%s'\n",
+			cm.ListItemLiteral, imageRef)
+
+		return
+	}
+
+	allowed, registry, err := state.Policy.IsImageAllowed(imageRef)
+	ctx.Log.AssertNoErrorF(err, "Could not check image reference '%s'.", imageRef)
+
+	_, _ = strs.FmtW(sb, " %s Runs containerized in image '%s' [registry: '%s', allowed by policy: '%v']\n",
+		cm.ListItemLiteral, imageRef, registry, allowed)
+}
+
+func runExplain(ctx *ccm.CmdContext, nsPathOrFile string) {
+	repoDir, gitDir, gitDirWorktree := ccm.AssertRepoRoot(ctx)
+
+	repoHooksDir := hooks.GetGithooksDir(repoDir)
+	state, shared, hookNamespace := list.PrepareListHookState(
+		ctx, repoDir, repoHooksDir, gitDirWorktree, hooks.ManagedHookNames)
+
+	src := findHook(ctx, repoDir, gitDir, repoHooksDir, shared, state, nsPathOrFile)
+	ctx.Log.PanicIfF(src == nil,
+		"No hook found with namespace path or file '%s'.\n"+
+			"See 'git hooks list' for all hooks.", nsPathOrFile)
+
+	hook := src.Hook
+	var sb strings.Builder
+
+	category := hooks.TagNameRepository
+	switch {
+	case src.IsReplaced:
+		category = hooks.TagNameReplaced
+	case src.Shared != nil:
+		category = hooks.GetSharedRepoTagNames()[src.Shared.Category]
+	}
+
+	_, _ = strs.FmtW(&sb, "Hook '%s':\n", hook.NamespacePath)
+	_, _ = strs.FmtW(&sb, " %s Path: '%s'\n", cm.ListItemLiteral, hook.Path)
+	_, _ = strs.FmtW(&sb, " %s Hook: '%s', type: '%s'\n", cm.ListItemLiteral, src.HookName, category)
+	if src.Shared != nil {
+		_, _ = strs.FmtW(&sb, " %s Shared repository: '%s'\n", cm.ListItemLiteral, src.Shared.Repo.OriginalURL)
+	}
+
+	// Ignores
+	sb.WriteString("\nIgnores:\n")
+
+	conditions := hooks.NewIgnoreConditionContext(ctx.GitX, nil, false)
+
+	userFiles := []ignoreFile{{File: hooks.GetHookIgnoreFileGitDir(gitDirWorktree), Namespace: hookNamespace}}
+	ignored := explainIgnores(ctx, &sb, "User ignores", userFiles, conditions, hook.NamespacePath)
+
+	// Replaced hooks can only be ignored by the user.
+	if !src.IsReplaced {
+		repoFiles := []ignoreFile{
+			{File: hooks.GetHookIgnoreFileHooksDir(repoHooksDir, ""), Namespace: hookNamespace},
+			{File: hooks.GetHookIgnoreFileHooksDir(repoHooksDir, src.HookName),
+				Namespace: hookNamespace, RootPath: src.HookName}}
+
+		ignored = explainIgnores(ctx, &sb, "Repository ignores", repoFiles, conditions, hook.NamespacePath) ||
+			ignored
+	}
+
+	if src.Shared != nil {
+		sharedFiles := []ignoreFile{
+			{File: hooks.GetHookIgnoreFileHooksDir(src.HooksDir, ""), Namespace: hook.Namespace},
+			{File: hooks.GetHookIgnoreFileHooksDir(src.HooksDir, src.HookName),
+				Namespace: hook.Namespace, RootPath: src.HookName}}
+
+		ignored = explainIgnores(ctx, &sb, "Shared repository ignores", sharedFiles, conditions, hook.NamespacePath) ||
+			ignored
+	}
+
+	cm.DebugAssertF(ignored == !hook.Active, "Explained ignore state of '%s' differs.", hook.NamespacePath)
+
+	skipPatterns, err := hooks.GetEnvSkipPatterns(hookNamespace)
+	ctx.Log.AssertNoErrorF(err, "Errors while loading patterns in '%s'.", hooks.EnvVariableSkip)
+
+	skipped := skipPatterns.Matches(hook.NamespacePath)
+	switch {
+	case skipped && state.Policy.IsSkipForbidden(hook.NamespacePath):
+		skipped = false
+		_, _ = strs.FmtW(&sb, " %s Matched by '%s' but skipping is forbidden by the trust policy '%s'.\n",
+			cm.ListItemLiteral, hooks.EnvVariableSkip, state.Policy.File)
+	case skipped:
+		_, _ = strs.FmtW(&sb, " %s Skipped by env '%s'.\n", cm.ListItemLiteral, hooks.EnvVariableSkip)
+	}
+
+	// Trust
+	sb.WriteString("\nTrust:\n")
+	trusted := explainTrust(ctx, &sb, repoDir, state, src)
+
+	// Run
+	sb.WriteString("\nRun:\n")
+	explainRun(ctx, &sb, state, src)
+
+	isDisabled := hooks.IsGithooksDisabled(ctx.GitX, true)
+	skipUntrusted, _ := hooks.SkipUntrustedHooks(ctx.GitX, git.Traverse)
+
+	var result string
+	switch {
+	case isDisabled && !src.IsReplaced:
+		result = "does not run: Githooks is disabled (see 'git hooks disable')"
+	case !hook.Active:
+		result = "does not run: it is ignored"
+	case skipped:
+		result = strs.Fmt("does not run: it is skipped by env '%s'", hooks.EnvVariableSkip)
+	case !trusted && skipUntrusted:
+		result = "does not run: it is untrusted and untrusted hooks are skipped " +
+			"(see 'git hooks config skip-untrusted-hooks')"
+	case !trusted:
+		result = "needs to be trusted: the trust prompt is shown and " +
+			"the hook fails if it stays untrusted (see 'git hooks trust hooks')"
+	default:
+		result = "runs"
+	}
+
+	_, _ = strs.FmtW(&sb, "\n=> The hook %s.", result)
+
+	ctx.Log.Info(sb.String())
+}
+
+// NewCmd creates this new command.
+func NewCmd(ctx *ccm.CmdContext) *cobra.Command {
+
+	explainCmd := &cobra.Command{
+		Use:   "explain <ns-path|file>",
+		Short: "Explains why a hook runs or not.",
+		Long: `Explains why a hook given by its namespace path or file runs or not.
+
+It traces which ignore files and which patterns (including inversions '!')
+ignore or activate the hook, from where the hook's trust originates
+and how the runner executes the hook.
+
+Conditions on hook arguments ('hook-args') of conditional ignore patterns
+are only fulfilled when hooks are run.
+
+To see the namespace paths of all hooks in the active repository,
+see '<ns-path>' in the output of 'git hooks list'.`,
+		PreRun: ccm.PanicIfNotExactArgs(ctx.Log, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+			runExplain(ctx, args[0])
+		}}
+
+	return ccm.SetCommandDefaults(ctx.Log, explainCmd)
+}
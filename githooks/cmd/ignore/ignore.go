@@ -20,9 +20,10 @@ type ignoreActionOptions struct {
 }
 
 type ignoreShowOptions struct {
-	User         bool
-	Repository   bool
-	OnlyExisting bool
+	User          bool
+	Repository    bool
+	OnlyExisting  bool
+	NamespacePath string
 }
 
 func loadIgnoreFile(
@@ -102,12 +103,72 @@ func runIgnoreAddPattern(
 	ctx.Log.InfoF("%s file '%s'.", text, file)
 }
 
+func formatConditions(ctx *ccm.CmdContext, sb *strings.Builder, file string, conditions *hooks.IgnoreConditionContext) {
+	patterns, err := hooks.LoadIgnorePatterns(file)
+	ctx.Log.AssertNoErrorF(err, "Could not load ignore file '%s'.", file)
+
+	for i := range patterns.Conditional {
+		c := &patterns.Conditional[i]
+
+		_, err := strs.FmtW(
+			sb, "   - when: [%s], fulfilled: '%v', patterns: '%q', paths: '%q'\n",
+			c.When.String(), c.When.Evaluate(conditions), c.Patterns, c.NamespacePaths)
+		cm.AssertNoErrorPanic(err, "Could not format ignore conditions.")
+	}
+}
+
+func showIgnoredPath(
+	ctx *ccm.CmdContext,
+	repoRoot string,
+	gitDirWorktree string,
+	conditions *hooks.IgnoreConditionContext,
+	namespacePath string) {
+
+	repoHooksDir := hooks.GetGithooksDir(repoRoot)
+
+	hookNamespace, err := hooks.GetHooksNamespace(repoHooksDir)
+	ctx.Log.AssertNoErrorF(err, "Errors while loading hook namespace.")
+	if strs.IsEmpty(hookNamespace) {
+		hookNamespace = hooks.NamespaceRepositoryHook
+	}
+
+	ignores, err := hooks.GetIgnorePatterns(repoHooksDir, gitDirWorktree, hooks.ManagedHookNames, hookNamespace)
+	ctx.Log.AssertNoErrorF(err, "Errors while loading ignore patterns.")
+	ignores.EvaluateConditions(conditions)
+
+	ignored, byUser, cond := ignores.IsIgnoredCondition(namespacePath)
+
+	category := "repository"
+	if byUser {
+		category = "user"
+	}
+
+	switch {
+	case !ignored:
+		ctx.Log.InfoF("Hook '%s' is not ignored.", namespacePath)
+	case cond == nil:
+		ctx.Log.InfoF("Hook '%s' is ignored by the %s ignore patterns.", namespacePath, category)
+	default:
+		ctx.Log.InfoF("Hook '%s' is ignored by the %s ignore patterns with condition [%s].",
+			namespacePath, category, cond.String())
+	}
+}
+
 func runIgnoreShow(ctx *ccm.CmdContext, ignShow *ignoreShowOptions) {
 
 	repoRoot, _, gitDirWorktree := ccm.AssertRepoRoot(ctx)
 	var sb strings.Builder
 	count := 0
 
+	// Hook arguments are not known here.
+	conditions := hooks.NewIgnoreConditionContext(ctx.GitX, nil, false)
+
+	if strs.IsNotEmpty(ignShow.NamespacePath) {
+		showIgnoredPath(ctx, repoRoot, gitDirWorktree, conditions, ignShow.NamespacePath)
+
+		return
+	}
+
 	print := func(file string, catergory string) {
 		exists := cm.IsFile(file)
 		if !ignShow.OnlyExisting || exists {
@@ -117,6 +178,10 @@ func runIgnoreShow(ctx *ccm.CmdContext, ignShow *ignoreShowOptions) {
 				cm.ListItemLiteral, file, exists, catergory)
 			cm.AssertNoErrorPanic(err, "Could not format ignore files.")
 
+			if exists {
+				formatConditions(ctx, &sb, file, conditions)
+			}
+
 			count++
 		}
 	}
@@ -284,13 +349,19 @@ about the pattern syntax and namespace paths.`,
 		}}
 
 	ignoreShowCmd := &cobra.Command{
-		Use:    "show [flags]...",
-		Short:  "Shows the paths of the ignore files.",
-		Long:   `Shows the paths of the ignore files.`,
+		Use:   "show [flags]...",
+		Short: "Shows the paths of the ignore files.",
+		Long: `Shows the paths of the ignore files and the conditions of
+conditional ignore patterns in them.
+
+With '--path' it shows if the hook with the given namespace path is ignored
+and by which condition.
+Conditions on hook arguments ('hook-args') are only fulfilled
+when hooks are run.`,
 		PreRun: ccm.PanicIfAnyArgs(ctx.Log),
 		Run: func(c *cobra.Command, args []string) {
 
-			if c.Flags().NFlag() == 0 {
+			if !ignoreShowOpts.Repository && !ignoreShowOpts.User {
 				ignoreShowOpts.Repository = true
 				ignoreShowOpts.User = true
 			}
@@ -306,6 +377,9 @@ about the pattern syntax and namespace paths.`,
 	ignoreShowCmd.Flags().BoolVar(&ignoreShowOpts.OnlyExisting,
 		"only-existing", false, "Show only existing ignore files.")
 
+	ignoreShowCmd.Flags().StringVar(&ignoreShowOpts.NamespacePath,
+		"path", "", "Show if the hook with this namespace path is ignored and by which condition.")
+
 	addFlags(ignoreAddPatternCmd, &patterns)
 	addIgnoreOpts(ignoreAddPatternCmd, &ignoreActionOpts, false)
 	ignoreCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, ignoreAddPatternCmd))
@@ -10,6 +10,12 @@ import (
 )
 
 func runImagesUpdate(ctx *ccm.CmdContext, imagesFile string) {
+	if hooks.IsOfflineMode(ctx.GitX, git.Traverse, true) {
+		ctx.Log.WarnF("Githooks is in offline mode: updating images skipped.")
+
+		return
+	}
+
 	repoDir, _, _, err := ctx.GitX.GetRepoRoot()
 
 	if err != nil {
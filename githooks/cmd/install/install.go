@@ -4,18 +4,74 @@ import (
 	ccm "github.com/gabyx/githooks/githooks/cmd/common"
 	inst "github.com/gabyx/githooks/githooks/cmd/common/install"
 	"github.com/gabyx/githooks/githooks/cmd/installer"
+	cm "github.com/gabyx/githooks/githooks/common"
 	"github.com/gabyx/githooks/githooks/git"
 	"github.com/gabyx/githooks/githooks/hooks"
+	strs "github.com/gabyx/githooks/githooks/strings"
 
 	"github.com/spf13/cobra"
 )
 
-func runInstallIntoRepo(ctx *ccm.CmdContext, maintainedHooks []string, nonInteractive bool) {
+// runInstallRepoHooksPath installs Githooks into the current repository
+// in install mode 'repo-hooks-path' by setting the local 'core.hooksPath'.
+func runInstallRepoHooksPath(
+	ctx *ccm.CmdContext,
+	gitDir string,
+	maintainedHooks []string,
+	externalHooks string,
+	nonInteractive bool) {
+	hooksDir, err := inst.FindHookTemplateDir(ctx.GitX, inst.InstallModeTypeV.RepoHooksPath)
+	ctx.Log.AssertNoErrorPanicF(err, "Could not get Githooks hooks directory.")
+	ctx.Log.PanicIfF(strs.IsEmpty(hooksDir) || !cm.IsDirectory(hooksDir),
+		"Githooks hooks directory for install mode '%s' is not found.\n"+
+			"Is '%s' unset?",
+		inst.GetInstallModeName(inst.InstallModeTypeV.RepoHooksPath),
+		hooks.GitCKPathForUseRepoHooksPath)
+
+	if maintainedHooks != nil {
+		ctx.Log.WarnF("Maintained hooks are set by the installer in install mode '%s'.\n"+
+			"Ignoring '--maintained-hooks'.",
+			inst.GetInstallModeName(inst.InstallModeTypeV.RepoHooksPath))
+	}
+
+	// A local `core.hooksPath` of an external hook manager
+	// gets saved and restored by `InstallRepoHooksPath`.
+	uiSettings := inst.UISettings{PromptCtx: ctx.PromptCtx}
+	inst.SetupExternalHookManagers(ctx.Log, gitDir, externalHooks, false, nonInteractive, false, &uiSettings)
+
+	inst.InstallRepoHooksPath(ctx.Log, gitDir, hooksDir, false)
+
+	err = hooks.RegisterRepo(gitDir, ctx.InstallDir, false, false)
+	ctx.Log.AssertNoError(err, "Could not register repository '%s'.", gitDir)
+	err = hooks.MarkRepoRegistered(ctx.GitX)
+	ctx.Log.AssertNoError(err, "Could not mark repository '%s' as registered.", gitDir)
+}
+
+func runInstallIntoRepo(
+	ctx *ccm.CmdContext,
+	maintainedHooks []string,
+	externalHooks string,
+	nonInteractive bool) {
 	_, gitDir, _ := ccm.AssertRepoRoot(ctx)
 
+	ctx.Log.PanicIfF(strs.IsNotEmpty(externalHooks) && !strs.Includes(inst.GetExternalHooksModes(), externalHooks),
+		"Value '%s' for '--external-hooks' is not one of '%q'.", externalHooks, inst.GetExternalHooksModes())
+
+	if inst.GetInstallMode(ctx.GitX) == inst.InstallModeTypeV.RepoHooksPath {
+		runInstallRepoHooksPath(ctx, gitDir, maintainedHooks, externalHooks, nonInteractive)
+
+		return
+	}
+
 	// Check if useCoreHooksPath or core.hooksPath is set
 	// and if so error out.
+	// A local value set by an external hook manager is handled on install.
 	value, exists := ctx.GitX.LookupConfig(git.GitCKCoreHooksPath, git.Traverse)
+	if local, isLocal := ctx.GitX.LookupConfig(git.GitCKCoreHooksPath, git.LocalScope); isLocal &&
+		externalHooks != inst.ExternalHooksSkip && inst.IsExternalHooksPath(gitDir, local) {
+		exists = false
+	}
+
 	ctx.Log.PanicIfF(exists, "You are using already '%s' = '%s'\n"+
 		"Installing Githooks run-wrappers into '%s'\n"+
 		"has no effect.",
@@ -46,7 +102,7 @@ func runInstallIntoRepo(ctx *ccm.CmdContext, maintainedHooks []string, nonIntera
 	inst.InstallIntoRepo(
 		ctx.Log, ctx.GitX, gitDir,
 		lfsHooksCache, maintainedHooks,
-		nonInteractive, false, false, &uiSettings)
+		nonInteractive, false, false, externalHooks, &uiSettings)
 
 	err = hooks.RegisterRepo(gitDir, ctx.InstallDir, false, false)
 	ctx.Log.AssertNoError(err, "Could not register repository '%s'.", gitDir)
@@ -67,7 +123,7 @@ func runUninstallFromRepo(ctx *ccm.CmdContext) {
 	lfsHooksCache, err := hooks.NewLFSHooksCache(hooks.GetTemporaryDir(ctx.InstallDir))
 	ctx.Log.AssertNoErrorPanicF(err, "Could not create LFS hooks cache.")
 
-	if inst.UninstallFromRepo(ctx.Log, gitDir, lfsHooksCache, false) {
+	if inst.UninstallFromRepo(ctx.Log, gitDir, lfsHooksCache, false, false, false) {
 
 		registeredGitDirs.Remove(gitDir)
 		err := registeredGitDirs.Store(ctx.InstallDir)
@@ -80,8 +136,8 @@ func runUninstall(ctx *ccm.CmdContext) {
 	runUninstallFromRepo(ctx)
 }
 
-func runInstall(ctx *ccm.CmdContext, maintainedHooks []string, nonInteractive bool) {
-	runInstallIntoRepo(ctx, maintainedHooks, nonInteractive)
+func runInstall(ctx *ccm.CmdContext, maintainedHooks []string, externalHooks string, nonInteractive bool) {
+	runInstallIntoRepo(ctx, maintainedHooks, externalHooks, nonInteractive)
 }
 
 // NewCmd creates this new command.
@@ -89,18 +145,32 @@ func NewCmd(ctx *ccm.CmdContext) []*cobra.Command {
 
 	var maintainedHooks *[]string
 	nonInteractive := false
+	externalHooks := ""
 
 	installCmd := &cobra.Command{
 		Use:   "install",
 		Short: "Installs Githooks run-wrappers into the current repository.",
 		Long: `Installs the Githooks run-wrappers and Git config settings
-into the current repository.`,
+into the current repository.
+
+In install mode 'repo-hooks-path' the local Git config 'core.hooksPath'
+is set to the Githooks hooks directory instead. A previous local value
+is restored by 'git hooks uninstall'.
+
+Other hook managers (husky, pre-commit, lefthook) configured in the
+repository are detected and can be chained, i.e. run by Githooks
+in namespace 'gh-external', or imported as Githooks runner configs
+into the repository's '.githooks' directory.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			runInstall(ctx, *maintainedHooks, nonInteractive)
+			runInstall(ctx, *maintainedHooks, externalHooks, nonInteractive)
 		},
 	}
 
 	installCmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "Install non-interactively.")
+	installCmd.Flags().StringVar(&externalHooks, "external-hooks", "",
+		"How to handle detected hook managers (husky, pre-commit, lefthook):\n"+
+			"'chain', 'import' or 'skip'. Asks if not given\n"+
+			"and skips them in non-interactive mode.")
 	maintainedHooks = installCmd.Flags().StringSlice(
 		"maintained-hooks", nil,
 		"A set of hook names which are maintained in this repository.\n"+
@@ -110,7 +180,9 @@ into the current repository.`,
 		Use:   "uninstall",
 		Short: "Uninstalls Githooks run-wrappers into the current repository.",
 		Long: `Uninstall the Githooks run-wrappers and Git config settings
-into the current repository.`,
+into the current repository.
+A local Git config 'core.hooksPath' set by 'git hooks install'
+is restored to its previous value.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			runUninstall(ctx)
 		},
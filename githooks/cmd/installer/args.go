@@ -4,6 +4,9 @@ package installer
 type Arguments struct {
 	Config string
 
+	InstallConfig string // Declarative install configuration file.
+	PrintConfig   bool   // Print the effective install configuration.
+
 	Log                  string // The log file.
 	InternalAutoUpdate   bool   // If the installer is run from the runner.
 	InternalPostDispatch bool   // If the installer has already dispatched itself to the downloaded/build installer.
@@ -11,12 +14,16 @@ type Arguments struct {
 	InternalUpdateFromVersion string   // Build version we are updating from.
 	InternalUpdateTo          string   // Commit SHA to update local branch to remote.
 	InternalBinaries          []string // Binaries which need to get installed.
+	InternalBundleDir         string   // The extracted install bundle.
+
+	FromBundle string // Install offline from this install bundle.
 
 	DryRun         bool
 	NonInteractive bool
 
 	Update bool // Directly update to the latest possible tag on the clone branch.
 	// Before `2.3.3` that was always true.
+	UpdateTo string // Directly update or downgrade to this version tag.
 
 	SkipInstallIntoExisting bool // Skip install into existing repositories.
 
@@ -24,6 +31,7 @@ type Arguments struct {
 
 	UseCoreHooksPath bool // Use install mode: `core.hooksPath` for the template dir.
 	UseManual        bool // Use install mode: manual -> no `core.hooksPath` nor `init.templateDir`
+	UseRepoHooksPath bool // Use install mode: local `core.hooksPath` set by `git hooks install`.
 
 	InstallPrefix string // Install prefix for Githooks.
 	TemplateDir   string // Template dir to use for the hooks.
@@ -36,7 +44,13 @@ type Arguments struct {
 	BuildFromSource bool     // If we build the install/update from source.
 	BuildTags       []string // Go build tags.
 
-	UsePreRelease bool // If also pre-release versions should be considered.
+	UsePreRelease bool   // If also pre-release versions should be considered.
+	UpdateChannel string // The update channel defined in the deploy settings.
+
+	SharedRepos               []string // Global shared repositories to configure.
+	AutoUpdateEnabled         *bool    // Enable automatic update checks, prompted if not set.
+	ContainerizedHooksEnabled *bool    // Enable running hooks containerized, unchanged if not set.
+	ContainerManager          string   // Container manager to configure.
 
 	UseStdin bool
 }
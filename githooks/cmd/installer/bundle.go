@@ -0,0 +1,537 @@
+package installer
+
+import (
+	"os"
+	"path"
+	"runtime"
+	"strings"
+
+	"github.com/gabyx/githooks/githooks/build"
+	ccm "github.com/gabyx/githooks/githooks/cmd/common"
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/container"
+	"github.com/gabyx/githooks/githooks/git"
+	"github.com/gabyx/githooks/githooks/hooks"
+	strs "github.com/gabyx/githooks/githooks/strings"
+	"github.com/gabyx/githooks/githooks/updates"
+	"github.com/gabyx/githooks/githooks/updates/download"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// BundleSharedRepo is a shared hook repository in an install bundle.
+type BundleSharedRepo struct {
+	// The shared repository url as configured.
+	URL string `yaml:"url"`
+	// The bundled branch.
+	Branch string `yaml:"branch"`
+	// The Git bundle file relative to the install bundle.
+	Bundle string `yaml:"bundle"`
+}
+
+// BundleManifest is the manifest of an install bundle
+// created by `git hooks installer bundle`.
+type BundleManifest struct {
+	// The bundled version tag.
+	Tag string `yaml:"tag"`
+
+	// The clone url and branch of the bundled release clone.
+	CloneURL    string `yaml:"cloneURL"`
+	CloneBranch string `yaml:"cloneBranch"`
+
+	// The platforms `<os>/<arch>` of the bundled binaries.
+	Platforms []string `yaml:"platforms"`
+
+	// The bundled shared hook repositories.
+	SharedRepos []BundleSharedRepo `yaml:"sharedRepos"`
+
+	// The container image archive relative to the install bundle.
+	// Empty if no images are bundled.
+	Images string `yaml:"images"`
+
+	// The version of the file.
+	Version int `yaml:"version"`
+}
+
+// Version for BundleManifest.
+// Version 1: Initial.
+const bundleManifestVersion int = 1
+
+const (
+	bundleManifestFile = "bundle.yaml"
+	bundleReleaseFile  = "release.bundle"
+	bundleImagesFile   = "images.tar"
+)
+
+// getBundleBinaryDir gets the directory of the release archive
+// for `platform` in the install bundle.
+func getBundleBinaryDir(bundleDir string, platform download.Platform) string {
+	return path.Join(bundleDir, "bin", platform.OS+"-"+platform.Arch)
+}
+
+type bundleOptions struct {
+	Output      string
+	Platforms   []string
+	SharedRepos []string
+	Images      bool
+}
+
+func newBundleCmd(ctx *ccm.CmdContext, vi *viper.Viper) *cobra.Command {
+	opts := bundleOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "bundle [flags]",
+		Short: "Create an install bundle for offline installations.",
+		Long: `Create an install bundle for machines without internet access.
+
+The bundle contains the release clone, the signed release archives
+for all platforms given by '--platform' and optionally shared hook
+repositories (see '--shared') and their container images (see '--images').
+
+The bundle contains the current version, or the latest version
+with '--update', or the version given by '--update-to'.
+The clone url, branch and deploy settings are taken from the installer flags
+'--clone-url', '--clone-branch', '--deploy-api' and '--deploy-settings'.
+The deploy settings are not bundled.
+
+Install the bundle on the offline machine with
+  $ cli installer --from-bundle <bundle>
+where 'cli' is extracted from the release archive in the 'bin' directory
+in the bundle or taken from an existing installation.
+The bundled release archive is verified with the deploy PGP key
+embedded in 'cli' before installing.`,
+		PreRun: ccm.PanicIfAnyArgs(ctx.Log),
+		Run: func(cmd *cobra.Command, _ []string) {
+			runBundle(ctx, vi, &opts)
+		}}
+
+	cmd.Flags().StringVar(&opts.Output, "output", "githooks-bundle.tar.gz",
+		"The install bundle file to create.")
+	cm.AssertNoErrorPanic(cmd.MarkFlagFilename("output"))
+	cmd.Flags().StringSliceVar(&opts.Platforms, "platform", nil,
+		"The platforms '<os>/<arch>' (e.g. 'linux/amd64') of the bundled binaries.\n"+
+			"Defaults to the current platform.")
+	cmd.Flags().StringArrayVar(&opts.SharedRepos, "shared", nil,
+		"The url of a shared hook repository to bundle (can be given multiple times).")
+	cmd.Flags().BoolVar(&opts.Images, "images", false,
+		"If the container images of the bundled shared hook repositories\n"+
+			"are built or pulled and bundled.")
+
+	return ccm.SetCommandDefaults(ctx.Log, cmd)
+}
+
+func runBundle(ctx *ccm.CmdContext, vi *viper.Viper, opts *bundleOptions) {
+	log := ctx.Log
+
+	args := Arguments{}
+	initArgs(log, &args, vi)
+
+	log.PanicIfF(args.BuildFromSource, "Bundles cannot be built from source.")
+	log.PanicIfF(args.Update && strs.IsNotEmpty(args.UpdateTo),
+		"You cannot specify '--update' together with '--update-to'.")
+	log.PanicIfF(strs.IsNotEmpty(args.DeployAPI) && strs.IsNotEmpty(args.DeploySettings),
+		"You cannot specify a deploy api type together with\n"+
+			"a deploy settings file.")
+
+	platforms := []download.Platform{download.GetRuntimePlatform()}
+	if len(opts.Platforms) != 0 {
+		platforms = nil
+		for _, p := range opts.Platforms {
+			platform, err := download.ParsePlatform(p)
+			log.AssertNoErrorPanic(err, "Platform is not valid.")
+			platforms = append(platforms, platform)
+		}
+	}
+
+	tempDir, err := os.MkdirTemp(os.TempDir(), "githooks-bundle-*")
+	log.AssertNoErrorPanic(err, "Can not create temporary bundle dir in '%s'", os.TempDir())
+	ctx.CleanupX.AddHandler(func() {
+		_ = os.RemoveAll(tempDir)
+	})
+	defer os.RemoveAll(tempDir)
+
+	workDir := path.Join(tempDir, "work")
+	bundleDir := path.Join(tempDir, "bundle")
+	err = os.MkdirAll(bundleDir, cm.DefaultFileModeDirectory)
+	log.AssertNoErrorPanicF(err, "Could not create directory '%s'.", bundleDir)
+
+	manifest := BundleManifest{Version: bundleManifestVersion}
+
+	bundleReleaseClone(log, ctx.GitX, &args, workDir, bundleDir, &manifest)
+
+	// The deploy settings are only used to download the release archives
+	// and are not bundled since nothing verifies them on the offline machine.
+	deploySettings := loadDeploySettings(log, ctx.InstallDir, manifest.CloneURL, &args)
+
+	for _, platform := range platforms {
+		log.InfoF("Download '%s' for platform '%s' from deploy source...", manifest.Tag, platform)
+		downloadArchive(log, deploySettings, getBundleBinaryDir(bundleDir, platform), manifest.Tag, platform)
+		manifest.Platforms = append(manifest.Platforms, platform.String())
+	}
+
+	imageRefs := bundleSharedRepos(log, ctx.InstallDir, opts, workDir, bundleDir, &manifest)
+
+	if len(imageRefs) != 0 {
+		log.InfoF("Saving '%v' container images...", len(imageRefs))
+
+		mgr, err := container.NewManager(ctx.GitX.GetConfig(hooks.GitCKContainerManager, git.Traverse))
+		log.AssertNoErrorPanic(err, "Creating container manager failed.")
+
+		err = mgr.ImageSave(path.Join(bundleDir, bundleImagesFile), imageRefs...)
+		log.AssertNoErrorPanic(err, "Could not save container images.")
+		manifest.Images = bundleImagesFile
+	}
+
+	err = cm.StoreYAML(path.Join(bundleDir, bundleManifestFile), &manifest)
+	log.AssertNoErrorPanic(err, "Could not store bundle manifest.")
+
+	file, err := os.Create(opts.Output)
+	log.AssertNoErrorPanicF(err, "Could not create bundle '%s'.", opts.Output)
+	defer file.Close()
+
+	err = cm.CreateTarGz(bundleDir, file)
+	log.AssertNoErrorPanicF(err, "Could not write bundle '%s'.", opts.Output)
+
+	log.InfoF("Created install bundle '%s' with version '%s' for platforms:\n%s",
+		opts.Output, manifest.Tag,
+		strings.Join(strs.Map(manifest.Platforms, func(s string) string {
+			return strs.Fmt(" %s '%s'", cm.ListItemLiteral, s)
+		}), "\n"))
+}
+
+// bundleReleaseClone clones the release repository and
+// stores the branch up to the bundled version in the bundle.
+func bundleReleaseClone(
+	log cm.ILogContext,
+	gitx *git.Context,
+	args *Arguments,
+	workDir string,
+	bundleDir string,
+	manifest *BundleManifest) {
+
+	url, branch := updates.GetCloneURL(gitx)
+	if strs.IsNotEmpty(args.CloneURL) {
+		url, branch = args.CloneURL, args.CloneBranch
+	}
+
+	if strs.IsEmpty(url) {
+		url = updates.GetDefaultCloneURL()
+	}
+	if strs.IsEmpty(branch) {
+		branch = updates.GetDefaultCloneBranch()
+	}
+
+	cloneDir := path.Join(workDir, "release")
+	log.InfoF("Cloning release repository '%s'...", url)
+	err := git.Clone(cloneDir, url, branch, -1)
+	log.AssertNoErrorPanic(err, "Could not clone release repository.")
+
+	clonex := git.NewCtxSanitizedAt(cloneDir)
+	if strs.IsEmpty(branch) {
+		branch, err = clonex.GetCurrentBranch()
+		log.AssertNoErrorPanic(err, "Could not get branch of release clone.")
+	}
+
+	// Only consider versions on the branch.
+	deleteUnmergedTags(log, clonex)
+
+	var tag string
+	switch {
+	case strs.IsNotEmpty(args.UpdateTo):
+		tag = args.UpdateTo
+	case args.Update:
+		tag, _, err = updates.FindVersionTag(cloneDir, nil, args.UsePreRelease)
+		log.AssertNoErrorPanic(err, "Could not find the latest version.")
+		log.PanicIfF(strs.IsEmpty(tag), "Could not find any version on branch '%s'.", branch)
+	default:
+		tag = build.BuildTag
+	}
+
+	reachable, err := git.IsRefReachable(clonex, git.HEAD, tag)
+	log.PanicIfF(err != nil || !reachable,
+		"Version tag '%s' could not be found on branch '%s'.", tag, branch)
+
+	// Only bundle the branch up to the version and its tags.
+	err = clonex.Check("reset", "--hard", tag)
+	log.AssertNoErrorPanicF(err, "Could not reset release clone to '%s'.", tag)
+
+	deleteUnmergedTags(log, clonex)
+
+	err = clonex.Check("bundle", "create", path.Join(bundleDir, bundleReleaseFile), branch, "--tags")
+	log.AssertNoErrorPanic(err, "Could not bundle release clone.")
+
+	manifest.Tag = tag
+	manifest.CloneURL = url
+	manifest.CloneBranch = branch
+}
+
+// deleteUnmergedTags deletes all tags not reachable from `HEAD`.
+func deleteUnmergedTags(log cm.ILogContext, gitx *git.Context) {
+	tags, err := gitx.GetSplit("tag", "--no-merged", git.HEAD)
+	log.AssertNoErrorPanic(err, "Could not get tags of release clone.")
+
+	if len(tags) != 0 {
+		err = gitx.Check(append([]string{"tag", "-d"}, tags...)...)
+		log.AssertNoErrorPanic(err, "Could not delete tags in release clone.")
+	}
+}
+
+// bundleSharedRepos stores the shared repositories in the bundle
+// and returns the image references of all container images to bundle.
+func bundleSharedRepos(
+	log cm.ILogContext,
+	installDir string,
+	opts *bundleOptions,
+	workDir string,
+	bundleDir string,
+	manifest *BundleManifest) (imageRefs []string) {
+
+	for i, url := range opts.SharedRepos {
+		repo, err := hooks.ParseSharedURL(installDir, url)
+		log.AssertNoErrorPanicF(err, "Shared repository url '%s' is not valid.", url)
+		log.PanicIfF(!repo.IsCloned,
+			"Shared repository '%s' is a local repository and cannot be bundled.", url)
+
+		log.InfoF("Cloning shared repository '%s'...", url)
+		cloneDir := path.Join(workDir, "shared", strs.Fmt("%v", i))
+		err = git.Clone(cloneDir, repo.URL, repo.Branch, -1)
+		log.AssertNoErrorPanicF(err, "Could not clone shared repository '%s'.", url)
+
+		clonex := git.NewCtxSanitizedAt(cloneDir)
+		branch := repo.Branch
+		if strs.IsEmpty(branch) {
+			branch, err = clonex.GetCurrentBranch()
+			log.AssertNoErrorPanicF(err, "Could not get branch of shared repository '%s'.", url)
+		}
+
+		file := path.Join("shared", strs.Fmt("%v.bundle", i))
+		err = os.MkdirAll(path.Join(bundleDir, "shared"), cm.DefaultFileModeDirectory)
+		log.AssertNoErrorPanic(err, "Could not create directory in bundle.")
+		err = clonex.Check("bundle", "create", path.Join(bundleDir, file), branch)
+		log.AssertNoErrorPanicF(err, "Could not bundle shared repository '%s'.", url)
+
+		manifest.SharedRepos = append(manifest.SharedRepos,
+			BundleSharedRepo{URL: url, Branch: branch, Bundle: file})
+
+		if !opts.Images {
+			continue
+		}
+
+		hooksDir := hooks.GetSharedGithooksDir(cloneDir)
+		err = hooks.UpdateImages(log, url, cloneDir, hooksDir, "")
+		log.AssertNoErrorPanicF(err, "Could not build/pull images of shared repository '%s'.", url)
+
+		refs, err := hooks.GetImageReferences(hooksDir)
+		log.AssertNoErrorPanicF(err, "Could not get images of shared repository '%s'.", url)
+		imageRefs = append(imageRefs, refs...)
+	}
+
+	return
+}
+
+// loadBundleManifest loads the manifest of the extracted install bundle in `bundleDir`.
+func loadBundleManifest(log cm.ILogContext, bundleDir string) (manifest BundleManifest) {
+	file := path.Join(bundleDir, bundleManifestFile)
+
+	err := cm.LoadYAML(file, &manifest)
+	log.AssertNoErrorPanicF(err, "Could not load bundle manifest '%s'.", file)
+
+	log.PanicIfF(manifest.Version < 1 || manifest.Version > bundleManifestVersion,
+		"Bundle manifest '%s' has version '%v'. "+
+			"This version of Githooks only supports version >= 1 and <= '%v'.",
+		file, manifest.Version, bundleManifestVersion)
+
+	return
+}
+
+// runInstallFromBundle extracts the install bundle, verifies the bundled binaries,
+// sets up the release clone from it and dispatches to the installer in the bundle.
+func runInstallFromBundle(
+	log cm.ILogContext,
+	settings *Settings,
+	args Arguments,
+	cleanUpX *cm.InterruptContext) (bool, error) {
+
+	log.InfoF("Installing from bundle '%s'...", args.FromBundle)
+
+	bundleDir, err := os.MkdirTemp(os.TempDir(), "githooks-bundle-*")
+	log.AssertNoErrorPanic(err, "Can not create temporary bundle dir in '%s'", os.TempDir())
+	cleanUpX.AddHandler(func() {
+		_ = os.RemoveAll(bundleDir)
+	})
+	defer os.RemoveAll(bundleDir)
+
+	file, err := os.Open(args.FromBundle)
+	log.AssertNoErrorPanicF(err, "Could not open bundle '%s'.", args.FromBundle)
+	_, err = cm.ExtractTarGz(file, bundleDir)
+	file.Close()
+	log.AssertNoErrorPanicF(err, "Could not extract bundle '%s'.", args.FromBundle)
+
+	manifest := loadBundleManifest(log, bundleDir)
+
+	binDir := getBundleBinaryDir(bundleDir, download.GetRuntimePlatform())
+	archive, err := download.FindReleaseArchive(binDir)
+	log.AssertNoErrorPanicF(err,
+		"Bundle '%s' contains no binaries for platform '%s'.",
+		args.FromBundle, download.GetRuntimePlatform())
+
+	err = verifyArchive(log, &archive)
+	if err == nil {
+		err = archive.VerifyVersion(manifest.Tag)
+	}
+	log.AssertNoErrorPanicF(err, "Verification of bundle '%s' failed.", args.FromBundle)
+
+	err = archive.Extract(binDir)
+	log.AssertNoErrorPanicF(err, "Could not extract binaries from bundle '%s'.", args.FromBundle)
+
+	status := setupReleaseCloneFromBundle(log, settings.CloneDir, bundleDir, &manifest, args.UsePreRelease)
+	log.InfoF("Githooks update available: '%v'", status.IsUpdateAvailable)
+
+	ext := ""
+	if runtime.GOOS == cm.WindowsOsName {
+		ext = cm.WindowsExecutableSuffix
+	}
+
+	all := []string{
+		path.Join(binDir, "cli"+ext),
+		path.Join(binDir, "runner"+ext),
+		path.Join(binDir, "dialog"+ext)}
+
+	installer := hooks.GetInstallerExecutable(settings.InstallDir)
+	installer.Cmd = all[0]
+
+	// Set variables for further install procedure...
+	// Note: `args` is passed by value.
+	args.InternalPostDispatch = true
+	args.InternalBinaries = all
+	args.InternalBundleDir = bundleDir
+	if status.IsUpdateAvailable {
+		args.InternalUpdateFromVersion = build.BuildVersion
+		args.InternalUpdateTo = status.UpdateCommitSHA
+		args.UpdateTo = manifest.Tag // The bundled version can be older.
+	}
+
+	if DevIsDispatchSkipped {
+		return false, nil
+	}
+
+	log.PanicIfF(!cm.IsFile(installer.Cmd),
+		"Githooks executable '%s' is not existing.", installer.Cmd)
+
+	return true, dispatchToInstaller(log, &installer, &args)
+}
+
+// setupReleaseCloneFromBundle sets up the release clone `cloneDir`
+// from the release bundle and sets the update to the bundled version.
+func setupReleaseCloneFromBundle(
+	log cm.ILogContext,
+	cloneDir string,
+	bundleDir string,
+	manifest *BundleManifest,
+	usePreRelease bool) updates.ReleaseStatus {
+
+	bundle := path.Join(bundleDir, bundleReleaseFile)
+	gitx := git.NewCtxSanitizedAt(cloneDir)
+
+	isValid := false
+	if gitx.IsGitRepo() {
+		url, branch, e := gitx.GetRemoteURLAndBranch(updates.DefaultRemote)
+		isValid = e == nil && url == manifest.CloneURL && branch == manifest.CloneBranch
+	}
+
+	var err error
+	if isValid {
+		log.InfoF("Fetching version '%s' from bundle into release clone...", manifest.Tag)
+		err = gitx.Check("fetch", bundle,
+			strs.Fmt("+refs/heads/%[1]s:refs/remotes/%[2]s/%[1]s", manifest.CloneBranch, updates.DefaultRemote),
+			"+refs/tags/*:refs/tags/*")
+	} else {
+		log.InfoF("Cloning version '%s' from bundle into release clone...", manifest.Tag)
+		err = os.RemoveAll(cloneDir)
+		if err == nil {
+			err = git.Clone(cloneDir, bundle, manifest.CloneBranch, -1)
+		}
+		if err == nil {
+			err = gitx.Check("remote", "set-url", updates.DefaultRemote, manifest.CloneURL)
+		}
+	}
+	log.AssertNoErrorPanicF(err, "Could not set up release clone '%s' from bundle.", cloneDir)
+
+	err = updates.SetCloneURL(manifest.CloneURL, manifest.CloneBranch)
+	log.AssertNoErrorPanic(err, "Could not set clone url and branch.")
+
+	status, err := updates.GetStatus(cloneDir, false, usePreRelease, nil)
+	log.AssertNoErrorPanicF(err, "Could not get status of release clone '%s'.", cloneDir)
+
+	err = updates.SetUpdateTo(cloneDir, &status, manifest.Tag)
+	log.AssertNoErrorPanicF(err, "Could not update to version '%s'.", manifest.Tag)
+
+	return status
+}
+
+// installBundleContent installs the shared repositories and container
+// images of the extracted install bundle and enables the offline mode.
+func installBundleContent(
+	log cm.ILogContext,
+	gitx *git.Context,
+	installDir string,
+	args *Arguments) {
+
+	manifest := loadBundleManifest(log, args.InternalBundleDir)
+
+	urls := make([]string, 0, len(manifest.SharedRepos))
+	for i := range manifest.SharedRepos {
+		urls = append(urls, manifest.SharedRepos[i].URL)
+	}
+
+	if len(args.SharedRepos) == 0 {
+		args.SharedRepos = urls
+	}
+
+	if args.DryRun {
+		log.InfoF("[dry run] Would install bundled shared repositories:\n%s\n"+
+			"and enable the offline mode.",
+			strings.Join(strs.Map(urls, func(s string) string {
+				return strs.Fmt(" %s '%s'", cm.ListItemLiteral, s)
+			}), "\n"))
+
+		return
+	}
+
+	for _, shared := range manifest.SharedRepos {
+		repo, err := hooks.ParseSharedURL(installDir, shared.URL)
+		log.AssertNoErrorPanicF(err, "Shared repository url '%s' is not valid.", shared.URL)
+
+		log.InfoF("Installing bundled shared repository '%s'...", shared.URL)
+		err = os.RemoveAll(repo.RepositoryDir)
+		if err == nil {
+			err = git.Clone(repo.RepositoryDir,
+				path.Join(args.InternalBundleDir, shared.Bundle), shared.Branch, -1)
+		}
+		if err == nil {
+			err = git.NewCtxSanitizedAt(repo.RepositoryDir).Check(
+				"remote", "set-url", updates.DefaultRemote, repo.URL)
+		}
+		log.AssertNoErrorF(err, "Could not install bundled shared repository '%s'.", shared.URL)
+	}
+
+	if strs.IsNotEmpty(manifest.Images) {
+		manager := args.ContainerManager
+		if strs.IsEmpty(manager) {
+			manager = gitx.GetConfig(hooks.GitCKContainerManager, git.GlobalScope)
+		}
+
+		mgr, err := container.NewManager(manager)
+		if err == nil {
+			log.Info("Loading bundled container images...")
+			err = mgr.ImageLoad(path.Join(args.InternalBundleDir, manifest.Images))
+		}
+		log.AssertNoErrorF(err, "Could not load bundled container images.")
+	}
+
+	err := hooks.SetOfflineMode(gitx, true, false, git.GlobalScope)
+	log.AssertNoErrorF(err, "Could not enable the offline mode.")
+	log.Info("Enabled the offline mode. Disable it when online with:\n" +
+		"  $ git hooks config offline --disable --global")
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path"
 	"runtime"
+	"strings"
 )
 
 // IsRunningCoverage returns if we are running coverage.
@@ -27,10 +28,13 @@ func downloadBinaries(
 	log cm.ILogContext,
 	deploySettings download.IDeploySettings,
 	tempDir string,
-	versionTag string) updates.Binaries {
+	versionTag string,
+	platform download.Platform) updates.Binaries {
 
 	bin := os.Getenv("GH_TEST_BIN")
 	cm.PanicIf(strs.IsEmpty(bin), "GH_TEST_BIN undefined")
+	cm.PanicIfF(platform != download.GetRuntimePlatform(),
+		"Faking download only works for platform '%s'.", download.GetRuntimePlatform())
 
 	log.InfoF("Faking download: taking from '%s'.", bin)
 
@@ -52,3 +56,49 @@ func downloadBinaries(
 
 	return updates.Binaries{All: all, Cli: all[0], Others: all[1:]}
 }
+
+func downloadArchive(
+	log cm.ILogContext,
+	deploySettings download.IDeploySettings,
+	dir string,
+	versionTag string,
+	platform download.Platform) {
+
+	tempDir, err := os.MkdirTemp(os.TempDir(), "githooks-archive-*")
+	cm.AssertNoErrorPanic(err, "Could not create temporary dir.")
+	defer os.RemoveAll(tempDir)
+
+	downloadBinaries(log, deploySettings, tempDir, versionTag, platform)
+
+	log.InfoF("Faking release archive in '%s'.", dir)
+
+	err = os.MkdirAll(dir, cm.DefaultFileModeDirectory)
+	cm.AssertNoErrorPanicF(err, "Could not create dir '%s'.", dir)
+
+	archive := download.NewReleaseArchive(dir, ".tar.gz")
+	file, err := os.Create(archive.File)
+	cm.AssertNoErrorPanicF(err, "Could not create '%s'.", archive.File)
+	err = cm.CreateTarGz(tempDir, file)
+	file.Close()
+	cm.AssertNoErrorPanicF(err, "Could not write '%s'.", archive.File)
+
+	file, err = os.Open(archive.File)
+	cm.AssertNoErrorPanicF(err, "Could not open '%s'.", archive.File)
+	hash, err := cm.GetSHA256Hash(file)
+	file.Close()
+	cm.AssertNoErrorPanicF(err, "Could not hash '%s'.", archive.File)
+
+	err = os.WriteFile(archive.Checksums,
+		[]byte(strs.Fmt("%s  githooks-%s-%s.%s.tar.gz\n",
+			hash, strings.TrimPrefix(versionTag, "v"), platform.OS, platform.Arch)), cm.DefaultFileModeFile)
+	if err == nil {
+		err = os.WriteFile(archive.ChecksumsSignature, []byte("fake"), cm.DefaultFileModeFile)
+	}
+	cm.AssertNoErrorPanic(err, "Could not write checksum files.")
+}
+
+func verifyArchive(log cm.ILogContext, archive *download.ReleaseArchive) error {
+	log.InfoF("Faking signature verification of '%s'.", archive.Checksums)
+
+	return archive.VerifyChecksum()
+}
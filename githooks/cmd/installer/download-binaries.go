@@ -5,7 +5,6 @@ package installer
 import (
 	"net/url"
 	"path"
-	"runtime"
 	"strings"
 
 	"github.com/gabyx/githooks/githooks/build"
@@ -106,16 +105,17 @@ func downloadBinaries(
 	log cm.ILogContext,
 	deploySettings download.IDeploySettings,
 	tempDir string,
-	versionTag string) updates.Binaries {
+	versionTag string,
+	platform download.Platform) updates.Binaries {
 
 	log.PanicIfF(deploySettings == nil,
 		"Could not determine deploy settings.")
 
-	err := deploySettings.Download(log, versionTag, tempDir)
-	log.AssertNoErrorPanicF(err, "Could not download binaries.")
+	err := deploySettings.Download(log, versionTag, platform, tempDir)
+	log.AssertNoErrorPanicF(err, "Could not download binaries for platform '%s'.", platform)
 
 	ext := ""
-	if runtime.GOOS == cm.WindowsOsName {
+	if platform.OS == cm.WindowsOsName {
 		ext = cm.WindowsExecutableSuffix
 	}
 
@@ -126,3 +126,32 @@ func downloadBinaries(
 
 	return updates.Binaries{All: all, Cli: all[0], Others: all[1:]}
 }
+
+// downloadArchive downloads the verified release archive of `versionTag`
+// for `platform` together with its signed checksum file into `dir`.
+func downloadArchive(
+	log cm.ILogContext,
+	deploySettings download.IDeploySettings,
+	dir string,
+	versionTag string,
+	platform download.Platform) {
+
+	log.PanicIfF(deploySettings == nil,
+		"Could not determine deploy settings.")
+
+	_, err := deploySettings.DownloadArchive(log, versionTag, platform, dir)
+	log.AssertNoErrorPanicF(err, "Could not download release archive for platform '%s'.", platform)
+}
+
+// verifyArchive verifies the release archive `archive`
+// with the embedded deploy PGP.
+func verifyArchive(log cm.ILogContext, archive *download.ReleaseArchive) error {
+	publicPGP, err := build.Asset("embedded/.deploy-pgp")
+	if err != nil {
+		return cm.CombineErrors(err, cm.ErrorF("Could not get embedded deploy PGP."))
+	}
+
+	log.InfoF("Verify signature of checksum file '%s' and validate checksums.", archive.Checksums)
+
+	return archive.Verify(string(publicPGP))
+}
@@ -0,0 +1,268 @@
+package installer
+
+import (
+	"path"
+	"path/filepath"
+
+	"github.com/gabyx/githooks/githooks/cmd/common/install"
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/container"
+	"github.com/gabyx/githooks/githooks/hooks"
+	strs "github.com/gabyx/githooks/githooks/strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/spf13/cobra"
+)
+
+// InstallConfigUpdate are the update settings in the install configuration.
+type InstallConfigUpdate struct {
+	// If the installer directly updates to the latest version (see `--update`).
+	OnInstall bool `yaml:"onInstall"`
+
+	// If automatic update checks are enabled.
+	// The installer prompts for it if not set.
+	AutoUpdateEnabled *bool `yaml:"autoUpdateEnabled,omitempty"`
+
+	// If pre-release versions are considered for updates.
+	UsePreRelease bool `yaml:"usePreRelease"`
+
+	// The clone URL and branch from which Githooks updates itself.
+	CloneURL    string `yaml:"cloneURL"`
+	CloneBranch string `yaml:"cloneBranch"`
+}
+
+// InstallConfigContainers are the container settings in the install configuration.
+type InstallConfigContainers struct {
+	// If hooks are run containerized.
+	// Left unchanged if not set.
+	Enabled *bool `yaml:"enabled,omitempty"`
+
+	// The container manager to use, e.g. `docker`.
+	Manager string `yaml:"manager"`
+}
+
+// InstallConfigDeploy are the deploy settings in the install configuration.
+type InstallConfigDeploy struct {
+	// The deploy api type, e.g. `github` or `gitea`.
+	API string `yaml:"api"`
+
+	// The deploy settings file.
+	Settings string `yaml:"settings"`
+
+	// If the binaries are built from source with build tags `BuildTags`.
+	BuildFromSource bool     `yaml:"buildFromSource"`
+	BuildTags       []string `yaml:"buildTags"`
+}
+
+// InstallConfig is the declarative configuration file
+// of the installer given by `--install-config`.
+type InstallConfig struct {
+	// The install mode: `template-dir`, `core-hooks-path` or `manual`.
+	InstallMode string `yaml:"installMode"`
+
+	// The install prefix and the template directory to use.
+	Prefix      string `yaml:"prefix"`
+	TemplateDir string `yaml:"templateDir"`
+
+	// The hooks maintained by Githooks (see `--maintained-hooks`).
+	MaintainedHooks []string `yaml:"maintainedHooks"`
+
+	// If the installation into existing repositories is skipped.
+	SkipInstallIntoExisting bool `yaml:"skipInstallIntoExisting"`
+
+	// The global shared repositories to configure.
+	SharedRepos []string `yaml:"sharedRepos"`
+
+	Update     InstallConfigUpdate     `yaml:"update"`
+	Containers InstallConfigContainers `yaml:"containers"`
+	Deploy     InstallConfigDeploy     `yaml:"deploy"`
+
+	// The version of the file.
+	Version int `yaml:"version"`
+}
+
+// Version for InstallConfig.
+// Version 1: Initial.
+const installConfigVersion int = 1
+
+// The supported deploy api types.
+var deployAPIs = []string{"github", "gitea"}
+
+// LoadInstallConfig loads the install configuration `file`
+// and fails on unknown fields.
+// Relative paths in the configuration are relative to the directory of `file`.
+func LoadInstallConfig(file string) (config InstallConfig, err error) {
+	if !cm.IsFile(file) {
+		return config, cm.ErrorF("Install configuration '%s' does not exist.", file)
+	}
+
+	if err = cm.LoadYAMLStrict(file, &config); err != nil {
+		return InstallConfig{}, cm.CombineErrors(cm.ErrorF("Could not load install configuration '%s'.", file), err)
+	}
+
+	if config.Version < 1 || config.Version > installConfigVersion {
+		return InstallConfig{}, cm.ErrorF(
+			"File '%s' has version '%v'. "+
+				"This version of Githooks only supports version >= 1 and <= '%v'.",
+			file, config.Version, installConfigVersion)
+	}
+
+	dir := path.Dir(filepath.ToSlash(file))
+	makeAbs := func(p *string) {
+		if strs.IsNotEmpty(*p) && !filepath.IsAbs(*p) && (*p)[0] != '~' {
+			*p = path.Join(dir, filepath.ToSlash(*p))
+		}
+	}
+
+	makeAbs(&config.Prefix)
+	makeAbs(&config.TemplateDir)
+	makeAbs(&config.Deploy.Settings)
+
+	if err = config.validate(); err != nil {
+		return InstallConfig{}, cm.CombineErrors(cm.ErrorF("Install configuration '%s' is invalid.", file), err)
+	}
+
+	return
+}
+
+// validate validates all values of the install configuration.
+func (c *InstallConfig) validate() (err error) {
+	add := func(format string, args ...interface{}) {
+		err = cm.CombineErrors(err, cm.ErrorF(format, args...))
+	}
+
+	switch c.InstallMode {
+	case "",
+		install.GetInstallModeName(install.InstallModeTypeV.TemplateDir),
+		install.GetInstallModeName(install.InstallModeTypeV.CoreHooksPath),
+		install.GetInstallModeName(install.InstallModeTypeV.Manual),
+		install.GetInstallModeName(install.InstallModeTypeV.RepoHooksPath):
+	default:
+		add("Install mode '%s' in 'installMode' is not one of "+
+			"'template-dir', 'core-hooks-path', 'manual' or 'repo-hooks-path'.", c.InstallMode)
+	}
+
+	if _, e := hooks.CheckHookNames(c.MaintainedHooks); e != nil {
+		err = cm.CombineErrors(err, cm.ErrorF("Hook names in 'maintainedHooks' are not valid."), e)
+	}
+
+	seen := strs.NewStringSet(len(c.SharedRepos))
+	for i, url := range c.SharedRepos {
+		switch {
+		case strs.IsEmpty(url):
+			add("Shared repository entry '%v' in 'sharedRepos' is empty.", i)
+		case seen.Exists(url):
+			add("Shared repository '%s' in 'sharedRepos' is given twice.", url)
+		}
+		seen.Insert(url)
+	}
+
+	if !container.IsManagerSupported(c.Containers.Manager) {
+		add("Container manager '%s' in 'containers.manager' is not supported.", c.Containers.Manager)
+	}
+
+	if strs.IsNotEmpty(c.Deploy.API) && !strs.Includes(deployAPIs, c.Deploy.API) {
+		add("Deploy api '%s' in 'deploy.api' is not one of 'github' or 'gitea'.", c.Deploy.API)
+	}
+
+	if strs.IsNotEmpty(c.Deploy.Settings) && !cm.IsFile(c.Deploy.Settings) {
+		add("Deploy settings file '%s' in 'deploy.settings' does not exist.", c.Deploy.Settings)
+	}
+
+	return
+}
+
+// applyInstallConfig applies the install configuration `config`
+// to all arguments `args` which are not given on the command line.
+func applyInstallConfig(cmd *cobra.Command, config *InstallConfig, args *Arguments) {
+	isSet := func(flag string) bool {
+		return cmd.PersistentFlags().Changed(flag)
+	}
+
+	if strs.IsNotEmpty(config.InstallMode) &&
+		!isSet("use-core-hookspath") && !isSet("use-manual") && !isSet("use-repo-hookspath") {
+		args.UseCoreHooksPath = config.InstallMode ==
+			install.GetInstallModeName(install.InstallModeTypeV.CoreHooksPath)
+		args.UseManual = config.InstallMode ==
+			install.GetInstallModeName(install.InstallModeTypeV.Manual)
+		args.UseRepoHooksPath = config.InstallMode ==
+			install.GetInstallModeName(install.InstallModeTypeV.RepoHooksPath)
+	}
+
+	setString := func(flag string, value string, arg *string) {
+		if strs.IsNotEmpty(value) && !isSet(flag) {
+			*arg = value
+		}
+	}
+
+	setBool := func(flag string, value bool, arg *bool) {
+		if value && !isSet(flag) {
+			*arg = value
+		}
+	}
+
+	setList := func(flag string, value []string, arg *[]string) {
+		if len(value) != 0 && !isSet(flag) {
+			*arg = value
+		}
+	}
+
+	setString("prefix", config.Prefix, &args.InstallPrefix)
+	setString("template-dir", config.TemplateDir, &args.TemplateDir)
+	setList("maintained-hooks", config.MaintainedHooks, &args.MaintainedHooks)
+	setBool("skip-install-into-existing", config.SkipInstallIntoExisting, &args.SkipInstallIntoExisting)
+
+	setBool("update", config.Update.OnInstall, &args.Update)
+	setBool("use-pre-release", config.Update.UsePreRelease, &args.UsePreRelease)
+	setString("clone-url", config.Update.CloneURL, &args.CloneURL)
+	setString("clone-branch", config.Update.CloneBranch, &args.CloneBranch)
+
+	setString("deploy-api", config.Deploy.API, &args.DeployAPI)
+	setString("deploy-settings", config.Deploy.Settings, &args.DeploySettings)
+	setBool("build-from-source", config.Deploy.BuildFromSource, &args.BuildFromSource)
+	setList("build-tags", config.Deploy.BuildTags, &args.BuildTags)
+
+	args.SharedRepos = config.SharedRepos
+	args.AutoUpdateEnabled = config.Update.AutoUpdateEnabled
+	args.ContainerizedHooksEnabled = config.Containers.Enabled
+	args.ContainerManager = config.Containers.Manager
+}
+
+// newEffectiveInstallConfig gets the effective install configuration from the arguments `args`.
+func newEffectiveInstallConfig(args *Arguments) InstallConfig {
+	return InstallConfig{
+		InstallMode: install.GetInstallModeName(
+			install.MapInstallerArgsToInstallMode(
+				args.UseCoreHooksPath, args.UseManual, args.UseRepoHooksPath)),
+		Prefix:                  args.InstallPrefix,
+		TemplateDir:             args.TemplateDir,
+		MaintainedHooks:         args.MaintainedHooks,
+		SkipInstallIntoExisting: args.SkipInstallIntoExisting,
+		SharedRepos:             args.SharedRepos,
+		Update: InstallConfigUpdate{
+			OnInstall:         args.Update,
+			AutoUpdateEnabled: args.AutoUpdateEnabled,
+			UsePreRelease:     args.UsePreRelease,
+			CloneURL:          args.CloneURL,
+			CloneBranch:       args.CloneBranch},
+		Containers: InstallConfigContainers{
+			Enabled: args.ContainerizedHooksEnabled,
+			Manager: args.ContainerManager},
+		Deploy: InstallConfigDeploy{
+			API:             args.DeployAPI,
+			Settings:        args.DeploySettings,
+			BuildFromSource: args.BuildFromSource,
+			BuildTags:       args.BuildTags},
+		Version: installConfigVersion}
+}
+
+// printInstallConfig prints the effective install configuration as YAML.
+func printInstallConfig(log cm.ILogContext, args *Arguments) {
+	config := newEffectiveInstallConfig(args)
+
+	data, err := yaml.Marshal(&config)
+	log.AssertNoErrorPanicF(err, "Could not serialize install configuration.")
+
+	_, err = log.GetInfoWriter().Write(data)
+	log.AssertNoErrorF(err, "Could not write output.")
+}
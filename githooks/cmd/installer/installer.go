@@ -19,6 +19,7 @@ import (
 	"github.com/gabyx/githooks/githooks/updates"
 	"github.com/gabyx/githooks/githooks/updates/download"
 
+	"github.com/hashicorp/go-version"
 	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -47,6 +48,8 @@ See further information at https://github.com/gabyx/githooks/blob/main/README.md
 
 	defineArguments(cmd, vi)
 
+	cmd.AddCommand(newBundleCmd(ctx, vi))
+
 	return ccm.SetCommandDefaults(ctx.Log, cmd)
 }
 
@@ -101,6 +104,10 @@ func defineArguments(cmd *cobra.Command, vi *viper.Viper) {
 		"update", false,
 		"Install and update directly to the latest\n"+
 			"possible tag on the clone branch.")
+	cmd.PersistentFlags().String(
+		"update-to", "",
+		"Install and update or downgrade directly to the\n"+
+			"version tag (e.g. 'v2.5.0') on the clone branch.")
 	cmd.PersistentFlags().Bool(
 		"skip-install-into-existing", false,
 		"Skip installation into existing repositories\n"+
@@ -125,6 +132,11 @@ func defineArguments(cmd *cobra.Command, vi *viper.Viper) {
 	cmd.PersistentFlags().Bool(
 		"use-manual", false,
 		"If the install mode 'manual' should be used.")
+	cmd.PersistentFlags().Bool(
+		"use-repo-hookspath", false,
+		"If the install mode 'repo-hooks-path' should be used:\n"+
+			"Repositories get Githooks only with 'git hooks install' which\n"+
+			"sets a local 'core.hooksPath'.")
 
 	cmd.PersistentFlags().String(
 		"clone-url", "",
@@ -160,6 +172,27 @@ func defineArguments(cmd *cobra.Command, vi *viper.Viper) {
 		"use-pre-release", false,
 		"When fetching the latest installer, also consider pre-release versions.")
 
+	cmd.PersistentFlags().String(
+		"update-channel", "",
+		"The update channel (e.g. 'stable', 'beta') to use for updates.\n"+
+			"Channels are defined in the deploy settings.")
+
+	cmd.PersistentFlags().String(
+		"install-config", "",
+		"The declarative install configuration YAML file.\n"+
+			"Arguments given on the command line take precedence.\n"+
+			"See the documentation for further details.")
+	cm.AssertNoErrorPanic(cmd.MarkPersistentFlagFilename("install-config"))
+	cmd.PersistentFlags().Bool(
+		"print-config", false,
+		"Print the effective install configuration and exit.")
+
+	cmd.PersistentFlags().String(
+		"from-bundle", "",
+		"Install offline from the install bundle created with\n"+
+			"'git hooks installer bundle'. This enables the offline mode.")
+	cm.AssertNoErrorPanic(cmd.MarkPersistentFlagFilename("from-bundle"))
+
 	cm.AssertNoErrorPanic(
 		vi.BindPFlag("config", cmd.PersistentFlags().Lookup("config")))
 	cm.AssertNoErrorPanic(
@@ -172,6 +205,8 @@ func defineArguments(cmd *cobra.Command, vi *viper.Viper) {
 		vi.BindPFlag("nonInteractive", cmd.PersistentFlags().Lookup("non-interactive")))
 	cm.AssertNoErrorPanic(
 		vi.BindPFlag("update", cmd.PersistentFlags().Lookup("update")))
+	cm.AssertNoErrorPanic(
+		vi.BindPFlag("updateTo", cmd.PersistentFlags().Lookup("update-to")))
 	cm.AssertNoErrorPanic(
 		vi.BindPFlag("skipInstallIntoExisting", cmd.PersistentFlags().Lookup("skip-install-into-existing")))
 	cm.AssertNoErrorPanic(
@@ -180,6 +215,8 @@ func defineArguments(cmd *cobra.Command, vi *viper.Viper) {
 		vi.BindPFlag("useCoreHooksPath", cmd.PersistentFlags().Lookup("use-core-hookspath")))
 	cm.AssertNoErrorPanic(
 		vi.BindPFlag("useManual", cmd.PersistentFlags().Lookup("use-manual")))
+	cm.AssertNoErrorPanic(
+		vi.BindPFlag("useRepoHooksPath", cmd.PersistentFlags().Lookup("use-repo-hookspath")))
 	cm.AssertNoErrorPanic(
 		vi.BindPFlag("cloneURL", cmd.PersistentFlags().Lookup("clone-url")))
 	cm.AssertNoErrorPanic(
@@ -194,10 +231,18 @@ func defineArguments(cmd *cobra.Command, vi *viper.Viper) {
 		vi.BindPFlag("buildTags", cmd.PersistentFlags().Lookup("build-tags")))
 	cm.AssertNoErrorPanic(
 		vi.BindPFlag("usePreRelease", cmd.PersistentFlags().Lookup("use-pre-release")))
+	cm.AssertNoErrorPanic(
+		vi.BindPFlag("updateChannel", cmd.PersistentFlags().Lookup("update-channel")))
 	cm.AssertNoErrorPanic(
 		vi.BindPFlag("installPrefix", cmd.PersistentFlags().Lookup("prefix")))
 	cm.AssertNoErrorPanic(
 		vi.BindPFlag("templateDir", cmd.PersistentFlags().Lookup("template-dir")))
+	cm.AssertNoErrorPanic(
+		vi.BindPFlag("installConfig", cmd.PersistentFlags().Lookup("install-config")))
+	cm.AssertNoErrorPanic(
+		vi.BindPFlag("printConfig", cmd.PersistentFlags().Lookup("print-config")))
+	cm.AssertNoErrorPanic(
+		vi.BindPFlag("fromBundle", cmd.PersistentFlags().Lookup("from-bundle")))
 
 	setupMockFlags(cmd, vi)
 }
@@ -210,6 +255,14 @@ func validateArgs(log cm.ILogContext, cmd *cobra.Command, args *Arguments) {
 			"Flag '%s' needs an non-empty value.", f.Name)
 	})
 
+	// Apply the install configuration, which is already
+	// applied if we are in the dispatched installer.
+	if strs.IsNotEmpty(args.InstallConfig) && !args.InternalPostDispatch {
+		config, err := LoadInstallConfig(args.InstallConfig)
+		log.AssertNoErrorPanic(err, "Could not apply install configuration.")
+		applyInstallConfig(cmd, &config, args)
+	}
+
 	// Check deploy-settings and deploy-api are not given together.
 	log.PanicIfF(strs.IsNotEmpty(args.DeployAPI) &&
 		strs.IsNotEmpty(args.DeploySettings),
@@ -221,6 +274,23 @@ func validateArgs(log cm.ILogContext, cmd *cobra.Command, args *Arguments) {
 		"You cannot build binaries from source together with specifying\n",
 		"a deploy settings file or deploy api.")
 
+	log.PanicIfF(args.Update && strs.IsNotEmpty(args.UpdateTo),
+		"You cannot specify '--update' together with '--update-to'.")
+
+	if strs.IsNotEmpty(args.FromBundle) && !args.InternalPostDispatch {
+		log.PanicIfF(!cm.IsFile(args.FromBundle), "Install bundle '%s' does not exist.", args.FromBundle)
+		log.PanicIfF(strs.IsNotEmpty(args.CloneURL) || strs.IsNotEmpty(args.CloneBranch) ||
+			strs.IsNotEmpty(args.DeployAPI) || strs.IsNotEmpty(args.DeploySettings) ||
+			args.BuildFromSource || args.Update || strs.IsNotEmpty(args.UpdateTo),
+			"You cannot specify '--from-bundle' together with a clone url or branch,\n"+
+				"deploy settings, building from source or updating.")
+	}
+
+	if strs.IsNotEmpty(args.UpdateTo) {
+		_, err := version.NewVersion(args.UpdateTo)
+		log.AssertNoErrorPanicF(err, "Update version '%s' is not a valid version tag.", args.UpdateTo)
+	}
+
 	var err error
 	args.MaintainedHooks, err = hooks.CheckHookNames(args.MaintainedHooks)
 	log.AssertNoErrorPanic(err,
@@ -357,10 +427,32 @@ func getDeploySettings(
 	cloneURL string,
 	args *Arguments) download.IDeploySettings {
 
+	deploySettings := loadDeploySettings(log, installDir, cloneURL, args)
+
+	// Keep the update channels.
+	channels := loadUpdateChannels(log, installDir, args)
+
+	if deploySettings != nil {
+		installDeploySettings := download.GetDeploySettingsFile(installDir)
+		err := download.StoreDeploySettings(installDeploySettings, deploySettings, channels)
+		log.AssertNoErrorPanicF(err, "Could not store deploy settings '%s'.", installDeploySettings)
+	}
+
+	return deploySettings
+}
+
+// loadDeploySettings loads the deploy settings given by the arguments `args`
+// or from the install directory `installDir`. Otherwise they are detected
+// from the clone url `cloneURL`.
+func loadDeploySettings(
+	log cm.ILogContext,
+	installDir string,
+	cloneURL string,
+	args *Arguments) download.IDeploySettings {
+
 	var err error
 	var deploySettings download.IDeploySettings
 
-	installDeploySettings := download.GetDeploySettingsFile(installDir)
 	fileToLoad := ""
 	switch {
 	case strs.IsNotEmpty(args.DeploySettings):
@@ -370,7 +462,7 @@ func getDeploySettings(
 	case strs.IsEmpty(args.DeployAPI):
 		// If the user did not specify a deploy api type,
 		// load the deploy settings from install dir.
-		fileToLoad = installDeploySettings
+		fileToLoad = download.GetDeploySettingsFile(installDir)
 	}
 
 	if cm.IsFile(fileToLoad) {
@@ -384,12 +476,49 @@ func getDeploySettings(
 		log.AssertNoErrorF(err, "Could not auto-detect deploy settings.")
 	}
 
-	if deploySettings != nil {
-		err := download.StoreDeploySettings(installDeploySettings, deploySettings)
-		log.AssertNoErrorPanicF(err, "Could not store deploy settings '%s'.", installDeploySettings)
+	return deploySettings
+}
+
+// loadUpdateChannels loads the update channels of the deploy settings.
+func loadUpdateChannels(log cm.ILogContext, installDir string, args *Arguments) *download.UpdateChannels {
+	file := getDeploySettingsFile(installDir, args)
+	if !cm.IsFile(file) {
+		return nil
 	}
 
-	return deploySettings
+	channels, err := download.LoadUpdateChannels(file)
+	log.AssertNoErrorPanicF(err, "Could not load update channels '%s'.", file)
+
+	return channels
+}
+
+// getDeploySettingsFile gets the deploy settings file which defines
+// the update channels and the release index.
+func getDeploySettingsFile(installDir string, args *Arguments) string {
+	if strs.IsNotEmpty(args.DeploySettings) {
+		return args.DeploySettings
+	}
+
+	return download.GetDeploySettingsFile(installDir)
+}
+
+// loadUpdateChannel loads the update channel to use for updates.
+func loadUpdateChannel(
+	log cm.ILogContext,
+	gitx *git.Context,
+	installDir string,
+	args *Arguments) *updates.UpdateChannel {
+
+	file := getDeploySettingsFile(installDir, args)
+
+	channel, err := updates.LoadUpdateChannel(gitx, file, args.UpdateChannel)
+	log.AssertNoErrorPanicF(err, "Could not load update channel.")
+
+	if channel != nil {
+		log.InfoF("Using update channel '%s'.", channel.Name)
+	}
+
+	return channel
 }
 
 func runInstallDispatched(
@@ -404,10 +533,16 @@ func runInstallDispatched(
 
 	log.Info("Running dispatched installer.")
 
-	if args.InternalAutoUpdate {
+	if strs.IsNotEmpty(args.FromBundle) {
+		return runInstallFromBundle(log, settings, args, cleanUpX)
+	}
+
+	channel := loadUpdateChannel(log, gitx, settings.InstallDir, &args)
+
+	if args.InternalAutoUpdate && strs.IsEmpty(args.UpdateTo) {
 		log.Info("Executing auto update...")
 
-		status, err = updates.GetStatus(settings.CloneDir, true, args.UsePreRelease)
+		status, err = updates.GetStatus(settings.CloneDir, true, args.UsePreRelease, channel)
 		log.AssertNoErrorPanic(err,
 			"Could not get status of release clone '%s'",
 			settings.CloneDir)
@@ -425,12 +560,25 @@ func runInstallDispatched(
 			build.BuildTag,
 			true,
 			updates.RecloneOnWrongRemote,
-			args.UsePreRelease)
+			args.UsePreRelease,
+			channel)
 
 		log.AssertNoErrorPanicF(err,
 			"Could not assert release clone '%s' existing",
 			settings.CloneDir)
 
+		if strs.IsNotEmpty(args.UpdateTo) {
+			log.InfoF("Setting update to version '%s'...", args.UpdateTo)
+			err = updates.SetUpdateTo(settings.CloneDir, &status, args.UpdateTo)
+			log.AssertNoErrorPanicF(err, "Could not update to version '%s'.", args.UpdateTo)
+		} else {
+			err = updates.RestrictToVersionIndex(
+				settings.CloneDir, &status,
+				getDeploySettingsFile(settings.InstallDir, &args),
+				args.UsePreRelease)
+			log.AssertNoErrorPanicF(err, "Could not check published versions.")
+		}
+
 		log.DebugF("Status: %v", status)
 	}
 
@@ -441,7 +589,8 @@ func runInstallDispatched(
 	log.InfoF("Githooks installer existing: '%v'", haveInstaller)
 
 	// We download/build the binaries always.
-	doUpdate := status.IsUpdateAvailable && (args.Update || args.InternalAutoUpdate)
+	doUpdate := status.IsUpdateAvailable &&
+		(args.Update || args.InternalAutoUpdate || strs.IsNotEmpty(args.UpdateTo))
 	tag := ""
 	commit := ""
 
@@ -484,7 +633,7 @@ func runInstallDispatched(
 		log.InfoF("Download '%s' from deploy source...", tag)
 
 		deploySettings := getDeploySettings(log, settings.InstallDir, status.RemoteURL, &args)
-		binaries = downloadBinaries(log, deploySettings, tempDir, tag)
+		binaries = downloadBinaries(log, deploySettings, tempDir, tag, download.GetRuntimePlatform())
 	}
 
 	installer.Cmd = binaries.Cli
@@ -528,6 +677,23 @@ func dispatchToInstaller(log cm.ILogContext, installer cm.IExecutable, args *Arg
 		"--config", file.Name())
 }
 
+// RunRestoredInstaller runs the installer restored by a rollback in the
+// install directory `installDir` non-interactively to reinstall the hook templates
+// and the run-wrappers in registered repositories of the restored version.
+func RunRestoredInstaller(log cm.ILogContext, installDir string) error {
+	installer := hooks.GetInstallerExecutable(installDir)
+
+	// Mark it as an update to reuse the installed install mode.
+	args := Arguments{
+		NonInteractive:            true,
+		InternalAutoUpdate:        true,
+		InternalPostDispatch:      true,
+		InternalUpdateFromVersion: build.BuildVersion,
+		SkipInstallIntoExisting:   true}
+
+	return dispatchToInstaller(log, &installer, &args)
+}
+
 // findHookTemplateDir returns the Git hook template directory
 // and optional a Git template dir which gets only set in case of
 // not using the core.hooksPath method.
@@ -557,14 +723,17 @@ func findHookTemplateDir(
 			"You seem to have install mode '%s' but the corresponding\n"+
 			"hook templates directory is not found:\n"+
 			" - For '%s', is '%s' unset?\n"+
+			" - For '%s', is '%s' unset?\n"+
 			" - For '%s', is '%s' unset?",
 		install.GetInstallModeName(installMode),
 		install.GetInstallModeName(install.InstallModeTypeV.TemplateDir), git.GitCKInitTemplateDir,
-		install.GetInstallModeName(install.InstallModeTypeV.CoreHooksPath), git.GitCKCoreHooksPath)
+		install.GetInstallModeName(install.InstallModeTypeV.CoreHooksPath), git.GitCKCoreHooksPath,
+		install.GetInstallModeName(install.InstallModeTypeV.RepoHooksPath), hooks.GitCKPathForUseRepoHooksPath)
 
 	// 4. No folder found: Try setup a new folder.
 	if nonInteractive ||
 		installMode == install.InstallModeTypeV.CoreHooksPath ||
+		installMode == install.InstallModeTypeV.RepoHooksPath ||
 		installMode == install.InstallModeTypeV.Manual {
 		templateDir := setupNewTemplateDir(log, installDir, nil)
 		return path.Join(templateDir, "hooks") // nolint:nlreturn
@@ -838,6 +1007,34 @@ func setDirectoryForInstallMode(
 				"the Githooks installation without the '--use-core-hookspath'\n"+
 				"parameter.")
 
+	case install.InstallModeTypeV.RepoHooksPath:
+
+		log.InfoF("%s '%s' to '%s'.", prefix, hooks.GitCKPathForUseRepoHooksPath, directory)
+
+		if !dryRun {
+			err := gitx.SetConfig(hooks.GitCKUseCoreHooksPath, false, git.GlobalScope)
+			log.AssertNoErrorPanic(err, "Could not set Git config value.")
+
+			err = gitx.SetConfig(hooks.GitCKUseManual, false, git.GlobalScope)
+			log.AssertNoErrorPanic(err, "Could not set Git config value.")
+
+			err = gitx.SetConfig(hooks.GitCKUseRepoHooksPath, true, git.GlobalScope)
+			log.AssertNoErrorPanic(err, "Could not set Git config value.")
+
+			err = gitx.SetConfig(hooks.GitCKPathForUseRepoHooksPath, directory, git.GlobalScope)
+			log.AssertNoErrorPanic(err, "Could not set Git config value.")
+		}
+
+		// Warnings:
+		// Check if hooks might not run...
+		hP := gitx.GetConfig(git.GitCKCoreHooksPath, git.GlobalScope)
+		log.WarnIfF(strs.IsNotEmpty(hP),
+			"The 'core.hooksPath' setting is currently set to\n"+
+				"'%s'\n"+
+				"Repositories without Githooks installed by\n"+
+				"'git hooks install' will use this folder.",
+			hP)
+
 	case install.InstallModeTypeV.None:
 		fallthrough
 	case install.InstallModeTypeV.TemplateDir:
@@ -990,13 +1187,74 @@ func installBinaries(
 		"Could not set dialog executable to '%s'.", dialog)
 }
 
+// keepRollbackState keeps the installed version for a rollback
+// if we are updating. Returns `true` if a rollback is possible.
+func keepRollbackState(log cm.ILogContext, installDir string, args *Arguments) bool {
+	if args.DryRun || strs.IsEmpty(args.InternalUpdateTo) ||
+		!cm.IsFile(hooks.GetRunnerExecutable(installDir)) {
+		return false
+	}
+
+	err := updates.StoreRollbackState(installDir, args.InternalUpdateFromVersion)
+	log.AssertNoErrorF(err, "Could not keep version '%s' for a rollback.", args.InternalUpdateFromVersion)
+
+	return err == nil
+}
+
+// checkInstalledRunner runs a smoke check on the installed runner
+// and rolls back to the previous version if it fails and `canRollback` is set.
+func checkInstalledRunner(log cm.ILogContext, installDir string, canRollback bool) {
+	runner := cm.Executable{Cmd: hooks.GetRunnerExecutable(installDir)}
+
+	out, _, err := cm.GetCombinedOutputFromExecutable(&cm.ExecContext{}, &runner, nil, "--version")
+	if err == nil && strings.Contains(string(out), build.BuildVersion) {
+		return
+	}
+
+	err = cm.CombineErrors(
+		cm.ErrorF("Smoke check of the installed runner '%s' failed:\n%s", runner.Cmd, out), err)
+
+	if !canRollback {
+		log.AssertNoErrorPanic(err, "Installed runner is not working.")
+	}
+
+	state, e := updates.Rollback(installDir, build.BuildTag)
+	if e != nil {
+		log.AssertNoErrorPanic(cm.CombineErrors(err, e), "Could not roll back to the previous version.")
+	}
+
+	log.AssertNoErrorPanicF(err,
+		"Installed runner is not working.\n"+
+			"Rolled back to the previous version '%s'.", state.BuildVersion)
+}
+
 func setupAutomaticUpdate(
 	log cm.ILogContext,
 	gitx *git.Context,
 	nonInteractive bool,
 	dryRun bool,
+	autoUpdateEnabled *bool,
 	promptx prompt.IContext) {
 
+	if autoUpdateEnabled != nil {
+		// Given by the install configuration.
+		state := "disabled"
+		if *autoUpdateEnabled {
+			state = "enabled"
+		}
+
+		if dryRun {
+			log.InfoF("[dry run] Would set automatic update checks %s.", state)
+		} else {
+			err := updates.SetAutomaticUpdateCheckSettings(*autoUpdateEnabled, false)
+			if log.AssertNoErrorF(err, "Failed to set automatic update checks.") {
+				log.InfoF("Automatic update checks are now %s.", state)
+			}
+		}
+
+		return
+	}
+
 	enabled, isSet := updates.GetAutomaticUpdateCheckSettings(gitx)
 	promptMsg := ""
 
@@ -1044,6 +1302,49 @@ func setupAutomaticUpdate(
 	}
 }
 
+// setupUpdateChannel stores the update channel `channel` if given.
+func setupUpdateChannel(log cm.ILogContext, channel string, dryRun bool) {
+	switch {
+	case strs.IsEmpty(channel):
+		return
+	case dryRun:
+		log.InfoF("[dry run] Would set update channel '%s'.", channel)
+	default:
+		err := updates.SetUpdateChannelName(channel)
+		if log.AssertNoErrorF(err, "Failed to set update channel.") {
+			log.InfoF("Update channel is now '%s'.", channel)
+		}
+	}
+}
+
+// setupContainerSettings sets the container settings
+// given by the install configuration.
+func setupContainerSettings(
+	log cm.ILogContext,
+	gitx *git.Context,
+	enabled *bool,
+	manager string,
+	dryRun bool) {
+
+	if enabled != nil {
+		if dryRun {
+			log.InfoF("[dry run] Would set running hooks containerized to '%v'.", *enabled)
+		} else {
+			err := gitx.SetConfig(hooks.GitCKContainerizedHooksEnabled, *enabled, git.GlobalScope)
+			log.AssertNoErrorF(err, "Could not set Git config '%s'.", hooks.GitCKContainerizedHooksEnabled)
+		}
+	}
+
+	if strs.IsNotEmpty(manager) {
+		if dryRun {
+			log.InfoF("[dry run] Would set container manager to '%s'.", manager)
+		} else {
+			err := gitx.SetConfig(hooks.GitCKContainerManager, manager, git.GlobalScope)
+			log.AssertNoErrorF(err, "Could not set Git config '%s'.", hooks.GitCKContainerManager)
+		}
+	}
+}
+
 func installIntoExistingRepos(
 	log cm.ILogContext,
 	gitx *git.Context,
@@ -1068,7 +1369,7 @@ func installIntoExistingRepos(
 			if install.InstallIntoRepo(
 				log, gitx, gitDir, lfsHooksCache, nil,
 				nonInteractive, dryRun,
-				skipReadme, uiSettings) {
+				skipReadme, install.ExternalHooksSkip, uiSettings) {
 
 				registeredRepos.Insert(gitDir)
 				installedRepos.Insert(gitDir)
@@ -1108,7 +1409,7 @@ func installIntoRegisteredRepos(
 			if install.InstallIntoRepo(
 				log, gitx, gitDir, lfsHooksCache, nil,
 				nonInteractive, dryRun,
-				skipReadme, uiSettings) {
+				skipReadme, install.ExternalHooksSkip, uiSettings) {
 
 				registeredRepos.Insert(gitDir)
 				installedRepos.Insert(gitDir)
@@ -1162,8 +1463,39 @@ func setupSharedRepositories(
 		return // nolint: nlreturn
 	}
 
+	storeSharedRepositories(log, gitx, installDir, entries)
+}
+
+// setupConfiguredSharedRepositories sets up the shared repositories
+// given by the install configuration.
+func setupConfiguredSharedRepositories(
+	log cm.ILogContext,
+	gitx *git.Context,
+	installDir string,
+	entries []string,
+	dryRun bool) {
+
+	if dryRun {
+		log.InfoF("[dry run] Would set shared hook repositories:\n%s",
+			strings.Join(strs.Map(entries, func(s string) string {
+				return strs.Fmt(" %s '%s'", cm.ListItemLiteral, s)
+			}), "\n"))
+
+		return
+	}
+
+	storeSharedRepositories(log, gitx, installDir, entries)
+}
+
+// storeSharedRepositories replaces the global shared repositories with `entries`.
+func storeSharedRepositories(
+	log cm.ILogContext,
+	gitx *git.Context,
+	installDir string,
+	entries []string) {
+
 	// Unset all shared configs.
-	err = gitx.UnsetConfig(hooks.GitCKShared, git.GlobalScope)
+	err := gitx.UnsetConfig(hooks.GitCKShared, git.GlobalScope)
 	log.AssertNoErrorF(err,
 		"Could not unset Git config '%s'.\n"+
 			"Failed to setup shared hook repositories.", hooks.GitCKShared)
@@ -1192,9 +1524,13 @@ func setupSharedRepositories(
 			"file will still be executed", hooks.GitCKShared, hooks.GetRepoSharedFileRel())
 	} else {
 
-		updated, err := hooks.UpdateAllSharedHooks(log, gitx, installDir, "", false)
-		log.ErrorIf(err != nil, "Could not update shared hook repositories.")
-		log.InfoF("Updated '%v' shared hook repositories.", updated)
+		if hooks.IsOfflineMode(gitx, git.GlobalScope, true) {
+			log.Info("Githooks is in offline mode: updating shared hook repositories skipped.")
+		} else {
+			updated, err := hooks.UpdateAllSharedHooks(log, gitx, installDir, "", false)
+			log.ErrorIf(err != nil, "Could not update shared hook repositories.")
+			log.InfoF("Updated '%v' shared hook repositories.", updated)
+		}
 
 		log.InfoF(
 			"Shared hook repositories have been set up.\n"+
@@ -1230,13 +1566,22 @@ func storeSettings(log cm.ILogContext, settings *Settings, uiSettings *install.U
 
 }
 
-func updateClone(log cm.ILogContext, cloneDir string, updateToSHA string) {
+func updateClone(log cm.ILogContext, cloneDir string, updateToSHA string, resetTo bool) {
 
 	if strs.IsEmpty(updateToSHA) {
 		return // We don't need to update the release clone.
 	}
 
-	commitSHA, err := updates.MergeUpdates(cloneDir, false)
+	var commitSHA string
+	var err error
+
+	if resetTo {
+		// The version can be older, no fast-forward possible.
+		err = updates.ResetUpdates(cloneDir, updateToSHA)
+		commitSHA = updateToSHA
+	} else {
+		commitSHA, err = updates.MergeUpdates(cloneDir, false)
+	}
 
 	log.AssertNoErrorF(err,
 		"Could not finalize by updating the local branch to the\n"+
@@ -1279,7 +1624,8 @@ func determineInstallMode(log cm.ILogContext, args *Arguments, gitx *git.Context
 
 		installMode = install.MapInstallerArgsToInstallMode(
 			args.UseCoreHooksPath,
-			args.UseManual)
+			args.UseManual,
+			args.UseRepoHooksPath)
 
 		if haveInstall && installMode != installModeInstalled {
 			log.PanicF(
@@ -1328,6 +1674,8 @@ func runInstaller(
 		uiSettings.PromptCtx)
 
 	if len(args.InternalBinaries) != 0 {
+		canRollback := keepRollbackState(log, settings.InstallDir, args)
+
 		installBinaries(
 			log,
 			settings.InstallDir,
@@ -1335,6 +1683,10 @@ func runInstaller(
 			settings.TempDir,
 			args.InternalBinaries,
 			args.DryRun)
+
+		if !args.DryRun {
+			checkInstalledRunner(log, settings.InstallDir, canRollback)
+		}
 	}
 
 	setupHookTemplates(
@@ -1350,10 +1702,21 @@ func runInstaller(
 		uiSettings)
 
 	if !args.InternalAutoUpdate {
-		setupAutomaticUpdate(log, gitx, args.NonInteractive, args.DryRun, uiSettings.PromptCtx)
+		setupAutomaticUpdate(log, gitx,
+			args.NonInteractive, args.DryRun, args.AutoUpdateEnabled, uiSettings.PromptCtx)
+
+		setupUpdateChannel(log, args.UpdateChannel, args.DryRun)
+
+		setupContainerSettings(log, gitx,
+			args.ContainerizedHooksEnabled, args.ContainerManager, args.DryRun)
 	}
 
-	if !args.SkipInstallIntoExisting && !args.UseCoreHooksPath &&
+	// In install mode 'repo-hooks-path' the repositories
+	// point to the updated hook template directory.
+	useHooksPath := args.UseCoreHooksPath ||
+		installMode == install.InstallModeTypeV.RepoHooksPath
+
+	if !args.SkipInstallIntoExisting && !useHooksPath &&
 		!args.InternalAutoUpdate {
 
 		installIntoExistingRepos(
@@ -1369,7 +1732,7 @@ func runInstaller(
 
 	}
 
-	if !args.UseCoreHooksPath {
+	if !useHooksPath {
 		installIntoRegisteredRepos(
 			log,
 			gitx,
@@ -1382,17 +1745,30 @@ func runInstaller(
 			uiSettings)
 	}
 
-	if !args.InternalAutoUpdate && !args.NonInteractive {
-		setupSharedRepositories(
-			log,
-			settings.InstallDir,
-			args.DryRun,
-			uiSettings)
+	if strs.IsNotEmpty(args.InternalBundleDir) {
+		installBundleContent(log, gitx, settings.InstallDir, args)
+	}
+
+	if !args.InternalAutoUpdate {
+		if len(args.SharedRepos) != 0 {
+			setupConfiguredSharedRepositories(
+				log,
+				gitx,
+				settings.InstallDir,
+				args.SharedRepos,
+				args.DryRun)
+		} else if !args.NonInteractive {
+			setupSharedRepositories(
+				log,
+				settings.InstallDir,
+				args.DryRun,
+				uiSettings)
+		}
 	}
 
 	if !args.DryRun {
 		storeSettings(log, settings, uiSettings)
-		updateClone(log, settings.CloneDir, args.InternalUpdateTo)
+		updateClone(log, settings.CloneDir, args.InternalUpdateTo, strs.IsNotEmpty(args.UpdateTo))
 	}
 }
 
@@ -1448,6 +1824,12 @@ func runInstall(cmd *cobra.Command, ctx *ccm.CmdContext, vi *viper.Viper) error
 	initArgs(log, &args, vi)
 	validateArgs(log, cmd, &args)
 
+	if args.PrintConfig {
+		printInstallConfig(log, &args)
+
+		return nil
+	}
+
 	isDefaultLog := false
 	isDefaultLog, args.Log = addInstallerLog(args.Log, log)
 
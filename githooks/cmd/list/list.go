@@ -77,6 +77,11 @@ func PrepareListHookState(
 	// Load ignore patterns
 	ignores, err := hooks.GetIgnorePatterns(repoHooksDir, gitDirWorktree, hookNames, hookNamespace)
 	ctx.Log.AssertNoErrorF(err, "Errors while loading ignore patterns.")
+
+	// Hook arguments are not known when listing.
+	ignoreConditions := hooks.NewIgnoreConditionContext(ctx.GitX, nil, false)
+	ignores.EvaluateConditions(ignoreConditions)
+
 	ctx.Log.DebugF("User ignore patterns: '%+q'.", ignores.User)
 	ctx.Log.DebugF("Accumuldated repository ignore patterns: '%q'.", ignores.HooksDir)
 
@@ -92,12 +97,25 @@ func PrepareListHookState(
 	shared[hooks.SharedHookTypeV.Global], err = hooks.LoadConfigSharedHooks(ctx.InstallDir, ctx.GitX, git.GlobalScope)
 	ctx.Log.AssertNoErrorF(err, "Could not load global shared hooks.")
 
+	policy, err := hooks.LoadTrustPolicy(ctx.GitX)
+	ctx.Log.AssertNoErrorPanicF(err, "Could not load trust policy.")
+
+	expiry, err := hooks.GetEffectiveTrustExpiry(ctx.GitX, &policy)
+	ctx.Log.AssertNoErrorF(err, "Could not get trust expiry.")
+	checksums.SetExpiry(expiry)
+
 	isTrusted, _, _ := hooks.IsRepoTrusted(ctx.GitX, repoDir)
+	if forbidden, _ := policy.IsTrustAllForbidden(ctx.GitX); forbidden {
+		isTrusted = false
+	}
+
 	isDisabled := hooks.IsGithooksDisabled(ctx.GitX, true)
 
 	state = &ListingState{
 		Checksums:          &checksums,
 		Ignores:            &ignores,
+		Policy:             &policy,
+		ignoreConditions:   ignoreConditions,
 		isRepoTrusted:      isTrusted,
 		isGithooksDisabled: isDisabled,
 		sharedIgnores:      make(ignoresPerHooksDir, 10)} // nolint: gomnd
@@ -110,7 +128,9 @@ func PrepareListHookState(
 type ListingState struct {
 	Checksums *hooks.ChecksumStore
 	Ignores   *hooks.RepoIgnorePatterns
+	Policy    *hooks.TrustPolicy
 
+	ignoreConditions   *hooks.IgnoreConditionContext
 	isRepoTrusted      bool
 	isGithooksDisabled bool
 
@@ -187,13 +207,16 @@ func listHooksForName(
 	replacedHooks := GetAllHooksIn(
 		log, gitx,
 		repoDir, path.Join(gitDir, "hooks"), hookName,
-		hooks.NamespaceReplacedHook, state, false, true)
+		hooks.NamespaceReplacedHook, state, false, true, false)
+
+	// List hooks of external hook managers
+	externalHooks := GetExternalHooks(log, gitx, repoDir, hookName, state)
 
 	// List repository hooks
 	repoHooks := GetAllHooksIn(
 		log, gitx,
 		repoDir, repoHooksDir, hookName,
-		hooks.NamespaceRepositoryHook, state, false, false)
+		hooks.NamespaceRepositoryHook, state, false, false, false)
 
 	// List all shared hooks
 	sharedCount := 0
@@ -232,17 +255,22 @@ func listHooksForName(
 	}
 
 	printHooks(replacedHooks, "Replaced:", "replaced")
+	printHooks(externalHooks, "External:", "external")
 	printHooks(repoHooks, "Repository:", "repo")
 
 	tagNames := hooks.GetSharedRepoTagNames()
 	for i := range all {
-		printHooks(
-			all[i].Hooks,
-			strs.Fmt("Shared '%s':", all[i].Repo.OriginalURL),
-			tagNames[all[i].Category])
+		title := strs.Fmt("Shared '%s':", all[i].Repo.OriginalURL)
+		if all[i].TrustedByPolicy {
+			title = strs.Fmt("Shared '%s' [trusted: policy '%s']:", all[i].Repo.OriginalURL, state.Policy.File)
+		} else if rev := all[i].TrustedRevision; rev != nil {
+			title = strs.Fmt("Shared '%s' [trusted: %s]:", all[i].Repo.OriginalURL, rev.Description())
+		}
+
+		printHooks(all[i].Hooks, title, tagNames[all[i].Category])
 	}
 
-	return sb.String(), len(replacedHooks) + len(repoHooks) + sharedCount
+	return sb.String(), len(replacedHooks) + len(externalHooks) + len(repoHooks) + sharedCount
 }
 
 func findPaddingListHooks(hooks []hooks.Hook, maxPadding int) int {
@@ -260,6 +288,12 @@ type SharedHooks struct {
 	Repo     *hooks.SharedRepo
 	Category hooks.SharedHookType
 	Hooks    []hooks.Hook
+
+	// The trust record if the checked out revision is trusted.
+	TrustedRevision *hooks.TrustedRevision
+
+	// If all hooks are trusted by the trust policy.
+	TrustedByPolicy bool
 }
 
 // GetAllHooksInShared gets all hooks in shared repositories.
@@ -278,28 +312,40 @@ func GetAllHooksInShared(
 
 		hookNamespace := hooks.GetDefaultHooksNamespaceShared(shRepo)
 
-		var allHooks []hooks.Hook
+		dir := hooks.GetSharedGithooksDir(shRepo.RepositoryDir)
 
-		if dir := hooks.GetSharedGithooksDir(shRepo.RepositoryDir); cm.IsDirectory(dir) {
-			allHooks = GetAllHooksIn(log, gitx, shRepo.RepositoryDir,
-				dir, hookName, hookNamespace, state, true, false)
+		namespace := hookNamespace
+		if ns, _ := hooks.GetHooksNamespace(dir); strs.IsNotEmpty(ns) {
+			namespace = ns
+		}
+
+		var trustedRevision *hooks.TrustedRevision
+		isTrustedByPolicy := state.Policy.IsSharedTrusted(shRepo.OriginalURL, namespace)
+		isRevisionTrusted := isTrustedByPolicy
 
-		} else if dir := hooks.GetGithooksDir(shRepo.RepositoryDir); cm.IsDirectory(dir) {
-			allHooks = GetAllHooksIn(log, gitx, shRepo.RepositoryDir,
-				dir, hookName, hookNamespace, state, true, false)
+		if !isTrustedByPolicy {
+			var rev hooks.TrustedRevision
+			var err error
 
-		} else {
-			allHooks = GetAllHooksIn(log, gitx, shRepo.RepositoryDir,
-				shRepo.RepositoryDir, hookName, hookNamespace, state, true, false)
+			isRevisionTrusted, rev, err = state.Checksums.IsRevisionTrusted(shRepo)
+			log.AssertNoErrorF(err, "Could not check trusted revisions of '%s'.", shRepo.OriginalURL)
+			if isRevisionTrusted {
+				trustedRevision = &rev
+			}
 		}
 
+		allHooks := GetAllHooksIn(log, gitx, shRepo.RepositoryDir,
+			dir, hookName, hookNamespace, state, true, false, isRevisionTrusted)
+
 		if len(allHooks) != 0 {
 			count += len(allHooks)
 			coll = append(coll,
 				SharedHooks{
-					Hooks:    allHooks,
-					Repo:     shRepo,
-					Category: category})
+					Hooks:           allHooks,
+					Repo:            shRepo,
+					Category:        category,
+					TrustedRevision: trustedRevision,
+					TrustedByPolicy: isTrustedByPolicy})
 		}
 	}
 
@@ -316,10 +362,11 @@ func GetAllHooksIn(
 	hookNamespace string,
 	state *ListingState,
 	addInternalIgnores bool,
-	isReplacedHook bool) []hooks.Hook {
+	isReplacedHook bool,
+	isRevisionTrusted bool) []hooks.Hook {
 
 	isTrusted := func(hookPath string) (bool, string) {
-		if state.isRepoTrusted {
+		if state.isRepoTrusted || isRevisionTrusted {
 			return true, ""
 		}
 
@@ -350,6 +397,7 @@ func GetAllHooksIn(
 
 		igns, e := hooks.GetHookPatternsHooksDir(hooksDir, []string{hookName}, hookNamespace)
 		log.AssertNoErrorF(e, "Could not get worktree ignores in '%s'.", hooksDir)
+		igns.EvaluateConditions(state.ignoreConditions)
 		state.sharedIgnores[hooksDir] = &igns
 		hookDirIgnores = &igns
 	}
@@ -381,6 +429,45 @@ func GetAllHooksIn(
 	return allHooks
 }
 
+// GetExternalHooks gets all hooks of the chained external hook managers.
+func GetExternalHooks(
+	log cm.ILogContext,
+	gitx *git.Context,
+	repoDir string,
+	hookName string,
+	state *ListingState) (allHooks []hooks.Hook) {
+
+	isTrusted := func(hookPath string) (bool, string) {
+		if state.isRepoTrusted {
+			return true, ""
+		}
+
+		trusted, sha, e := state.Checksums.IsTrusted(hookPath)
+		log.AssertNoErrorF(e, "Could not check trust status '%s'.", hookPath)
+
+		return trusted, sha
+	}
+
+	// External hooks can only be ignored by the user.
+	isIgnored := func(namespacePath string) bool {
+		ignored, byUser := state.Ignores.IsIgnored(namespacePath)
+
+		return ignored && byUser
+	}
+
+	managers := hooks.GetExternalHookManagers(gitx, repoDir)
+	for i := range managers {
+		hook, err := managers[i].GetHook(gitx, repoDir, hookName, isIgnored, isTrusted)
+		log.AssertNoErrorF(err, "Could not get hook of '%s'.", managers[i].Name)
+
+		if hook != nil {
+			allHooks = append(allHooks, *hook)
+		}
+	}
+
+	return
+}
+
 func formatHookState(
 	w io.Writer,
 	hook *hooks.Hook,
@@ -8,6 +8,8 @@ import (
 	inst "github.com/gabyx/githooks/githooks/cmd/common/install"
 	"github.com/gabyx/githooks/githooks/cmd/config"
 	"github.com/gabyx/githooks/githooks/cmd/disable"
+	"github.com/gabyx/githooks/githooks/cmd/doctor"
+	"github.com/gabyx/githooks/githooks/cmd/explain"
 	"github.com/gabyx/githooks/githooks/cmd/ignore"
 	"github.com/gabyx/githooks/githooks/cmd/images"
 	"github.com/gabyx/githooks/githooks/cmd/install"
@@ -63,6 +65,8 @@ func NewSettings(
 func addSubCommands(cmd *cobra.Command, ctx *ccm.CmdContext) {
 	cmd.AddCommand(config.NewCmd(ctx))
 	cmd.AddCommand(disable.NewCmd(ctx))
+	cmd.AddCommand(doctor.NewCmd(ctx))
+	cmd.AddCommand(explain.NewCmd(ctx))
 	cmd.AddCommand(ignore.NewCmd(ctx))
 	cmd.AddCommand(install.NewCmd(ctx)...)
 	cmd.AddCommand(list.NewCmd(ctx))
@@ -1,10 +1,14 @@
 package shared
 
 import (
+	"encoding/json"
+	"path"
+	"path/filepath"
 	"strings"
 
 	ccm "github.com/gabyx/githooks/githooks/cmd/common"
 	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/container"
 	"github.com/gabyx/githooks/githooks/git"
 	"github.com/gabyx/githooks/githooks/hooks"
 	strs "github.com/gabyx/githooks/githooks/strings"
@@ -118,9 +122,52 @@ func runSharedPurge(ctx *ccm.CmdContext) {
 	ctx.Log.Info("Purged all shared repositories.")
 }
 
+func runSharedGC(ctx *ccm.CmdContext, dryRun bool) {
+	repoDir, _, _, err := ctx.GitX.GetRepoRoot()
+	if err != nil {
+		repoDir = ""
+	}
+
+	garbage, err := hooks.FindSharedGarbage(ctx.InstallDir, ctx.GitX, repoDir)
+	ctx.Log.AssertNoErrorPanicF(err, "Could not determine unreferenced shared repositories.")
+
+	format := func(lst []string) string {
+		if len(lst) == 0 {
+			return strs.Fmt(" %s None", cm.ListItemLiteral)
+		}
+
+		return strings.Join(strs.Map(lst, func(s string) string {
+			return strs.Fmt(" %s '%s'", cm.ListItemLiteral, s)
+		}), "\n")
+	}
+
+	if dryRun {
+		ctx.Log.InfoF("Unreferenced shared repositories which would be removed:\n%s", format(garbage.CloneDirs))
+		ctx.Log.InfoF("Container images which would be removed:\n%s", format(garbage.Images))
+
+		return
+	}
+
+	var mgr container.IManager
+	if len(garbage.Images) != 0 {
+		mgr, err = container.NewManager(ctx.GitX.GetConfig(hooks.GitCKContainerManager, git.Traverse))
+		ctx.Log.AssertNoErrorF(err, "Could not create container manager. Removing images skipped.")
+	}
+
+	err = hooks.RemoveSharedGarbage(&garbage, mgr)
+	ctx.Log.AssertNoErrorPanicF(err, "Could not remove all unreferenced shared repositories.")
+
+	ctx.Log.InfoF("Removed unreferenced shared repositories:\n%s", format(garbage.CloneDirs))
+	if mgr != nil {
+		ctx.Log.InfoF("Removed container images:\n%s", format(garbage.Images))
+	}
+}
+
 func runSharedList(ctx *ccm.CmdContext, opts *sharedOpts) {
 	sharedOptsSetAll(opts)
 
+	verifyConfig := hooks.GetSharedRepoVerifyConfig(ctx.GitX, git.Traverse)
+
 	formatLine := func(s *hooks.SharedRepo) string {
 		state := "invalid"
 
@@ -136,6 +183,18 @@ func runSharedList(ctx *ccm.CmdContext, opts *sharedOpts) {
 			}
 		}
 
+		if verify := s.GetVerify(verifyConfig); state == "active" && len(verify) != 0 {
+			state = "verified"
+
+			for _, v := range verify {
+				if err := s.VerifySignature(v); err != nil {
+					state = "unverified"
+
+					break
+				}
+			}
+		}
+
 		return strs.Fmt(" %s '%s' : state: '%s'", cm.ListItemLiteral, s.OriginalURL, state)
 	}
 
@@ -184,6 +243,12 @@ func runSharedList(ctx *ccm.CmdContext, opts *sharedOpts) {
 }
 
 func runSharedUpdate(ctx *ccm.CmdContext) {
+	if hooks.IsOfflineMode(ctx.GitX, git.Traverse, true) {
+		ctx.Log.WarnF("Githooks is in offline mode: updating shared hooks skipped.")
+
+		return
+	}
+
 	repoDir, _, _, err := ctx.GitX.GetRepoRoot()
 
 	if err != nil {
@@ -199,6 +264,184 @@ func runSharedUpdate(ctx *ccm.CmdContext) {
 	ctx.Log.InfoF("Update '%v' shared repositories.", updated)
 }
 
+func runSharedStatus(ctx *ccm.CmdContext, opts *sharedOpts, fetch bool, asJSON bool) {
+	sharedOptsSetAll(opts)
+
+	if fetch && hooks.IsOfflineMode(ctx.GitX, git.Traverse, true) {
+		ctx.Log.WarnF("Githooks is in offline mode: fetching shared hooks skipped.")
+		fetch = false
+	}
+
+	var all []hooks.SharedRepoStatus
+	status := func(sharedHooks []hooks.SharedRepo, sharedType hooks.SharedHookType) (res []hooks.SharedRepoStatus) {
+		for i := range sharedHooks {
+			res = append(res, hooks.GetSharedRepoStatus(&sharedHooks[i], sharedType, fetch))
+		}
+		all = append(all, res...)
+
+		return
+	}
+
+	var lists []string
+	addList := func(title string, statuses []hooks.SharedRepoStatus) {
+		lists = append(lists, strs.Fmt("%s:\n%s", title, formatSharedStatus(statuses)))
+	}
+
+	if opts.Shared {
+		repoDir, _, _ := ccm.AssertRepoRoot(ctx)
+		shared, err := hooks.LoadRepoSharedHooks(ctx.InstallDir, repoDir)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not load shared hook list '%s'.", hooks.GetRepoSharedFileRel())
+		addList(strs.Fmt("Shared hook repositories in '%s'", hooks.GetRepoSharedFileRel()),
+			status(shared, hooks.SharedHookTypeV.Repo))
+	}
+
+	if opts.Local {
+		if !opts.Shared {
+			ccm.AssertRepoRoot(ctx)
+		}
+
+		local, err := hooks.LoadConfigSharedHooks(ctx.InstallDir, ctx.GitX, git.LocalScope)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not load local shared hook list.")
+		addList("Local shared hook repositories", status(local, hooks.SharedHookTypeV.Local))
+	}
+
+	if opts.Global {
+		global, err := hooks.LoadConfigSharedHooks(ctx.InstallDir, ctx.GitX, git.GlobalScope)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not load global shared hook list.")
+		addList("Global shared hook repositories", status(global, hooks.SharedHookTypeV.Global))
+	}
+
+	if asJSON {
+		if all == nil {
+			all = []hooks.SharedRepoStatus{}
+		}
+
+		data, err := json.MarshalIndent(all, "", "  ")
+		ctx.Log.AssertNoErrorPanicF(err, "Could not serialize shared repository status.")
+		_, err = ctx.Log.GetInfoWriter().Write(append(data, '\n'))
+		ctx.Log.AssertNoErrorF(err, "Could not write output.")
+
+		return
+	}
+
+	for _, l := range lists {
+		ctx.Log.Info(l)
+	}
+}
+
+func formatSharedStatus(statuses []hooks.SharedRepoStatus) string {
+	if len(statuses) == 0 {
+		return strs.Fmt(" %s None", cm.ListItemLiteral)
+	}
+
+	var lst []string
+	for i := range statuses {
+		s := &statuses[i]
+
+		lines := []string{strs.Fmt(" %s '%s' : state: '%s'", cm.ListItemLiteral, s.URL, s.State)}
+		add := func(format string, args ...interface{}) {
+			lines = append(lines, "     "+strs.Fmt(format, args...))
+		}
+
+		if s.State == hooks.SharedStateActive {
+			if s.HasDrift() {
+				add("drift: yes")
+			} else {
+				add("drift: no")
+			}
+
+			if s.Pinned {
+				add("pinned: tag '%s'", s.Branch)
+			} else if s.IsBranchMismatch() {
+				add("branch: '%s' (expected: '%s')", s.CurrentBranch, s.Branch)
+			} else {
+				add("branch: '%s'", s.CurrentBranch)
+			}
+
+			if s.IsRemoteURLMismatch() {
+				add("remote url: '%s' (expected: '%s')", s.RemoteURL, s.ExpectedRemoteURL)
+			} else {
+				add("remote url: '%s'", s.RemoteURL)
+			}
+
+			add("commit: '%s', ahead: '%v', behind: '%v'", s.Commit, s.Ahead, s.Behind)
+
+			if len(s.ModifiedFiles) != 0 {
+				add("local modifications: '%v' files", len(s.ModifiedFiles))
+			}
+
+			if s.Locked {
+				add("locked: an update is in progress or has been aborted")
+			}
+
+			if !s.Fetched {
+				add("not fetched: use '--fetch' to compare against the latest remote")
+			}
+		}
+
+		for _, e := range s.Errors {
+			add("error: %s", strings.ReplaceAll(e, "\n", " "))
+		}
+
+		lst = append(lst, strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(lst, "\n")
+}
+
+func getSharedRepoDir(ctx *ccm.CmdContext, args []string) string {
+	dir := ctx.Cwd
+	if len(args) != 0 {
+		dir = args[0]
+	}
+
+	dir, err := filepath.Abs(dir)
+	ctx.Log.AssertNoErrorPanicF(err, "Could not get absolute path of '%s'.", dir)
+
+	return filepath.ToSlash(dir)
+}
+
+func runSharedInit(ctx *ccm.CmdContext, args []string, namespace string) {
+	repoDir := getSharedRepoDir(ctx, args)
+
+	if strs.IsEmpty(namespace) {
+		namespace = path.Base(repoDir)
+	}
+
+	created, err := hooks.InitSharedRepo(repoDir, namespace)
+	ctx.Log.AssertNoErrorPanicF(err, "Could not scaffold shared hook repository in '%s'.", repoDir)
+
+	ctx.Log.InfoF("Scaffolded shared hook repository with namespace '%s' in '%s':\n%s",
+		namespace, repoDir,
+		strings.Join(strs.Map(created, func(s string) string {
+			return strs.Fmt(" %s '%s'", cm.ListItemLiteral, s)
+		}), "\n"))
+}
+
+func runSharedValidate(ctx *ccm.CmdContext, args []string) error {
+	repoDir := getSharedRepoDir(ctx, args)
+	ctx.Log.PanicIfF(!cm.IsDirectory(repoDir), "Directory '%s' does not exist.", repoDir)
+
+	hooksDir, checked, errs := hooks.ValidateSharedRepo(repoDir)
+
+	ctx.Log.InfoF("Validated '%v' files in hooks directory '%s'.", len(checked), hooksDir)
+
+	if len(errs) != 0 {
+		lst := make([]string, 0, len(errs))
+		for _, e := range errs {
+			lst = append(lst, strs.Fmt(" %s %s", cm.ListItemLiteral,
+				strings.ReplaceAll(e.Error(), "\n", "\n   ")))
+		}
+
+		return ctx.NewCmdExit(1, "Shared hook repository '%s' has '%v' errors:\n%s",
+			repoDir, len(errs), strings.Join(lst, "\n"))
+	}
+
+	ctx.Log.InfoF("Shared hook repository '%s' is valid.", repoDir)
+
+	return nil
+}
+
 func runSharedRoot(ctx *ccm.CmdContext, namespaces []string) (exitCode error) {
 	ctx.WrapPanicExitCode()
 	repoDir, _, _ := ccm.AssertRepoRoot(ctx)
@@ -327,6 +570,26 @@ file is modified in the local repository.`, hooks.GetRepoSharedFileRel())
 			runSharedPurge(ctx)
 		}}
 
+	dryRun := false
+	sharedGCCmd := &cobra.Command{
+		Use:   "gc [flags]",
+		Short: `Remove unreferenced shared repositories.`,
+		Long: strs.Fmt(`Deletes all cloned shared hook repositories locally which are
+not referenced anymore together with their container images.
+
+A shared repository is referenced if it is configured in
+the '%s' file or the local Git config of any registered repository
+or of the current repository, or in the global Git config.
+Repositories which are not registered (e.g. not yet run by Githooks)
+are not considered and their shared repositories are cloned again
+on the next update.`, hooks.GetRepoSharedFileRel()),
+		Run: func(c *cobra.Command, args []string) {
+			runSharedGC(ctx, dryRun)
+		}}
+
+	sharedGCCmd.Flags().BoolVar(&dryRun, "dry-run", false,
+		"Only list the shared repositories and images which would be removed.")
+
 	sharedListCmd := &cobra.Command{
 		Use:   "list [flags]",
 		Short: `List shared repositories.`,
@@ -350,6 +613,61 @@ running 'git pull' on existing ones or 'git clone' on new ones.`,
 			runSharedUpdate(ctx)
 		}}
 
+	fetch := false
+	asJSON := false
+	sharedStatusCmd := &cobra.Command{
+		Use:   "status [flags]",
+		Short: `Show the status of shared repositories.`,
+		Long: `Show the status of the shared, local, global or all (default) shared hooks repositories.
+
+For each cloned shared repository it reports if it has drifted, namely
+if it is ahead or behind its remote branch, has local modifications,
+is on a different branch than requested or has a different 'remote.origin.url'
+than the configured URL. It also reports if a Git lock file exists,
+e.g. if an update is in progress or has been aborted.
+
+Use '--fetch' to fetch the remote branches before computing the status.
+Use '--json' to get a machine readable output.`,
+		Run: func(c *cobra.Command, args []string) {
+			if !opts.Shared && !opts.Local && !opts.Global {
+				opts.All = true
+			}
+
+			runSharedStatus(ctx, &opts, fetch, asJSON)
+		}}
+
+	sharedStatusCmd.Flags().BoolVar(&fetch, "fetch", false, "Fetch the remote branches first.")
+	sharedStatusCmd.Flags().BoolVar(&asJSON, "json", false, "Output the status in JSON format.")
+
+	namespace := ""
+	sharedInitCmd := &cobra.Command{
+		Use:   "init [flags] [<dir>]",
+		Short: `Scaffold a new shared repository.`,
+		Long: `Scaffolds a new shared hook repository in '<dir>' (default: current directory)
+with an example containerized hook, a Dockerfile referenced in '.images.yaml'
+and a README.
+A Git repository is initialized if not yet existing. Existing files are never overwritten.
+The namespace defaults to the name of the directory.`,
+		PreRun: ccm.PanicIfNotRangeArgs(ctx.Log, 0, 1),
+		Run: func(cmd *cobra.Command, args []string) {
+			runSharedInit(ctx, args, namespace)
+		}}
+
+	sharedInitCmd.Flags().StringVar(&namespace, "namespace", "",
+		"The namespace of the shared repository.")
+
+	sharedValidateCmd := &cobra.Command{
+		Use:   "validate [<dir>]",
+		Short: `Validate a shared repository.`,
+		Long: `Validates all Githooks configuration files in the shared hook repository
+'<dir>' (default: current directory), namely the namespace, the ignore files,
+the images config file '.images.yaml' and all hook run configurations.
+All errors are reported at once and exit-code '1' is returned if any are found.`,
+		PreRun: ccm.PanicIfNotRangeArgs(ctx.Log, 0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSharedValidate(ctx, args)
+		}}
+
 	sharedRootCmd := &cobra.Command{
 		Use:   "root <namespace>...",
 		Short: `Get the root directory of shared repository in the current repository.`,
@@ -387,8 +705,14 @@ To ensure run 'git hooks shared update'.`,
 	addSharedOpts(sharedListCmd, &opts, true)
 	sharedCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, sharedListCmd))
 
+	addSharedOpts(sharedStatusCmd, &opts, true)
+	sharedCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, sharedStatusCmd))
+
 	sharedCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, sharedPurgeCmd))
+	sharedCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, sharedGCCmd))
 	sharedCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, sharedUpdateCmd))
+	sharedCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, sharedInitCmd))
+	sharedCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, sharedValidateCmd))
 	sharedCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, sharedRootCmd))
 	sharedCmd.AddCommand(ccm.SetCommandDefaults(ctx.Log, sharedRootFromUrlCmd))
 
@@ -2,10 +2,13 @@ package trust
 
 import (
 	"os"
+	"strings"
 
 	ccm "github.com/gabyx/githooks/githooks/cmd/common"
+	"github.com/gabyx/githooks/githooks/cmd/list"
 	cm "github.com/gabyx/githooks/githooks/common"
 	"github.com/gabyx/githooks/githooks/hooks"
+	strs "github.com/gabyx/githooks/githooks/strings"
 
 	"github.com/spf13/cobra"
 )
@@ -24,15 +27,27 @@ func runTrust(ctx *ccm.CmdContext, opt trustOption) {
 	repoRoot, _, _ := ccm.AssertRepoRoot(ctx)
 	file := hooks.GetTrustMarkerFile(repoRoot)
 
+	var decision string
+	wasTrusted, _ := hooks.GetTrustAllSetting(ctx.GitX)
+
 	switch opt {
 	case trustAdd:
-		err := cm.TouchFile(file, true)
+		policy, err := hooks.LoadTrustPolicy(ctx.GitX)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not load trust policy.")
+
+		forbidden, remoteURL := policy.IsTrustAllForbidden(ctx.GitX)
+		ctx.Log.PanicIfF(forbidden,
+			"Trusting all hooks of this repository is forbidden\n"+
+				"by the trust policy '%s' for remote '%s'.", policy.File, remoteURL)
+
+		err = cm.TouchFile(file, true)
 		ctx.Log.AssertNoErrorPanicF(err, "Could not touch trust marker '%s'.", file)
 		ctx.Log.Info("The trust marker is added to the repository.")
 
 		err = hooks.SetTrustAllSetting(ctx.GitX, true, false)
 		ctx.Log.AssertNoErrorPanic(err, "Could not set trust settings.")
 		ctx.Log.Info("The current repository is now trusted.")
+		decision = hooks.TrustDecisionTrusted
 
 		if !ctx.GitX.IsBareRepo() {
 			ctx.Log.Info("Do not forget to commit and push it!")
@@ -44,6 +59,7 @@ func runTrust(ctx *ccm.CmdContext, opt trustOption) {
 		} else {
 			err := hooks.SetTrustAllSetting(ctx.GitX, false, true)
 			ctx.Log.AssertNoErrorPanic(err, "Could not unset trust settings.")
+			decision = hooks.TrustDecisionForgotten
 		}
 
 		ctx.Log.Info("The current repository is no longer trusted.")
@@ -54,6 +70,16 @@ func runTrust(ctx *ccm.CmdContext, opt trustOption) {
 		err := hooks.SetTrustAllSetting(ctx.GitX, false, false)
 		ctx.Log.AssertNoErrorPanicF(err, "Could not set trust settings.")
 		ctx.Log.Info("The current repository is no longer trusted.")
+
+		if wasTrusted {
+			decision = hooks.TrustDecisionRevoked
+		}
+	}
+
+	if strs.IsNotEmpty(decision) {
+		err := hooks.AppendTrustAuditEntries(ctx.InstallDir,
+			hooks.NewTrustAuditEntry(repoRoot, "", "", decision))
+		ctx.Log.AssertNoErrorF(err, "Could not write trust audit log.")
 	}
 
 	if opt == trustDelete {
@@ -68,6 +94,89 @@ func runTrust(ctx *ccm.CmdContext, opt trustOption) {
 	}
 }
 
+func runTrustMigrate(ctx *ccm.CmdContext) {
+	_, _, gitDirWorktree := ccm.AssertRepoRoot(ctx)
+
+	store, err := hooks.GetChecksumStorage(gitDirWorktree)
+	ctx.Log.AssertNoErrorPanicF(err, "Could not load checksum store.")
+
+	migrated, kept, err := store.MigrateChecksums()
+	ctx.Log.AssertNoErrorPanicF(err, "Could not migrate trusted checksums.")
+
+	ctx.Log.InfoF("Migrated '%v' trusted SHA1 checksums to SHA256.", migrated)
+	if kept != 0 {
+		ctx.Log.InfoF("Kept '%v' SHA1 checksums of files which\n"+
+			"do not exist anymore or have changed.", kept)
+	}
+}
+
+func runTrustPolicy(ctx *ccm.CmdContext) {
+	repoDir, _, gitDirWorktree := ccm.AssertRepoRoot(ctx)
+
+	repoHooksDir := hooks.GetGithooksDir(repoDir)
+	state, shared, _ := list.PrepareListHookState(ctx, repoDir, repoHooksDir, gitDirWorktree, nil)
+	policy := state.Policy
+
+	if !policy.IsSet() {
+		ctx.Log.InfoF("No trust policy is in effect.\n"+
+			"The trust policy is read from '%s'.", hooks.GetTrustPolicyFile(ctx.GitX))
+
+		return
+	}
+
+	var sb strings.Builder
+	_, _ = strs.FmtW(&sb, "Trust policy '%s' is in effect.", policy.File)
+
+	if forbidden, remoteURL := policy.IsTrustAllForbidden(ctx.GitX); forbidden {
+		_, _ = strs.FmtW(&sb, "\nTrusting all hooks of this repository is forbidden for remote '%s'.", remoteURL)
+	} else {
+		_, _ = strs.FmtW(&sb, "\nTrusting all hooks of this repository is allowed.")
+	}
+
+	var trusted []string
+	for i := range shared {
+		for j := range shared[i] {
+			sh := &shared[i][j]
+
+			namespace := hooks.GetDefaultHooksNamespaceShared(sh)
+			if ns, _ := hooks.GetHooksNamespace(hooks.GetSharedGithooksDir(sh.RepositoryDir)); strs.IsNotEmpty(ns) {
+				namespace = ns
+			}
+
+			if policy.IsSharedTrusted(sh.OriginalURL, namespace) {
+				trusted, _ = strs.AppendUnique(trusted, sh.OriginalURL)
+			}
+		}
+	}
+
+	if len(trusted) == 0 {
+		_, _ = strs.FmtW(&sb, "\nNo configured shared hooks are trusted by the policy.")
+	} else {
+		_, _ = strs.FmtW(&sb, "\nConfigured shared hooks trusted by the policy:")
+		for _, url := range trusted {
+			_, _ = strs.FmtW(&sb, "\n %s '%s'", cm.ListItemLiteral, url)
+		}
+	}
+
+	if len(policy.AllowedRegistries) == 0 {
+		_, _ = strs.FmtW(&sb, "\nContainerized hooks can use images from all registries.")
+	} else {
+		_, _ = strs.FmtW(&sb, "\nContainerized hooks can only use images from registries '%q'.",
+			policy.AllowedRegistries)
+	}
+
+	if expiry := policy.GetTrustExpiry(); expiry > 0 {
+		_, _ = strs.FmtW(&sb, "\nTrusted hooks expire after at most '%v'.", expiry)
+	}
+
+	if len(policy.ForbidSkip) != 0 {
+		_, _ = strs.FmtW(&sb, "\nHooks matching '%q' cannot be skipped by '%s'.",
+			policy.ForbidSkip, hooks.EnvVariableSkip)
+	}
+
+	ctx.Log.Info(sb.String())
+}
+
 // NewCmd creates this new command.
 func NewCmd(ctx *ccm.CmdContext) *cobra.Command {
 
@@ -82,7 +191,10 @@ The 'revoke' argument resets the already accepted trust setting,
 and the 'delete' argument also deletes the trust marker.
 
 The 'forget' option unsets the trust setting, asking for accepting
-it again next time, if the repository is marked as trusted.`,
+it again next time, if the repository is marked as trusted.
+
+Trusting the repository might be forbidden by the organization-wide
+trust policy (see 'git hooks trust policy').`,
 		Run: func(cmd *cobra.Command, args []string) {
 			runTrust(ctx, trustAdd)
 		}}
@@ -108,11 +220,40 @@ it again next time, if the repository is marked as trusted.`,
 			runTrust(ctx, trustDelete)
 		}}
 
+	trustMigrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: `Migrate trusted checksums to SHA256.`,
+		Long: `Migrates all legacy SHA1 checksums of trusted hooks in the current
+repository to SHA256 checksums. Only checksums of files which
+still exist with unchanged content are migrated.
+Legacy SHA1 checksums are still considered trusted.
+This is done automatically when Githooks is installed into the repository.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			runTrustMigrate(ctx)
+		}}
+
+	trustPolicyCmd := &cobra.Command{
+		Use:   "policy",
+		Short: `Show the organization-wide trust policy.`,
+		Long: `Shows the organization-wide trust policy in effect and how it applies
+to the current repository.
+
+The trust policy is read from the file given by 'githooks.trustPolicy'
+in the system Git config (default: '` + hooks.DefaultTrustPolicyFile + `').`,
+		Run: func(cmd *cobra.Command, args []string) {
+			runTrustPolicy(ctx)
+		}}
+
 	trustCmd.AddCommand(
 		ccm.SetCommandDefaults(ctx.Log, trustRevokeCmd),
 		ccm.SetCommandDefaults(ctx.Log, trustForgetCmd),
 		ccm.SetCommandDefaults(ctx.Log, trustDeleteCmd),
-		ccm.SetCommandDefaults(ctx.Log, NewTrustHooksCmd(ctx)))
+		ccm.SetCommandDefaults(ctx.Log, trustMigrateCmd),
+		ccm.SetCommandDefaults(ctx.Log, trustPolicyCmd),
+		ccm.SetCommandDefaults(ctx.Log, NewTrustHooksCmd(ctx)),
+		ccm.SetCommandDefaults(ctx.Log, NewTrustReviewCmd(ctx)),
+		ccm.SetCommandDefaults(ctx.Log, NewTrustSharedCmd(ctx)),
+		ccm.SetCommandDefaults(ctx.Log, NewTrustLogCmd(ctx)))
 
 	return ccm.SetCommandDefaults(ctx.Log, trustCmd)
 }
@@ -2,6 +2,7 @@ package trust
 
 import (
 	"path"
+	"time"
 
 	ccm "github.com/gabyx/githooks/githooks/cmd/common"
 	"github.com/gabyx/githooks/githooks/cmd/ignore"
@@ -9,6 +10,7 @@ import (
 	cm "github.com/gabyx/githooks/githooks/common"
 	"github.com/gabyx/githooks/githooks/git"
 	"github.com/gabyx/githooks/githooks/hooks"
+	strs "github.com/gabyx/githooks/githooks/strings"
 
 	"github.com/spf13/cobra"
 )
@@ -31,12 +33,15 @@ func getAllHooks(
 		// List replaced hooks (normally only one)
 		replacedHooks := list.GetAllHooksIn(
 			log, gitx, repoDir, path.Join(gitDir, "hooks"), hookName,
-			hooks.NamespaceReplacedHook, state, false, true)
+			hooks.NamespaceReplacedHook, state, false, true, false)
 		allHooks = append(allHooks, replacedHooks...)
 
+		// List hooks of external hook managers
+		allHooks = append(allHooks, list.GetExternalHooks(log, gitx, repoDir, hookName, state)...)
+
 		// List repository hooks
 		repoHooks := list.GetAllHooksIn(log, gitx, repoDir, repoHooksDir, hookName,
-			hooks.NamespaceRepositoryHook, state, false, false)
+			hooks.NamespaceRepositoryHook, state, false, false, false)
 		allHooks = append(allHooks, repoHooks...)
 
 		// List all shared hooks
@@ -55,37 +60,56 @@ func getAllHooks(
 	return
 }
 
-func apply(log cm.ILogContext, hook *hooks.Hook, checksums *hooks.ChecksumStore, reset bool) {
+// apply trusts or untrusts the hook `hook` and reports the changed trust decision (if any).
+func apply(
+	log cm.ILogContext,
+	hook *hooks.Hook,
+	checksums *hooks.ChecksumStore,
+	reset bool,
+	expiry time.Duration) (decision string) {
 
-	err := hook.AssertSHA1()
-	log.AssertNoErrorPanicF(err, "Could not compute SHA1 hash for hook '%s'.", hook.Path)
+	err := hook.AssertChecksum()
+	log.AssertNoErrorPanicF(err, "Could not compute SHA256 hash for hook '%s'.", hook.Path)
 
 	if reset {
 
-		removed, err := checksums.SyncChecksumRemove(hook.SHA1)
+		removed, err := checksums.SyncChecksumRemoveFile(hook.Path)
 		log.AssertNoErrorPanicF(err, "Could not sync checksum for hook '%s'.", hook.Path)
 
 		if removed != 0 {
 			log.InfoF("Removed trust checksum for hook '%s'.", hook.NamespacePath)
+			decision = hooks.TrustDecisionRevoked
 		} else {
 			log.InfoF("No trust checksum for hook '%s'.", hook.NamespacePath)
 		}
 
 	} else {
 
-		err = checksums.SyncChecksumAdd(
+		err = checksums.SyncChecksumAddWithExpiry(expiry,
 			hooks.ChecksumResult{
-				SHA1:          hook.SHA1,
+				Checksum:      hook.Checksum,
 				Path:          hook.Path,
 				NamespacePath: hook.NamespacePath})
 
 		log.AssertNoErrorPanicF(err, "Could not sync checksum for hook '%s'.", hook.Path)
 
-		log.InfoF("Set trust checksum for hook '%s'.", hook.NamespacePath)
+		if expiry > 0 {
+			log.InfoF("Set trust checksum for hook '%s' expiring in '%v'.", hook.NamespacePath, expiry)
+		} else {
+			log.InfoF("Set trust checksum for hook '%s'.", hook.NamespacePath)
+		}
+		decision = hooks.TrustDecisionTrusted
 	}
+
+	return
 }
 
-func runTrustPatterns(ctx *ccm.CmdContext, reset bool, all bool, patterns *hooks.HookPatterns) {
+func runTrustPatterns(
+	ctx *ccm.CmdContext,
+	reset bool,
+	all bool,
+	expiry time.Duration,
+	patterns *hooks.HookPatterns) {
 	repoDir, gitDir, gitDirWorktree := ccm.AssertRepoRoot(ctx)
 
 	repoHooksDir := hooks.GetGithooksDir(repoDir)
@@ -97,16 +121,24 @@ func runTrustPatterns(ctx *ccm.CmdContext, reset bool, all bool, patterns *hooks
 	patterns.MakeRelativePatternsAbsolute(hookNamespace, "")
 
 	countMatches := 0
+	var entries []hooks.TrustAuditEntry
 
 	for i := range allHooks {
 		hook := &allHooks[i]
 
 		if all || patterns.Matches(hook.NamespacePath) {
 			countMatches++
-			apply(ctx.Log, hook, state.Checksums, reset)
+
+			if decision := apply(ctx.Log, hook, state.Checksums, reset, expiry); strs.IsNotEmpty(decision) {
+				entries = append(entries,
+					hooks.NewTrustAuditEntry(repoDir, hook.NamespacePath, hook.Checksum, decision))
+			}
 		}
 	}
 
+	err := hooks.AppendTrustAuditEntries(ctx.InstallDir, entries...)
+	ctx.Log.AssertNoErrorF(err, "Could not write trust audit log.")
+
 	ctx.Log.PanicIfF(countMatches == 0,
 		"Given pattern or paths did not match any hooks '%v'.",
 		patterns)
@@ -118,13 +150,19 @@ func NewTrustHooksCmd(ctx *ccm.CmdContext) *cobra.Command {
 
 	reset := false
 	all := false
+	expiry := time.Duration(0)
 	patterns := hooks.HookPatterns{}
 
 	trustHooks := &cobra.Command{
 		Use:   "hooks [flags]",
 		Short: "Trust all hooks which match the glob patterns or namespace paths.",
 		Long: `Trust all hooks which match the glob patterns or namespace paths given
-by '--patterns' or '--paths'.` + "\n\n" +
+by '--patterns' or '--paths'.
+
+With '--expire' the trust of the matched hooks expires after the given
+duration and the trust prompt is shown again. Trust also expires
+after the duration set by 'git hooks config trust-expiry'
+or by the trust policy.` + "\n\n" +
 			ignore.SeeHookListHelpText + "\n\n" +
 			ignore.NamespaceHelpText + "\n\n" +
 			ignore.PatternsHelpText,
@@ -138,11 +176,13 @@ by '--patterns' or '--paths'.` + "\n\n" +
 			}
 
 			ctx.Log.PanicIfF(count == 0, "You need to provide at least one pattern or namespace path.")
+			ctx.Log.PanicIfF(reset && expiry != 0, "You cannot use '--expire' together with '--reset'.")
+			ctx.Log.PanicIfF(expiry < 0, "The duration of '--expire' must be positive.")
 		},
 
 		Run: func(cmd *cobra.Command, args []string) {
 
-			runTrustPatterns(ctx, reset, all, &patterns)
+			runTrustPatterns(ctx, reset, all, expiry, &patterns)
 		},
 	}
 
@@ -159,5 +199,8 @@ by '--patterns' or '--paths'.` + "\n\n" +
 	trustHooks.Flags().BoolVar(&reset, "reset", false,
 		"If the matched hooks are set 'untrusted'.")
 
+	trustHooks.Flags().DurationVar(&expiry, "expire", 0,
+		"The duration after which the trust of the matched hooks expires (e.g. '720h').")
+
 	return ccm.SetCommandDefaults(ctx.Log, trustHooks)
 }
@@ -0,0 +1,156 @@
+package trust
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"time"
+
+	ccm "github.com/gabyx/githooks/githooks/cmd/common"
+	"github.com/gabyx/githooks/githooks/hooks"
+	strs "github.com/gabyx/githooks/githooks/strings"
+
+	"github.com/spf13/cobra"
+)
+
+type trustLogOptions struct {
+	Repository    string
+	Current       bool
+	NamespacePath string
+	Decision      string
+	User          string
+	Since         string
+	AsJSON        bool
+}
+
+// parseSince parses a time given as duration relative to now (e.g. '24h')
+// or as date 'YYYY-MM-DD' or RFC3339 timestamp.
+func parseSince(s string) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
+
+func runTrustLog(ctx *ccm.CmdContext, opts *trustLogOptions) {
+	filter := hooks.TrustAuditFilter{
+		Repository:    opts.Repository,
+		NamespacePath: opts.NamespacePath,
+		Decision:      opts.Decision,
+		User:          opts.User}
+
+	if opts.Current {
+		repoDir, _, _ := ccm.AssertRepoRoot(ctx)
+		filter.Repository = repoDir
+	} else if strs.IsNotEmpty(filter.Repository) && !strings.ContainsAny(filter.Repository, "*?[") {
+		repoDir, err := filepath.Abs(filter.Repository)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not get absolute path of '%s'.", filter.Repository)
+		filter.Repository = filepath.ToSlash(repoDir)
+	}
+
+	if strs.IsNotEmpty(opts.Since) {
+		var err error
+		filter.Since, err = parseSince(opts.Since)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not parse '--since' value '%s'.", opts.Since)
+	}
+
+	entries, err := hooks.LoadTrustAuditEntries(ctx.InstallDir, &filter)
+	ctx.Log.AssertNoErrorF(err, "Could not load trust audit log '%s'.", hooks.GetTrustAuditLogFile(ctx.InstallDir))
+
+	if opts.AsJSON {
+		if entries == nil {
+			entries = []hooks.TrustAuditEntry{}
+		}
+
+		data, err := json.MarshalIndent(entries, "", "  ")
+		ctx.Log.AssertNoErrorPanicF(err, "Could not serialize trust audit log entries.")
+		_, err = ctx.Log.GetInfoWriter().Write(append(data, '\n'))
+		ctx.Log.AssertNoErrorF(err, "Could not write output.")
+
+		return
+	}
+
+	if len(entries) == 0 {
+		ctx.Log.Info("No trust audit log entries found.")
+
+		return
+	}
+
+	var sb strings.Builder
+	for i := range entries {
+		e := &entries[i]
+		_, _ = strs.FmtW(&sb, "%s %-8s by '%s' in '%s': %s\n",
+			e.Timestamp.Local().Format(time.RFC3339), e.Decision, e.User, e.Repository, e.Description())
+	}
+
+	_, err = ctx.Log.GetInfoWriter().Write([]byte(sb.String()))
+	ctx.Log.AssertNoErrorF(err, "Could not write output.")
+}
+
+// NewTrustLogCmd creates this new command.
+func NewTrustLogCmd(ctx *ccm.CmdContext) *cobra.Command {
+
+	opts := trustLogOptions{}
+
+	trustLog := &cobra.Command{
+		Use:   "log [flags]",
+		Short: "Show the trust audit log.",
+		Long: `Shows the trust audit log which records every change of the trust state
+of hooks, namely when hooks are trusted, disabled, revoked, declined or forgotten by
+the trust prompt, 'git hooks trust hooks', 'git hooks trust shared'
+or 'git hooks trust [revoke|forget|delete]'.
+
+Each entry contains the timestamp, the user, the repository, the namespace path
+and checksum of the hook and the decision ('trusted', 'disabled', 'revoked',
+'declined' or 'forgotten').
+Entries without namespace path apply to all hooks of the repository ('trust-all')
+or to all hooks of a shared repository at a revision ('trust shared').
+
+The entries can be filtered and the repository and namespace path filters
+can be glob patterns. Use '--json' to get a machine readable output.`,
+
+		PreRun: func(cmd *cobra.Command, args []string) {
+			ccm.PanicIfAnyArgs(ctx.Log)(cmd, args)
+
+			ctx.Log.PanicIfF(opts.Current && strs.IsNotEmpty(opts.Repository),
+				"You cannot use '--current' together with '--repository'.")
+
+			ctx.Log.PanicIfF(strs.IsNotEmpty(opts.Decision) &&
+				!strs.Includes(hooks.TrustDecisions, opts.Decision),
+				"Decision '%s' must be one of '%q'.", opts.Decision, hooks.TrustDecisions)
+		},
+
+		Run: func(cmd *cobra.Command, args []string) {
+			runTrustLog(ctx, &opts)
+		},
+	}
+
+	trustLog.Flags().StringVar(&opts.Repository, "repository", "",
+		"Only show entries of repositories matching this path or glob pattern.")
+
+	trustLog.Flags().BoolVar(&opts.Current, "current", false,
+		"Only show entries of the current repository.")
+
+	trustLog.Flags().StringVar(&opts.NamespacePath, "pattern", "",
+		"Only show entries with namespace paths matching this glob pattern.")
+
+	trustLog.Flags().StringVar(&opts.Decision, "decision", "",
+		"Only show entries with this decision\n"+
+			"('trusted', 'disabled', 'revoked', 'declined', 'forgotten').")
+
+	trustLog.Flags().StringVar(&opts.User, "user", "",
+		"Only show entries of this user.")
+
+	trustLog.Flags().StringVar(&opts.Since, "since", "",
+		"Only show entries since this duration ago (e.g. '24h') or date ('2006-01-02').")
+
+	trustLog.Flags().BoolVar(&opts.AsJSON, "json", false,
+		"Output the entries in JSON format.")
+
+	return ccm.SetCommandDefaults(ctx.Log, trustLog)
+}
@@ -0,0 +1,273 @@
+package trust
+
+import (
+	"os"
+	"strings"
+
+	ccm "github.com/gabyx/githooks/githooks/cmd/common"
+	"github.com/gabyx/githooks/githooks/cmd/list"
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/hooks"
+	"github.com/gabyx/githooks/githooks/prompt"
+	strs "github.com/gabyx/githooks/githooks/strings"
+
+	"github.com/spf13/cobra"
+)
+
+type trustReviewOptions struct {
+	UseStdin bool
+}
+
+// The decisions in the review.
+const (
+	reviewPending = iota
+	reviewTrust
+	reviewIgnore
+)
+
+type reviewedHook struct {
+	Hook     *hooks.Hook
+	Decision int
+}
+
+func formatReviewedHook(h *reviewedHook) string {
+	return strs.Fmt("\n %s '%s'", cm.ListItemLiteral, h.Hook.NamespacePath)
+}
+
+// getPendingHooks gets all active hooks which are not trusted.
+func getPendingHooks(allHooks []hooks.Hook) (pending []reviewedHook) {
+	for i := range allHooks {
+		if allHooks[i].Active && !allHooks[i].Trusted {
+			pending = append(pending, reviewedHook{Hook: &allHooks[i]})
+		}
+	}
+
+	return
+}
+
+func showHookContent(ctx *ccm.CmdContext, hook *hooks.Hook) {
+	data, err := os.ReadFile(hook.Path)
+	ctx.Log.AssertNoErrorPanicF(err, "Could not read hook '%s'.", hook.Path)
+
+	ctx.Log.InfoF("Content of hook '%s':\n%s", hook.NamespacePath, strings.TrimSuffix(string(data), "\n"))
+}
+
+func showHookDiff(ctx *ccm.CmdContext, checksums *hooks.ChecksumStore, hook *hooks.Hook) {
+	change, err := checksums.GetHookChange(hook.Path)
+	ctx.Log.AssertNoErrorPanicF(err, "Could not get changes of hook '%s'.", hook.Path)
+
+	if change.IsNew() {
+		ctx.Log.InfoF("No previously trusted content of hook '%s' is known.", hook.NamespacePath)
+
+		return
+	}
+
+	text := strs.Fmt("Changes of hook '%s' since last trusted [sha256: '%s']:",
+		hook.NamespacePath, change.PreviousChecksum)
+
+	if change.IsImageChanged() {
+		text += strs.Fmt("\nImage reference changed: '%s' -> '%s'", change.PreviousImage, change.Image)
+	}
+
+	ctx.Log.InfoF("%s\n%s", text, strings.TrimSuffix(change.Diff, "\n"))
+}
+
+// reviewHooks shows the review prompt for all pending hooks
+// and records the decisions.
+func reviewHooks(
+	ctx *ccm.CmdContext,
+	promptx prompt.IContext,
+	checksums *hooks.ChecksumStore,
+	pending []reviewedHook) {
+
+	for i := 0; i < len(pending); i++ {
+		h := &pending[i]
+
+		err := h.Hook.AssertChecksum()
+		ctx.Log.AssertNoErrorPanicF(err, "Could not compute SHA256 hash for hook '%s'.", h.Hook.Path)
+
+		state := "new"
+		if change, e := checksums.GetHookChange(h.Hook.Path); e == nil && !change.IsNew() {
+			state = "changed"
+		}
+		if expired, _ := checksums.IsExpired(h.Hook.Checksum); expired {
+			state = "expired"
+		}
+
+		question := strs.Fmt(
+			"Hook '%v/%v' [%s]:\n'%s'\n[sha256: '%s']\nTrust this hook?",
+			i+1, len(pending), state, h.Hook.NamespacePath, h.Hook.Checksum)
+
+		for {
+			answer, err := promptx.ShowOptions(question,
+				"(trust, ignore, Skip, view, diff, trust all remaining, quit)",
+				"t/i/S/v/d/a/q",
+				"Trust", "Ignore", "Skip", "View", "Diff", "Trust All Remaining", "Quit")
+			ctx.Log.AssertNoErrorPanicF(err, "Could not get review answer.")
+
+			switch answer {
+			case "t":
+				h.Decision = reviewTrust
+			case "i":
+				h.Decision = reviewIgnore
+			case "v":
+				showHookContent(ctx, h.Hook)
+
+				continue
+			case "d":
+				showHookDiff(ctx, checksums, h.Hook)
+
+				continue
+			case "a":
+				for j := i; j < len(pending); j++ {
+					pending[j].Decision = reviewTrust
+				}
+
+				return
+			case "q":
+				return
+			}
+
+			break
+		}
+	}
+}
+
+// applyReview writes the decisions of the review through
+// the checksum store and the user ignore patterns.
+func applyReview(
+	ctx *ccm.CmdContext,
+	repoDir string,
+	gitDirWorktree string,
+	state *list.ListingState,
+	pending []reviewedHook) {
+
+	var entries []hooks.TrustAuditEntry
+	ignoreCount := 0
+
+	for i := range pending {
+		h := &pending[i]
+
+		switch h.Decision {
+		case reviewTrust:
+			err := state.Checksums.SyncChecksumAdd(
+				hooks.ChecksumResult{
+					Checksum:      h.Hook.Checksum,
+					Path:          h.Hook.Path,
+					NamespacePath: h.Hook.NamespacePath})
+			ctx.Log.AssertNoErrorPanicF(err, "Could not sync checksum for hook '%s'.", h.Hook.Path)
+
+			entries = append(entries,
+				hooks.NewTrustAuditEntry(repoDir, h.Hook.NamespacePath, h.Hook.Checksum, hooks.TrustDecisionTrusted))
+
+		case reviewIgnore:
+			ignoreCount += state.Ignores.User.AddNamespacePathsUnique(h.Hook.NamespacePath)
+
+			entries = append(entries,
+				hooks.NewTrustAuditEntry(repoDir, h.Hook.NamespacePath, h.Hook.Checksum, hooks.TrustDecisionDisabled))
+		}
+	}
+
+	if ignoreCount != 0 {
+		err := hooks.StoreHookPatternsGitDir(state.Ignores.User, gitDirWorktree)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not store user ignore patterns.")
+	}
+
+	err := hooks.AppendTrustAuditEntries(ctx.InstallDir, entries...)
+	ctx.Log.AssertNoErrorF(err, "Could not write trust audit log.")
+}
+
+func runTrustReview(ctx *ccm.CmdContext, opts *trustReviewOptions) {
+	repoDir, gitDir, gitDirWorktree := ccm.AssertRepoRoot(ctx)
+
+	repoHooksDir := hooks.GetGithooksDir(repoDir)
+	hookNames := hooks.ManagedHookNames
+
+	state, shared, _ := list.PrepareListHookState(ctx, repoDir, repoHooksDir, gitDirWorktree, hookNames)
+	allHooks := getAllHooks(ctx.Log, hookNames, repoDir, gitDir, repoHooksDir, shared, state)
+
+	pending := getPendingHooks(allHooks)
+	if len(pending) == 0 {
+		ctx.Log.Info("All active hooks are trusted. Nothing to review.")
+
+		return
+	}
+
+	promptx := ctx.PromptCtx
+	if opts.UseStdin {
+		var err error
+		promptx, err = prompt.CreateContext(ctx.Log, false, true)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not create prompt.")
+	}
+
+	reviewHooks(ctx, promptx, state.Checksums, pending)
+
+	var trusted, ignored strings.Builder
+	for i := range pending {
+		switch pending[i].Decision {
+		case reviewTrust:
+			trusted.WriteString(formatReviewedHook(&pending[i]))
+		case reviewIgnore:
+			ignored.WriteString(formatReviewedHook(&pending[i]))
+		}
+	}
+
+	if trusted.Len() == 0 && ignored.Len() == 0 {
+		ctx.Log.Info("No hooks have been trusted or ignored.")
+
+		return
+	}
+
+	var summary strings.Builder
+	if trusted.Len() != 0 {
+		_, _ = strs.FmtW(&summary, "Hooks to trust:%s\n", trusted.String())
+	}
+	if ignored.Len() != 0 {
+		_, _ = strs.FmtW(&summary, "Hooks to ignore:%s\n", ignored.String())
+	}
+
+	answer, err := promptx.ShowOptions(summary.String()+"Apply these decisions?",
+		"(Yes, no)", "Y/n", "Yes", "No")
+	ctx.Log.AssertNoErrorPanicF(err, "Could not get review answer.")
+
+	if answer != "y" {
+		ctx.Log.Info("Discarded all decisions.")
+
+		return
+	}
+
+	applyReview(ctx, repoDir, gitDirWorktree, state, pending)
+	ctx.Log.Info("Applied all decisions.")
+}
+
+// NewTrustReviewCmd creates this new command.
+func NewTrustReviewCmd(ctx *ccm.CmdContext) *cobra.Command {
+
+	opts := trustReviewOptions{}
+
+	trustReview := &cobra.Command{
+		Use:   "review [flags]",
+		Short: "Review all active hooks which are not trusted.",
+		Long: `Interactively reviews all active hooks which are not trusted
+in the local repository and all shared repositories.
+
+For each hook its content can be shown ('view') as well as the
+changes since its content was last trusted ('diff').
+The hook can then be marked as trusted or ignored or be skipped.
+All decisions are applied at the end of the review,
+trusted hooks are added to the checksum store and
+ignored hooks are added to the user ignore patterns
+(see 'git hooks ignore').`,
+
+		PreRun: ccm.PanicIfAnyArgs(ctx.Log),
+
+		Run: func(cmd *cobra.Command, args []string) {
+			runTrustReview(ctx, &opts)
+		},
+	}
+
+	trustReview.Flags().BoolVar(&opts.UseStdin, "stdin", false,
+		"Read the answers of the review from stdin.")
+
+	return ccm.SetCommandDefaults(ctx.Log, trustReview)
+}
@@ -0,0 +1,158 @@
+package trust
+
+import (
+	ccm "github.com/gabyx/githooks/githooks/cmd/common"
+	"github.com/gabyx/githooks/githooks/cmd/list"
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
+	"github.com/gabyx/githooks/githooks/hooks"
+	strs "github.com/gabyx/githooks/githooks/strings"
+
+	"github.com/spf13/cobra"
+)
+
+type trustSharedOptions struct {
+	All      bool
+	Reset    bool
+	Commit   string
+	SignedBy []string
+}
+
+func runTrustShared(ctx *ccm.CmdContext, opts *trustSharedOptions, urls []string) {
+	repoDir, _, gitDirWorktree := ccm.AssertRepoRoot(ctx)
+
+	repoHooksDir := hooks.GetGithooksDir(repoDir)
+	state, shared, _ := list.PrepareListHookState(ctx, repoDir, repoHooksDir, gitDirWorktree, nil)
+
+	var repos []*hooks.SharedRepo
+	var configured []string
+	for i := range shared {
+		for j := range shared[i] {
+			sh := &shared[i][j]
+			configured = append(configured, sh.OriginalURL)
+
+			if opts.All || strs.Includes(urls, sh.OriginalURL) {
+				repos = append(repos, sh)
+			}
+		}
+	}
+
+	if opts.Reset {
+		if opts.All {
+			urls = nil
+			for _, sh := range repos {
+				urls, _ = strs.AppendUnique(urls, sh.OriginalURL)
+			}
+		}
+
+		var entries []hooks.TrustAuditEntry
+		for _, url := range urls {
+			removed, err := state.Checksums.SyncTrustedRevisionRemove(url)
+			ctx.Log.AssertNoErrorPanicF(err, "Could not remove trusted revisions of '%s'.", url)
+
+			if removed != 0 {
+				ctx.Log.InfoF("Removed '%v' trusted revisions of shared hooks in '%s'.", removed, url)
+				entries = append(entries,
+					hooks.NewTrustAuditSharedEntry(repoDir, url, "", hooks.TrustDecisionRevoked))
+			} else {
+				ctx.Log.InfoF("No trusted revisions of shared hooks in '%s'.", url)
+			}
+		}
+
+		err := hooks.AppendTrustAuditEntries(ctx.InstallDir, entries...)
+		ctx.Log.AssertNoErrorF(err, "Could not write trust audit log.")
+
+		return
+	}
+
+	for _, url := range urls {
+		ctx.Log.PanicIfF(!strs.Includes(configured, url),
+			"Shared hooks '%s' are not configured in this repository.", url)
+	}
+
+	var entries []hooks.TrustAuditEntry
+	for _, sh := range repos {
+		rev := hooks.TrustedRevision{URL: sh.OriginalURL, SignedBy: opts.SignedBy}
+
+		if len(opts.SignedBy) == 0 {
+			ctx.Log.PanicIfF(!cm.IsDirectory(sh.RepositoryDir),
+				"Shared hooks '%s' are not available. To fix, run:\n"+
+					"$ git hooks shared update", sh.OriginalURL)
+
+			ref := git.HEAD
+			if strs.IsNotEmpty(opts.Commit) {
+				ref = opts.Commit
+			}
+
+			var err error
+			rev.Commit, err = git.GetCommitSHA(git.NewCtxSanitizedAt(sh.RepositoryDir), ref+"^{commit}")
+			ctx.Log.AssertNoErrorPanicF(err, "Could not resolve commit '%s' in '%s'.", ref, sh.RepositoryDir)
+
+			if _, modified, e := hooks.GetSharedRevision(sh); e == nil && len(modified) != 0 {
+				ctx.Log.WarnF("Shared hooks in '%s' have '%v' modified files.\n"+
+					"The trusted revision only applies without local modifications.",
+					sh.OriginalURL, len(modified))
+			}
+		}
+
+		err := state.Checksums.SyncTrustedRevisionAdd(rev)
+		ctx.Log.AssertNoErrorPanicF(err, "Could not store trusted revision of '%s'.", sh.OriginalURL)
+
+		ctx.Log.InfoF("Trusted all shared hooks in '%s' at %s.", sh.OriginalURL, rev.Description())
+		entries = append(entries,
+			hooks.NewTrustAuditSharedEntry(repoDir, sh.OriginalURL, rev.Description(), hooks.TrustDecisionTrusted))
+	}
+
+	err := hooks.AppendTrustAuditEntries(ctx.InstallDir, entries...)
+	ctx.Log.AssertNoErrorF(err, "Could not write trust audit log.")
+}
+
+// NewTrustSharedCmd creates this new command.
+func NewTrustSharedCmd(ctx *ccm.CmdContext) *cobra.Command {
+
+	opts := trustSharedOptions{}
+
+	trustShared := &cobra.Command{
+		Use:   "shared [flags] [url]...",
+		Short: "Trust all hooks of shared repositories at a revision.",
+		Long: `Trust all hooks of the shared repositories given by their URL
+(as listed by 'git hooks shared list') at a specific revision.
+
+By default the checked out commit is trusted. With '--commit' another commit
+and with '--signed-by' any commit signed by one of the given keys
+(GPG key ids or fingerprints or SSH key fingerprints or principals) is trusted.
+A revision of a shared repository with local modifications is never trusted.
+
+The trusted revisions are stored alongside the trusted checksums and
+are shown in 'git hooks list'.`,
+
+		PreRun: func(cmd *cobra.Command, args []string) {
+			count := len(args)
+			if opts.All {
+				count++
+			}
+
+			ctx.Log.PanicIfF(count == 0, "You need to provide at least one URL or '--all'.")
+			ctx.Log.PanicIfF(strs.IsNotEmpty(opts.Commit) && len(opts.SignedBy) != 0,
+				"You cannot use '--commit' together with '--signed-by'.")
+		},
+
+		Run: func(cmd *cobra.Command, args []string) {
+			runTrustShared(ctx, &opts, args)
+		},
+	}
+
+	trustShared.Flags().BoolVar(&opts.All, "all", false,
+		"If the action applies to all shared repositories.")
+
+	trustShared.Flags().StringVar(&opts.Commit, "commit", "",
+		"The commit to trust instead of the checked out one.")
+
+	trustShared.Flags().StringArrayVar(&opts.SignedBy, "signed-by", nil,
+		"Trust any commit signed by this key.")
+
+	trustShared.Flags().BoolVar(&opts.Reset, "reset", false,
+		"Remove all trusted revisions of the shared repositories.")
+
+	return ccm.SetCommandDefaults(ctx.Log, trustShared)
+}
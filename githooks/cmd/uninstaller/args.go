@@ -6,7 +6,12 @@ type Arguments struct {
 
 	InternalPostDispatch bool
 
+	DryRun         bool
 	NonInteractive bool
 
 	UseStdin bool
+
+	Repos            []string // Only uninstall from these repositories.
+	KeepSharedClones bool     // Keep the shared hook repository clones.
+	KeepTrust        bool     // Keep the trusted checksums and trust settings.
 }
@@ -2,6 +2,7 @@ package uninstaller
 
 import (
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -64,15 +65,37 @@ func defineArguments(cmd *cobra.Command, vi *viper.Viper) {
 	cm.AssertNoErrorPanic(cmd.PersistentFlags().MarkHidden("config"))
 
 	// User commands
+	cmd.PersistentFlags().Bool("dry-run", false,
+		"Dry run the uninstallation showing all files\n"+
+			"and Git config keys which would be removed.")
 	cmd.PersistentFlags().Bool(
 		"non-interactive", false,
 		"Run the uninstallation non-interactively\n"+
 			"without showing prompts.")
+	cmd.PersistentFlags().StringSlice(
+		"repo", nil,
+		"Only uninstall Githooks from these repositories\n"+
+			"and keep the global installation.")
+	cm.AssertNoErrorPanic(cmd.MarkPersistentFlagDirname("repo"))
+	cmd.PersistentFlags().Bool(
+		"keep-shared", false,
+		"Keep the shared hook repository clones.")
+	cmd.PersistentFlags().Bool(
+		"keep-trust", false,
+		"Keep the trusted checksums and trust settings in repositories.")
 
 	cm.AssertNoErrorPanic(
 		vi.BindPFlag("config", cmd.PersistentFlags().Lookup("config")))
+	cm.AssertNoErrorPanic(
+		vi.BindPFlag("dryRun", cmd.PersistentFlags().Lookup("dry-run")))
 	cm.AssertNoErrorPanic(
 		vi.BindPFlag("nonInteractive", cmd.PersistentFlags().Lookup("non-interactive")))
+	cm.AssertNoErrorPanic(
+		vi.BindPFlag("repos", cmd.PersistentFlags().Lookup("repo")))
+	cm.AssertNoErrorPanic(
+		vi.BindPFlag("keepSharedClones", cmd.PersistentFlags().Lookup("keep-shared")))
+	cm.AssertNoErrorPanic(
+		vi.BindPFlag("keepTrust", cmd.PersistentFlags().Lookup("keep-trust")))
 
 	setupMockFlags(cmd, vi)
 }
@@ -84,6 +107,12 @@ func setupSettings(log cm.ILogContext, gitx *git.Context, args *Arguments) (Sett
 
 	log.AssertNoErrorPanic(err, "Could not get current working directory.")
 
+	for i := range args.Repos {
+		repo, err := filepath.Abs(args.Repos[i])
+		log.AssertNoErrorPanicF(err, "Could not get absolute path of '%s'.", args.Repos[i])
+		args.Repos[i] = filepath.ToSlash(repo)
+	}
+
 	if !args.NonInteractive {
 		promptx, err = prompt.CreateContext(log, false, args.UseStdin)
 		log.AssertNoErrorF(err, "Prompt setup failed -> using fallback.")
@@ -167,7 +196,7 @@ func uninstallFromExistingRepos(
 	log cm.ILogContext,
 	gitx *git.Context,
 	lfsHooksCache hooks.LFSHooksCache,
-	nonInteractive bool,
+	args *Arguments,
 	uninstalledRepos UninstallSet,
 	registeredRepos *hooks.RegisterRepos,
 	uiSettings *UISettings) {
@@ -176,12 +205,12 @@ func uninstallFromExistingRepos(
 	install.PromptExistingRepos(
 		log,
 		gitx,
-		nonInteractive,
+		args.NonInteractive,
 		true,
 		uiSettings.PromptCtx,
 		func(gitDir string) {
 
-			if install.UninstallFromRepo(log, gitDir, lfsHooksCache, true) {
+			if install.UninstallFromRepo(log, gitDir, lfsHooksCache, true, args.KeepTrust, args.DryRun) {
 
 				registeredRepos.Remove(gitDir)
 				uninstalledRepos.Insert(gitDir)
@@ -192,7 +221,7 @@ func uninstallFromExistingRepos(
 func uninstallFromRegisteredRepos(
 	log cm.ILogContext,
 	lfsHooksCache hooks.LFSHooksCache,
-	nonInteractive bool,
+	args *Arguments,
 	uninstalledRepos UninstallSet,
 	registeredRepos *hooks.RegisterRepos,
 	uiSettings *UISettings) {
@@ -210,11 +239,11 @@ func uninstallFromRegisteredRepos(
 	install.PromptRegisteredRepos(
 		log,
 		dirsWithNoUninstalls,
-		nonInteractive,
+		args.NonInteractive,
 		true,
 		uiSettings.PromptCtx,
 		func(gitDir string) {
-			if install.UninstallFromRepo(log, gitDir, lfsHooksCache, true) {
+			if install.UninstallFromRepo(log, gitDir, lfsHooksCache, true, args.KeepTrust, args.DryRun) {
 
 				registeredRepos.Remove(gitDir)
 				uninstalledRepos.Insert(gitDir)
@@ -222,7 +251,36 @@ func uninstallFromRegisteredRepos(
 		})
 }
 
-func cleanTemplateDir(log cm.ILogContext, gitx *git.Context, lfsHooksCache hooks.LFSHooksCache) {
+// uninstallFromGivenRepos uninstalls only from the repositories given
+// on the command line and keeps the global installation.
+func uninstallFromGivenRepos(
+	log cm.ILogContext,
+	settings *Settings,
+	args *Arguments) {
+
+	for _, repo := range args.Repos {
+		gitDir, err := git.NewCtxAt(repo).GetGitDirCommon()
+		if !log.AssertNoErrorF(err, "Path '%s' is not a Git repository.", repo) {
+			continue
+		}
+
+		if install.UninstallFromRepo(
+			log, gitDir, settings.LFSHooksCache, true, args.KeepTrust, args.DryRun) {
+
+			settings.RegisteredGitDirs.Remove(gitDir)
+			settings.UninstalledGitDirs.Insert(gitDir)
+		}
+	}
+
+	if args.DryRun {
+		return
+	}
+
+	err := settings.RegisteredGitDirs.Store(settings.InstallDir)
+	log.AssertNoErrorF(err, "Could not store register file in '%s'.", settings.InstallDir)
+}
+
+func cleanTemplateDir(log cm.ILogContext, gitx *git.Context, lfsHooksCache hooks.LFSHooksCache, dryRun bool) {
 	installMode := install.GetInstallMode(gitx)
 
 	hookTemplateDir, err := install.FindHookTemplateDir(gitx, installMode)
@@ -232,6 +290,11 @@ func cleanTemplateDir(log cm.ILogContext, gitx *git.Context, lfsHooksCache hooks
 		log.ErrorF(
 			"Git hook templates directory not found.\n" +
 				"Installation is corrupt!")
+	} else if dryRun {
+		files, err := hooks.GetRunWrappers(hookTemplateDir)
+		log.AssertNoErrorF(err, "Could not get Githooks run-wrappers in\n'%s'.", hookTemplateDir)
+		install.ReportDryRunRemovals(log,
+			strs.Fmt("run-wrappers in template directory '%s'", hookTemplateDir), files, nil)
 	} else {
 		_, err = hooks.UninstallRunWrappers(hookTemplateDir, lfsHooksCache)
 		log.AssertNoErrorF(err, "Could not uninstall Githooks run-wrappers in\n'%s'.", hookTemplateDir)
@@ -291,31 +354,52 @@ func cleanReleaseClone(
 	}
 }
 
-func cleanGitConfig(log cm.ILogContext, gitx *git.Context) {
+func cleanRollbackState(
+	log cm.ILogContext,
+	installDir string) {
+
+	rollbackDir := hooks.GetRollbackDir(installDir)
+
+	if cm.IsDirectory(rollbackDir) {
+		err := os.RemoveAll(rollbackDir)
+		log.AssertNoErrorF(err,
+			"Could not delete rollback directory '%s'.", rollbackDir)
+	}
+}
+
+// getGlobalGitConfigKeys gets all global Git config keys which are set and get removed.
+func getGlobalGitConfigKeys(gitx *git.Context, keepTrust bool) (keys []string) {
 
 	// Remove core.hooksPath if we are using it.
 	pathForUseCoreHooksPath := gitx.GetConfig(hooks.GitCKPathForUseCoreHooksPath, git.GlobalScope)
 	coreHooksPath := gitx.GetConfig(git.GitCKCoreHooksPath, git.GlobalScope)
 
-	if coreHooksPath == pathForUseCoreHooksPath {
-		err := gitx.UnsetConfig(git.GitCKCoreHooksPath, git.GlobalScope)
-		log.AssertNoError(err, "Could not unset global Git config 'core.hooksPath'.")
+	if strs.IsNotEmpty(coreHooksPath) && coreHooksPath == pathForUseCoreHooksPath {
+		keys = append(keys, git.GitCKCoreHooksPath)
 	}
 
-	// Remove all global configs
-	for _, k := range hooks.GetGlobalGitConfigKeys() {
+	// Remove all global configs and legacy values.
+	all := append(hooks.GetGlobalGitConfigKeys(), hooks.GetLegacyGitConfigKeys()...)
+
+	for _, k := range all {
+		if keepTrust && strs.Includes(hooks.GetTrustGitConfigKeys(), k) {
+			continue
+		}
+
+		if len(gitx.GetConfigAll(k, git.GlobalScope)) != 0 {
+			keys = append(keys, k)
+		}
+	}
+
+	return
+}
+
+func cleanGitConfig(log cm.ILogContext, gitx *git.Context, keepTrust bool) {
+	for _, k := range getGlobalGitConfigKeys(gitx, keepTrust) {
 
 		log.AssertNoErrorF(gitx.UnsetConfig(k, git.GlobalScope),
 			"Could not unset global Git config '%s'.", k)
 	}
-
-	// Remove legacy values
-	k := "githooks.checksumCacheDir"
-	log.AssertNoErrorF(gitx.UnsetConfig(k, git.GlobalScope),
-		"Could not unset global Git config '%s'.", k)
-	k = "githooks.maintainOnlyServerHooks"
-	log.AssertNoErrorF(gitx.UnsetConfig(k, git.GlobalScope),
-		"Could not unset global Git config '%s'.", k)
 }
 
 func cleanRegister(log cm.ILogContext, installDir string) {
@@ -329,6 +413,30 @@ func cleanRegister(log cm.ILogContext, installDir string) {
 	}
 }
 
+// reportGlobalUninstall reports all files and global Git config keys
+// which the global uninstall steps would remove.
+func reportGlobalUninstall(log cm.ILogContext, settings *Settings, args *Arguments) {
+	dirs := []string{
+		hooks.GetReleaseCloneDir(settings.InstallDir),
+		hooks.GetRollbackDir(settings.InstallDir),
+		hooks.GetBinaryDir(settings.InstallDir),
+		hooks.GetRegisterFile(settings.InstallDir)}
+
+	if !args.KeepSharedClones {
+		dirs = append([]string{hooks.GetSharedDir(settings.InstallDir)}, dirs...)
+	}
+
+	files := strs.Filter(dirs, func(f string) bool {
+		exists, _ := cm.IsPathExisting(f)
+
+		return exists
+	})
+
+	install.ReportDryRunRemovals(log,
+		strs.Fmt("the installation in '%s'", settings.InstallDir),
+		files, getGlobalGitConfigKeys(settings.Gitx, args.KeepTrust))
+}
+
 func runUninstallSteps(
 	log cm.ILogContext,
 	settings *Settings,
@@ -343,11 +451,17 @@ func runUninstallSteps(
 
 	log.InfoF("Running uninstall at version '%s' ...", build.BuildVersion)
 
+	if len(args.Repos) != 0 {
+		uninstallFromGivenRepos(log, settings, args)
+
+		return
+	}
+
 	uninstallFromExistingRepos(
 		log,
 		settings.Gitx,
 		settings.LFSHooksCache,
-		args.NonInteractive,
+		args,
 		settings.UninstalledGitDirs,
 		&settings.RegisteredGitDirs,
 		uiSettings)
@@ -355,19 +469,28 @@ func runUninstallSteps(
 	uninstallFromRegisteredRepos(
 		log,
 		settings.LFSHooksCache,
-		args.NonInteractive,
+		args,
 		settings.UninstalledGitDirs,
 		&settings.RegisteredGitDirs,
 		uiSettings)
 
-	cleanTemplateDir(log, settings.Gitx, settings.LFSHooksCache)
+	cleanTemplateDir(log, settings.Gitx, settings.LFSHooksCache, args.DryRun)
+
+	if args.DryRun {
+		reportGlobalUninstall(log, settings, args)
 
-	cleanSharedClones(log, settings.InstallDir)
+		return
+	}
+
+	if !args.KeepSharedClones {
+		cleanSharedClones(log, settings.InstallDir)
+	}
 	cleanReleaseClone(log, settings.InstallDir)
+	cleanRollbackState(log, settings.InstallDir)
 	cleanBinaries(log, settings.InstallDir, settings.TempDir)
 	cleanRegister(log, settings.InstallDir)
 
-	cleanGitConfig(log, settings.Gitx)
+	cleanGitConfig(log, settings.Gitx, args.KeepTrust)
 }
 
 func runUninstall(ctx *ccm.CmdContext, vi *viper.Viper) {
@@ -392,7 +515,11 @@ func runUninstall(ctx *ccm.CmdContext, vi *viper.Viper) {
 	runUninstallSteps(log, &settings, &uiSettings, &args)
 
 	if ctx.LogStats.ErrorCount() == 0 {
-		thankYou(log)
+		if args.DryRun {
+			log.Info("[dry run] Nothing has been uninstalled.")
+		} else {
+			thankYou(log)
+		}
 	} else {
 		log.ErrorF("Tried my best at uninstalling, but\n"+
 			" • %v errors\n"+
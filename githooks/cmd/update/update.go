@@ -5,7 +5,10 @@ import (
 	ccm "github.com/gabyx/githooks/githooks/cmd/common"
 	"github.com/gabyx/githooks/githooks/cmd/config"
 	"github.com/gabyx/githooks/githooks/cmd/installer"
+	"github.com/gabyx/githooks/githooks/git"
+	"github.com/gabyx/githooks/githooks/hooks"
 	"github.com/gabyx/githooks/githooks/prompt"
+	strs "github.com/gabyx/githooks/githooks/strings"
 	"github.com/gabyx/githooks/githooks/updates"
 
 	"github.com/spf13/cobra"
@@ -22,8 +25,18 @@ func runUpdate(
 	case setOpts.Set || setOpts.Unset:
 		config.RunUpdate(ctx, setOpts)
 
+	case hooks.IsOfflineMode(ctx.GitX, git.Traverse, true):
+		ctx.Log.WarnF("Githooks is in offline mode: update check skipped.")
+
 	default:
 
+		if skipped := updates.GetSkippedUpdateVersion(ctx.GitX); strs.IsNotEmpty(skipped) {
+			// Running the update manually opts in to the rolled back version again.
+			err := updates.SetSkippedUpdateVersion("", true)
+			ctx.Log.AssertNoErrorF(err, "Could not reset the skipped version '%s'.", skipped)
+			ctx.Log.InfoF("Version '%s' which was rolled back is not skipped anymore.", skipped)
+		}
+
 		var promptx prompt.IContext
 		if !nonInteractive {
 			promptx = ctx.PromptCtx
@@ -61,6 +74,24 @@ func runUpdate(
 	}
 }
 
+func runRollback(ctx *ccm.CmdContext) {
+	state, exists, err := updates.LoadRollbackState(ctx.InstallDir)
+	ctx.Log.AssertNoErrorPanicF(err, "Could not load the previous version.")
+	ctx.Log.PanicIfF(!exists, "No previous version to roll back to exists.")
+
+	_, err = updates.Rollback(ctx.InstallDir, build.BuildTag)
+	ctx.Log.AssertNoErrorPanicF(err, "Could not roll back to version '%s'.", state.BuildVersion)
+
+	err = installer.RunRestoredInstaller(ctx.Log, ctx.InstallDir)
+	ctx.Log.AssertNoErrorPanicF(err, "Could not run the installer of version '%s'.", state.BuildVersion)
+
+	ctx.Log.InfoF(
+		"Rolled back from version '%s' to version '%s' [commit: '%s'].\n"+
+			"Update checks skip version '%s' until you run\n"+
+			"  $ git hooks update",
+		build.GetBuildVersion().String(), state.BuildVersion, state.CommitSHA, build.BuildTag)
+}
+
 // NewCmd creates this new command.
 func NewCmd(ctx *ccm.CmdContext) *cobra.Command {
 
@@ -68,6 +99,7 @@ func NewCmd(ctx *ccm.CmdContext) *cobra.Command {
 	no := false
 	yesMajor := false
 	usePreRelease := false
+	rollback := false
 
 	setOpts := config.SetOptions{}
 
@@ -81,9 +113,23 @@ the installer is executed to update to the latest version.
 
 The '--enable' and '--disable' options enable or disable
 the automatic checks that would normally run daily
-after a successful commit event.`,
+after a successful commit event.
+
+The '--rollback' option restores the previous version
+kept by the last update and runs its installer non-interactively.
+An update also rolls back automatically if the smoke check of the
+new runner fails. The rolled back version is skipped by update checks
+until this command is run again without '--rollback'.`,
 		Run: func(cmd *cobra.Command, args []string) {
 
+			if rollback {
+				ctx.Log.PanicIfF(yes || no || yesMajor || usePreRelease || setOpts.Set || setOpts.Unset,
+					"Option '--rollback' cannot be used with other options.")
+				runRollback(ctx)
+
+				return
+			}
+
 			nonInteractive := false
 			nonInteractiveAccept := updates.AcceptNonInteractiveNone
 
@@ -113,6 +159,8 @@ after a successful commit event.`,
 		"Always accepts a new update (non-interactive, all versions).")
 	updateCmd.Flags().BoolVar(&usePreRelease, "use-pre-release", false,
 		"Also discover pre-release versions when updating.")
+	updateCmd.Flags().BoolVar(&rollback, "rollback", false,
+		"Roll back to the previous version kept by the last update.")
 	updateCmd.Flags().BoolVar(&setOpts.Set, "enable", false, "Enable daily Githooks update checks.")
 	updateCmd.Flags().BoolVar(&setOpts.Unset, "disable", false, "Disable daily Githooks update checks.")
 
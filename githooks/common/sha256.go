@@ -4,9 +4,44 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"io"
+	"os"
+
+	strs "github.com/gabyx/githooks/githooks/strings"
 )
 
-// GetSHA256Hash gets the SHA1 hash of a string.
+// GetSHA256HashFile gets the SHA256 hash of a file.
+// It mimics `git hash-object` in a SHA256 repository.
+func GetSHA256HashFile(path string) (sha string, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return
+	}
+
+	hash := sha256.New()
+
+	// Mimic a Git SHA256 hash.
+	_, err = strs.FmtW(hash, "blob %v\u0000", stat.Size())
+	if err != nil {
+		return "", err
+	}
+
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return
+	}
+
+	sha = hex.EncodeToString(hash.Sum(nil))
+
+	return
+}
+
+// GetSHA256Hash gets the SHA256 hash of a string.
 func GetSHA256Hash(reader io.Reader) (string, error) {
 	h := sha256.New()
 	if _, err := io.Copy(h, reader); err != nil {
@@ -6,11 +6,14 @@ import (
 	"io"
 	"os"
 	"path"
+	"path/filepath"
 	"runtime"
+	"strings"
 )
 
 // ExtractTarGz extracts `.tar.gz` streams to `baseDir` which must not exist.
-// Overwrites everything.
+// Overwrites everything. Only directories, regular files and symlinks inside `baseDir` are extracted.
+// Symlinks are created last and only if they resolve to an existing path inside `baseDir`.
 func ExtractTarGz(gzipStream io.Reader, baseDir string) (paths []string, err error) {
 	uncompressedStream, err := gzip.NewReader(gzipStream)
 	if err != nil {
@@ -19,6 +22,7 @@ func ExtractTarGz(gzipStream io.Reader, baseDir string) (paths []string, err err
 
 	tarReader := tar.NewReader(uncompressedStream)
 	var header *tar.Header
+	var links []*tar.Header
 
 	err = os.MkdirAll(baseDir, DefaultFileModeDirectory)
 	if err != nil {
@@ -36,6 +40,14 @@ func ExtractTarGz(gzipStream io.Reader, baseDir string) (paths []string, err err
 
 		outPath := path.Join(baseDir, header.Name)
 
+		// Check for path traversal ("tar slip").
+		if strings.Contains(header.Name, "\\") ||
+			(outPath != path.Clean(baseDir) && !strings.HasPrefix(outPath, path.Clean(baseDir)+"/")) {
+			err = ErrorF("Tar extracting: illegal file path '%s'.", header.Name)
+
+			return
+		}
+
 		switch header.Typeflag {
 		case tar.TypeDir:
 
@@ -70,6 +82,10 @@ func ExtractTarGz(gzipStream io.Reader, baseDir string) (paths []string, err err
 
 			paths = append(paths, outPath)
 
+		case tar.TypeSymlink:
+			// Create them after all files such that nothing is written through them.
+			links = append(links, header)
+
 		default:
 			err = ErrorF("Tar extracting: unknown type: '%v' in '%v'",
 				header.Typeflag,
@@ -79,5 +95,108 @@ func ExtractTarGz(gzipStream io.Reader, baseDir string) (paths []string, err err
 		}
 	}
 
+	return extractSymlinks(links, baseDir, paths)
+}
+
+// extractSymlinks creates the symlinks `links` inside `baseDir`.
+// Symlinks to other symlinks are created as soon as their target exists.
+func extractSymlinks(links []*tar.Header, baseDir string, paths []string) ([]string, error) {
+	realBaseDir, err := filepath.EvalSymlinks(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for len(links) != 0 {
+		var pending []*tar.Header
+
+		for _, link := range links {
+			outPath := path.Join(baseDir, link.Name)
+
+			if path.IsAbs(link.Linkname) || strings.Contains(link.Linkname, "\\") {
+				return nil, ErrorF("Tar extracting: illegal symlink '%s' -> '%s'.", link.Name, link.Linkname)
+			}
+
+			// Resolve without cleaning, since `..` after a symlink
+			// does not cancel the previous path component.
+			target, e := filepath.EvalSymlinks(path.Dir(outPath) + "/" + link.Linkname)
+			if e != nil {
+				pending = append(pending, link)
+
+				continue
+			} else if target != realBaseDir && !strings.HasPrefix(target, realBaseDir+string(filepath.Separator)) {
+				return nil, ErrorF("Tar extracting: illegal symlink '%s' -> '%s'.", link.Name, link.Linkname)
+			}
+
+			if e = os.Symlink(link.Linkname, outPath); e != nil {
+				return nil, e
+			}
+
+			paths = append(paths, outPath)
+		}
+
+		if len(pending) == len(links) {
+			return nil, ErrorF("Tar extracting: symlink '%s' -> '%s' points to nothing.",
+				pending[0].Name, pending[0].Linkname)
+		}
+
+		links = pending
+	}
+
 	return paths, nil
 }
+
+// CreateTarGz writes all directories, regular files and symlinks in `baseDir`
+// as a `.tar.gz` stream to `writer`. Paths are relative to `baseDir`.
+func CreateTarGz(baseDir string, writer io.Writer) (err error) {
+	gzipWriter := gzip.NewWriter(writer)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err = filepath.Walk(baseDir, func(p string, info os.FileInfo, e error) error {
+		if e != nil {
+			return e
+		}
+
+		rel, e := filepath.Rel(baseDir, p)
+		if e != nil || rel == "." {
+			return e
+		}
+
+		isLink := info.Mode()&os.ModeSymlink != 0
+		if !info.IsDir() && !info.Mode().IsRegular() && !isLink {
+			return ErrorF("Tar creating: unsupported file type of '%s'.", p)
+		}
+
+		link := ""
+		if isLink {
+			if link, e = os.Readlink(p); e != nil {
+				return e
+			}
+		}
+
+		header, e := tar.FileInfoHeader(info, filepath.ToSlash(link))
+		if e != nil {
+			return e
+		}
+		header.Name = filepath.ToSlash(rel)
+
+		if e = tarWriter.WriteHeader(header); e != nil || info.IsDir() || isLink {
+			return e
+		}
+
+		file, e := os.Open(p)
+		if e != nil {
+			return e
+		}
+		defer file.Close()
+
+		if _, e = io.Copy(tarWriter, file); e != nil {
+			return CombineErrors(ErrorF("Copy of data from '%s' failed", p), e)
+		}
+
+		return nil
+	})
+
+	err = CombineErrors(err, tarWriter.Close(), gzipWriter.Close())
+
+	return
+}
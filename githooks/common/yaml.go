@@ -27,6 +27,21 @@ func LoadYAML(file string, repr interface{}) error {
 	return nil
 }
 
+// LoadYAMLStrict loads and parses a YAML file into a representation
+// and fails on unknown or duplicate fields.
+func LoadYAMLStrict(file string, repr interface{}) error {
+	bytes, err := os.ReadFile(file)
+	if err != nil {
+		return ErrorF("Could not read file '%s'.", file)
+	}
+
+	if err := yaml.UnmarshalWithOptions(bytes, repr, yaml.Strict()); err != nil {
+		return CombineErrors(ErrorF("Could not unmarshal file '%s'.", file), err)
+	}
+
+	return nil
+}
+
 // StoreYAML stores a representation in a JSON file.
 func StoreYAML(file string, repr interface{}) error {
 	yamlFile, err := os.OpenFile(file, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0664) // nolint: gomnd
@@ -4,6 +4,7 @@ package container
 type ContainerizedExecutable struct {
 	containerType ContainerManagerType
 	usedVolumes   bool
+	noPull        bool // If the image is never pulled.
 
 	Cmd string // The command.
 
@@ -49,6 +50,13 @@ func (e *ContainerizedExecutable) ResolveExitCode(exitCode int) string {
 	if e.containerType == ContainerManagerTypeV.Docker {
 		switch exitCode {
 		case 125: // nolint: gomnd
+			if e.noPull {
+				return "The docker daemon reported an error.\n" +
+					"Note: The image is not pulled (offline mode or locally built image)\n" +
+					"and might not exist locally. Build or pull it (when online) with:\n" +
+					"  $ git hooks images update"
+			}
+
 			return "The docker daemon reported an error.\n" +
 				"Note: If you are inside a container ALREADY and want\n" +
 				"to run hooks containerized (docker-in-docker) you can ONLY do\n" +
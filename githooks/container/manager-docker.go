@@ -49,7 +49,7 @@ func (m *ManagerDocker) ImageBuild(
 		"build",
 		"-f", dockerfile,
 		"-t", ref,
-		"--label", strs.Fmt("githooks-version=%v", build.GetBuildVersion().String())}
+		"--label", strs.Fmt("%s=%v", ImageLabelVersion, build.GetBuildVersion().String())}
 
 	if strs.IsNotEmpty(stage) {
 		cmd = append(cmd, "--target", stage)
@@ -67,23 +67,52 @@ func (m *ManagerDocker) ImageExists(ref string) (exists bool, err error) {
 	return len(out) != 0, err
 }
 
+// ImageLabel gets the value of the label `label` of the image with reference `ref`.
+// The value is empty if the label is not set.
+func (m *ManagerDocker) ImageLabel(ref string, label string) (string, error) {
+	out, err := m.cmdCtx.Get("image", "inspect", "--format",
+		strs.Fmt("{{ index .Config.Labels %q }}", label), ref)
+	if err != nil {
+		return "", err
+	}
+
+	out = strings.TrimSpace(out)
+	if out == "<no value>" {
+		out = ""
+	}
+
+	return out, nil
+}
+
 // ImageRemove removes an image with reference `ref`.
 func (m *ManagerDocker) ImageRemove(ref string) (err error) {
 	return m.cmdCtx.Check("image", "rm", ref)
 }
 
+// ImageSave saves the images with references `refs` to the archive `file`.
+func (m *ManagerDocker) ImageSave(file string, refs ...string) (err error) {
+	return m.cmdCtx.Check(append([]string{"image", "save", "-o", file}, refs...)...)
+}
+
+// ImageLoad loads all images from the archive `file`.
+func (m *ManagerDocker) ImageLoad(file string) (err error) {
+	return m.cmdCtx.Check("image", "load", "-i", file)
+}
+
 func resolveWSBasePath(envValue string, dirname string) string {
 	return strings.ReplaceAll(envValue, "${repository-dir-name}", dirname)
 }
 
 // NewHookRunExec runs a hook over a container.
+// If `noPull` is set, the image `ref` is never pulled and must exist.
 func (m *ManagerDocker) NewHookRunExec(
 	ref string,
 	workspaceDir string,
 	workspaceHookDir string,
 	hookExec cm.IExecutable,
+	noPull bool,
 ) (cm.IExecutable, error) {
-	containerExec := ContainerizedExecutable{containerType: ContainerManagerTypeV.Docker}
+	containerExec := ContainerizedExecutable{containerType: ContainerManagerTypeV.Docker, noPull: noPull}
 
 	containerExec.Cmd = dockerCmd
 
@@ -156,6 +185,10 @@ func (m *ManagerDocker) NewHookRunExec(
 		"-w", workingDir,                       // Set working dir.
 	}
 
+	if noPull {
+		containerExec.ArgsPre = append(containerExec.ArgsPre, "--pull=never")
+	}
+
 	if mountWSShared {
 		containerExec.ArgsPre = append(containerExec.ArgsPre,
 			"-v",
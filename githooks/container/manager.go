@@ -17,6 +17,10 @@ func (m *ManagerNotAvailableError) Error() string {
 // set to true in containerized runs.
 const EnvVariableContainerRun = "GITHOOKS_CONTAINER_RUN"
 
+// ImageLabelVersion is the label with the Githooks version
+// set on all images built by Githooks.
+const ImageLabelVersion = "githooks-version"
+
 type ContainerManagerType int
 type containerManagerType struct {
 	Docker ContainerManagerType
@@ -38,16 +42,26 @@ type IManager interface {
 		stage string,
 		ref string) (string, error)
 	ImageExists(ref string) (bool, error)
+	ImageLabel(ref string, label string) (string, error)
 	ImageRemove(ref string) error
+	ImageSave(file string, refs ...string) error
+	ImageLoad(file string) error
 
 	NewHookRunExec(
 		ref string,
 		workspaceDir string,
 		workspaceHookDir string,
 		exe cm.IExecutable,
+		noPull bool,
 	) (cm.IExecutable, error)
 }
 
+// IsManagerSupported reports if the container manager `manager` is supported.
+// An empty manager defaults to `docker`.
+func IsManagerSupported(manager string) bool {
+	return strs.IsEmpty(manager) || manager == "docker"
+}
+
 // NewManager creates a container manager of type `manager`.
 // If empty `docker` is taken.
 // Currently only `docker` is supported.
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 
 	cm "github.com/gabyx/githooks/githooks/common"
@@ -308,6 +309,25 @@ func (c *Context) FetchBranch(remote string, branch string, tagPattern string) e
 	return nil
 }
 
+// FetchTag executes a fetch of a `tag` from the `remote` in `repoPath`.
+// A moved tag overwrites the local one.
+func (c *Context) FetchTag(remote string, tag string) error {
+	ref := strs.Fmt("refs/tags/%s", tag)
+
+	out, e := c.GetCombined("fetch", "--force", "--no-tags", remote, strs.Fmt("%s:%s", ref, ref))
+	if e != nil {
+		return cm.ErrorF("Fetching of tag '%s' from '%s'\nin '%s' failed:\n%s",
+			tag, remote, c.GetCwd(), out)
+	}
+
+	return nil
+}
+
+// IsTag reports if `name` is an existing tag in the repository.
+func IsTag(gitx *Context, name string) bool {
+	return gitx.Check("rev-parse", "--verify", "-q", strs.Fmt("refs/tags/%s^{commit}", name)) == nil
+}
+
 // GetCommits gets all commits in the ancestry path starting from `firstSHA` (excluded in the result)
 // up to and including `lastSHA`.
 func (c *Context) GetCommits(firstSHA string, lastSHA string) ([]string, error) {
@@ -329,6 +349,50 @@ func (c *Context) GetRemoteURLAndBranch(remote string) (currentURL string, curre
 	return
 }
 
+// GetAheadBehind reports how many commits `ref` is ahead and behind `upstream`.
+func (c *Context) GetAheadBehind(ref string, upstream string) (ahead int, behind int, err error) {
+	out, err := c.Get("rev-list", "--left-right", "--count", strs.Fmt("%s...%s", ref, upstream))
+	if err != nil {
+		return
+	}
+
+	return ParseAheadBehind(out)
+}
+
+// ParseAheadBehind parses the output of `git rev-list --left-right --count`.
+func ParseAheadBehind(out string) (ahead int, behind int, err error) {
+	fields := strings.Fields(out)
+	if len(fields) != 2 { // nolint: gomnd
+		return 0, 0, cm.ErrorF("Could not parse ahead/behind counts '%s'.", out)
+	}
+
+	if ahead, err = strconv.Atoi(fields[0]); err != nil {
+		return
+	}
+
+	behind, err = strconv.Atoi(fields[1])
+
+	return
+}
+
+// GetModifiedFiles reports all files in the worktree which have local modifications
+// (staged, unstaged, untracked or ignored).
+func (c *Context) GetModifiedFiles() (files []string, err error) {
+	if files, err = c.GetSplit("diff", "--name-only", HEAD); err != nil {
+		return
+	}
+
+	// Ignored files count too, since they can change what hooks run.
+	untracked, err := c.GetSplit("ls-files", "--others")
+	if err != nil {
+		return
+	}
+
+	files, _ = strs.AppendUnique(files, untracked...)
+
+	return
+}
+
 // PullOrClone either executes a pull in `repoPath` or if not
 // existing, clones to this path.
 func PullOrClone(
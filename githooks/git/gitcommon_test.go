@@ -0,0 +1,50 @@
+package git
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAheadBehind(t *testing.T) {
+	ahead, behind, err := ParseAheadBehind("3\t5")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, ahead)
+	assert.Equal(t, 5, behind)
+
+	ahead, behind, err = ParseAheadBehind("0 0")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, ahead)
+	assert.Equal(t, 0, behind)
+
+	_, _, err = ParseAheadBehind("3")
+	assert.NotNil(t, err)
+
+	_, _, err = ParseAheadBehind("a b")
+	assert.NotNil(t, err)
+}
+
+func TestGetModifiedFiles(t *testing.T) {
+	dir := t.TempDir()
+	gitx := NewCtxSanitizedAt(dir)
+	assert.Nil(t, gitx.Check("init"))
+	assert.Nil(t, os.WriteFile(path.Join(dir, ".gitignore"), []byte("*.log\n"), 0600))
+	assert.Nil(t, os.WriteFile(path.Join(dir, "hook.sh"), []byte("a"), 0600))
+	assert.Nil(t, gitx.Check("add", "."))
+	assert.Nil(t, gitx.Check("-c", "user.name=test", "-c", "user.email=test@test.com",
+		"commit", "--no-gpg-sign", "-m", "Init"))
+
+	files, err := gitx.GetModifiedFiles()
+	assert.Nil(t, err)
+	assert.Empty(t, files)
+
+	assert.Nil(t, os.WriteFile(path.Join(dir, "hook.sh"), []byte("b"), 0600))
+	assert.Nil(t, os.WriteFile(path.Join(dir, "new.sh"), []byte("a"), 0600))
+	assert.Nil(t, os.WriteFile(path.Join(dir, "ignored.log"), []byte("a"), 0600))
+
+	files, err = gitx.GetModifiedFiles()
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"hook.sh", "new.sh", "ignored.log"}, files)
+}
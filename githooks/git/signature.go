@@ -0,0 +1,67 @@
+package git
+
+import (
+	"regexp"
+	"strings"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	strs "github.com/gabyx/githooks/githooks/strings"
+)
+
+var reSSHSignature = regexp.MustCompile(`Good "git" signature for (\S+) with \S+ key (\S+)`)
+
+// ParseSignatureKeys parses the raw output of `git verify-commit --raw` or
+// `git verify-tag --raw` and returns all reported key identifiers of good signatures.
+// For GPG this reports the key id, the fingerprint and the primary key fingerprint.
+// For SSH this reports the principal and the key fingerprint.
+func ParseSignatureKeys(out string) (keys []string) {
+	for _, line := range strs.SplitLines(out) {
+		fields := strings.Fields(line)
+
+		switch {
+		case len(fields) >= 3 && fields[0] == "[GNUPG:]" && fields[1] == "GOODSIG":
+			keys, _ = strs.AppendUnique(keys, fields[2])
+
+		case len(fields) >= 3 && fields[0] == "[GNUPG:]" && fields[1] == "VALIDSIG":
+			keys, _ = strs.AppendUnique(keys, fields[2])
+
+			// The last field is the primary key fingerprint.
+			if len(fields) >= 12 { // nolint: gomnd
+				keys, _ = strs.AppendUnique(keys, fields[len(fields)-1])
+			}
+
+		default:
+			if m := reSSHSignature.FindStringSubmatch(line); m != nil {
+				keys, _ = strs.AppendUnique(keys, m[1], m[2])
+			}
+		}
+	}
+
+	return
+}
+
+// VerifyCommit verifies the signature of the commit `ref`
+// and returns the key identifiers of the good signatures.
+func (c *Context) VerifyCommit(ref string) ([]string, error) {
+	if strs.IsEmpty(ref) {
+		ref = HEAD
+	}
+
+	out, err := c.GetCombined("verify-commit", "--raw", ref)
+	if err != nil {
+		return nil, cm.CombineErrors(cm.ErrorF("Commit '%s' in '%s' is not validly signed.", ref, c.GetCwd()), err)
+	}
+
+	return ParseSignatureKeys(out), nil
+}
+
+// VerifyTag verifies the signature of the tag `tag`
+// and returns the key identifiers of the good signatures.
+func (c *Context) VerifyTag(tag string) ([]string, error) {
+	out, err := c.GetCombined("verify-tag", "--raw", tag)
+	if err != nil {
+		return nil, cm.CombineErrors(cm.ErrorF("Tag '%s' in '%s' is not validly signed.", tag, c.GetCwd()), err)
+	}
+
+	return ParseSignatureKeys(out), nil
+}
@@ -0,0 +1,34 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSignatureKeys(t *testing.T) {
+
+	// GPG status output.
+	out := `[GNUPG:] NEWSIG
+[GNUPG:] KEY_CONSIDERED 2C9A0C7E1B4C3A9F7D6E5F4A3B2C1D0E9F8A7B6C 0
+[GNUPG:] SIG_ID 0Wq2lNZsZQ8xS4nBh1iYxk 2023-05-01 1682950000
+[GNUPG:] GOODSIG 3B2C1D0E9F8A7B6C Githook Tests <githook@test.com>
+[GNUPG:] VALIDSIG 2C9A0C7E1B4C3A9F7D6E5F4A3B2C1D0E9F8A7B6C 2023-05-01 1682950000 0 4 0 1 10 00 2C9A0C7E1B4C3A9F7D6E5F4A3B2C1D0E9F8A7B6C
+[GNUPG:] TRUST_ULTIMATE 0 pgp`
+
+	keys := ParseSignatureKeys(out)
+	assert.Equal(t,
+		[]string{"3B2C1D0E9F8A7B6C", "2C9A0C7E1B4C3A9F7D6E5F4A3B2C1D0E9F8A7B6C"},
+		keys)
+
+	// SSH output.
+	out = `Good "git" signature for githook@test.com with ED25519 key SHA256:m2Jm8Zw5bkGSP7Q6kDfEXw8FzNfhKzH9mVq1bJb8P0c`
+	keys = ParseSignatureKeys(out)
+	assert.Equal(t,
+		[]string{"githook@test.com", "SHA256:m2Jm8Zw5bkGSP7Q6kDfEXw8FzNfhKzH9mVq1bJb8P0c"},
+		keys)
+
+	// Bad signatures report nothing.
+	out = `[GNUPG:] BADSIG 3B2C1D0E9F8A7B6C Githook Tests <githook@test.com>`
+	assert.Empty(t, ParseSignatureKeys(out))
+}
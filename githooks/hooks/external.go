@@ -0,0 +1,293 @@
+package hooks
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
+	strs "github.com/gabyx/githooks/githooks/strings"
+)
+
+// ExternalHookManager is another hook manager (e.g. husky, pre-commit, lefthook)
+// configured in a repository which Githooks can run alongside its own hooks.
+type ExternalHookManager struct {
+	// The name of the manager.
+	Name string
+
+	// The absolute path of the detected configuration (file or directory).
+	Config string
+
+	// Regex to detect hooks in `.git/hooks` generated by this manager.
+	generatedHookRe *regexp.Regexp
+	// Local `core.hooksPath` values set by this manager.
+	hooksPaths []string
+
+	getHookNames func(config string) []string
+	getRunConfig func(config string, hookName string, rootDir string) (trustFile string, c runnerConfigFile)
+}
+
+type externalHookManagerDef struct {
+	name            string
+	configs         []string
+	generatedHookRe *regexp.Regexp
+	hooksPaths      []string
+
+	getHookNames func(config string) []string
+	// Gets the runner config for hook `hookName` with paths
+	// relative to `rootDir` (empty for the current directory).
+	getRunConfig func(config string, hookName string, rootDir string) (string, runnerConfigFile)
+}
+
+var externalHookManagerDefs = []externalHookManagerDef{
+	{
+		name:            "husky",
+		configs:         []string{".husky"},
+		generatedHookRe: regexp.MustCompile(`^# Created by Husky v\d`),
+		hooksPaths:      []string{".husky", ".husky/_"},
+		getHookNames: func(config string) []string {
+			return strs.Filter(ManagedHookNames, func(h string) bool {
+				return cm.IsFile(path.Join(config, h))
+			})
+		},
+		getRunConfig: func(config string, hookName string, rootDir string) (string, runnerConfigFile) {
+			c := createRunnerConfig()
+			c.Cmd = "sh"
+			c.Args = []string{"-e", path.Join(rootDir, path.Base(config), hookName)}
+			c.Env = []string{"PATH=" + path.Join(rootDir, "node_modules/.bin") + ":${env:PATH}"}
+
+			return path.Join(config, hookName), c
+		},
+	},
+	{
+		name:            "pre-commit",
+		configs:         []string{".pre-commit-config.yaml"},
+		generatedHookRe: regexp.MustCompile(`File generated by pre-commit`),
+		getHookNames: func(config string) []string {
+			var c struct {
+				HookTypes []string `yaml:"default_install_hook_types"`
+			}
+
+			if err := cm.LoadYAML(config, &c); err != nil || len(c.HookTypes) == 0 {
+				return []string{"pre-commit"}
+			}
+
+			return strs.Filter(c.HookTypes, func(h string) bool { return strs.Includes(ManagedHookNames, h) })
+		},
+		getRunConfig: func(config string, hookName string, rootDir string) (string, runnerConfigFile) {
+			c := createRunnerConfig()
+			c.Cmd = "pre-commit"
+			c.Args = []string{
+				"hook-impl",
+				"--config=" + path.Join(rootDir, path.Base(config)),
+				"--hook-type=" + hookName,
+				"--hook-dir=" + path.Join(rootDir, ".git/hooks"),
+				"--skip-on-missing-config",
+				"--"}
+
+			return config, c
+		},
+	},
+	{
+		name:            "lefthook",
+		configs:         []string{"lefthook.yml", ".lefthook.yml", "lefthook.yaml", ".lefthook.yaml"},
+		generatedHookRe: regexp.MustCompile(`^\s*call_lefthook\s*\(\)`),
+		getHookNames: func(config string) []string {
+			var c map[string]interface{}
+			if err := cm.LoadYAML(config, &c); err != nil {
+				return nil
+			}
+
+			return strs.Filter(ManagedHookNames, func(h string) bool {
+				_, exists := c[h]
+
+				return exists
+			})
+		},
+		getRunConfig: func(config string, hookName string, _ string) (string, runnerConfigFile) {
+			c := createRunnerConfig()
+			c.Cmd = "lefthook"
+			c.Args = []string{"run", hookName}
+
+			return config, c
+		},
+	},
+}
+
+// GetExternalHookManagerNames gets the names of all supported external hook managers.
+func GetExternalHookManagerNames() (names []string) {
+	for i := range externalHookManagerDefs {
+		names = append(names, externalHookManagerDefs[i].name)
+	}
+
+	return
+}
+
+// DetectExternalHookManagers detects all external hook managers
+// configured in the repository `repoDir`.
+func DetectExternalHookManagers(repoDir string) (managers []ExternalHookManager) {
+	for i := range externalHookManagerDefs {
+		def := &externalHookManagerDefs[i]
+
+		for _, c := range def.configs {
+			config := path.Join(repoDir, c)
+			if exists, _ := cm.IsPathExisting(config); !exists {
+				continue
+			}
+
+			managers = append(managers,
+				ExternalHookManager{
+					Name:            def.name,
+					Config:          config,
+					generatedHookRe: def.generatedHookRe,
+					hooksPaths:      def.hooksPaths,
+					getHookNames:    def.getHookNames,
+					getRunConfig:    def.getRunConfig})
+
+			break
+		}
+	}
+
+	return
+}
+
+// GetHookNames gets all hook names the manager has configured.
+func (m *ExternalHookManager) GetHookNames() []string {
+	return m.getHookNames(m.Config)
+}
+
+// IsGeneratedHook checks if the hook `file` was generated by the manager.
+func (m *ExternalHookManager) IsGeneratedHook(file string) bool {
+	if isRunWrapper, _ := IsRunWrapper(file); isRunWrapper {
+		return false
+	}
+
+	found, _ := cm.MatchLineRegexInFile(file, m.generatedHookRe)
+
+	return found
+}
+
+// IsHooksPath checks if the local `core.hooksPath` value `hooksPath`
+// has been set by the manager.
+func (m *ExternalHookManager) IsHooksPath(hooksPath string) bool {
+	return strs.Includes(m.hooksPaths, strings.TrimSuffix(path.Clean(hooksPath), "/"))
+}
+
+// GetNamespacePath gets the namespace path of the manager's hooks.
+func (m *ExternalHookManager) GetNamespacePath() string {
+	return path.Join(NamespacePrefix+NamespaceExternalHook, m.Name)
+}
+
+// GetHook gets the hook which runs the manager for hook `hookName`.
+// The path of the hook is the manager's configuration which gets trusted.
+// It returns `nil` if the manager has no hook `hookName` configured.
+func (m *ExternalHookManager) GetHook(
+	gitx *git.Context,
+	repoDir string,
+	hookName string,
+	isIgnored IgnoreCallback,
+	isTrusted TrustCallback) (*Hook, error) {
+
+	if !strs.Includes(m.GetHookNames(), hookName) {
+		return nil, nil
+	}
+
+	trustFile, config := m.getRunConfig(m.Config, hookName, repoDir)
+
+	subst := getVarSubstitution(os.LookupEnv, gitx.LookupConfig)
+	exec := cm.Executable{Cmd: config.Cmd, Args: config.Args}
+
+	for _, e := range config.Env {
+		e, err := subst(e)
+		if err != nil {
+			return nil, cm.CombineErrors(err,
+				cm.ErrorF("Could not get run command for '%s'.", m.Name))
+		}
+		exec.Env = append(exec.Env, e)
+	}
+
+	namespacePath := m.GetNamespacePath()
+	ignored := isIgnored(namespacePath)
+	trusted, sha := isTrusted(trustFile)
+
+	return &Hook{
+		IExecutable:   &exec,
+		Path:          trustFile,
+		Namespace:     NamespaceExternalHook,
+		NamespacePath: namespacePath,
+		Active:        !ignored,
+		Trusted:       trusted,
+		Checksum:      sha,
+		BatchName:     m.Name}, nil
+}
+
+// ImportHooks writes runner configurations for all hooks of the manager
+// into the repository hooks directory `repoHooksDir`.
+// Existing files are not overwritten.
+func (m *ExternalHookManager) ImportHooks(repoHooksDir string) (files []string, err error) {
+	for _, hookName := range m.GetHookNames() {
+		_, config := m.getRunConfig(m.Config, hookName, "")
+
+		dir := path.Join(repoHooksDir, hookName)
+		file := path.Join(dir, m.Name+".yaml")
+
+		if cm.IsFile(file) {
+			continue
+		}
+
+		if e := os.MkdirAll(dir, cm.DefaultFileModeDirectory); e != nil {
+			err = cm.CombineErrors(err, e)
+
+			continue
+		}
+
+		if e := cm.StoreYAML(file, &config); e != nil {
+			err = cm.CombineErrors(err, cm.ErrorF("Could not write runner config '%s'.", file), e)
+
+			continue
+		}
+
+		files = append(files, file)
+	}
+
+	return
+}
+
+// GetExternalHookManagers gets the chained external hook managers
+// of the repository `repoDir`.
+func GetExternalHookManagers(gitx *git.Context, repoDir string) (managers []ExternalHookManager) {
+	names := gitx.GetConfigAll(GitCKExternalHookManagers, git.LocalScope)
+	if len(names) == 0 {
+		return
+	}
+
+	return filterExternalHookManagers(DetectExternalHookManagers(repoDir), names)
+}
+
+func filterExternalHookManagers(managers []ExternalHookManager, names []string) (res []ExternalHookManager) {
+	for i := range managers {
+		if strs.Includes(names, managers[i].Name) {
+			res = append(res, managers[i])
+		}
+	}
+
+	return
+}
+
+// SetExternalHookManagers sets the chained external hook managers `names`
+// in the repository of `gitx`.
+func SetExternalHookManagers(gitx *git.Context, names []string) error {
+	if err := gitx.UnsetConfig(GitCKExternalHookManagers, git.LocalScope); err != nil {
+		return err
+	}
+
+	for _, n := range names {
+		if err := gitx.AddConfig(GitCKExternalHookManagers, n, git.LocalScope); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package hooks
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectExternalHookManagers(t *testing.T) {
+	repo, e := os.MkdirTemp("", "")
+	assert.Nil(t, e)
+	defer os.RemoveAll(repo)
+
+	assert.Empty(t, DetectExternalHookManagers(repo))
+
+	assert.Nil(t, os.MkdirAll(path.Join(repo, ".husky", "_"), 0775))
+	assert.Nil(t, os.WriteFile(path.Join(repo, ".husky", "pre-commit"), []byte("npm test"), 0664))
+	assert.Nil(t, os.WriteFile(path.Join(repo, ".pre-commit-config.yaml"),
+		[]byte("default_install_hook_types: [pre-push, commit-msg]\nrepos: []"), 0664))
+	assert.Nil(t, os.WriteFile(path.Join(repo, ".lefthook.yml"),
+		[]byte("post-merge:\n  commands: {}\nskip_output: [meta]"), 0664))
+
+	managers := DetectExternalHookManagers(repo)
+	assert.Equal(t, 3, len(managers))
+
+	assert.Equal(t, "husky", managers[0].Name)
+	assert.Equal(t, []string{"pre-commit"}, managers[0].GetHookNames())
+	assert.True(t, managers[0].IsHooksPath(".husky/_/"))
+	assert.False(t, managers[0].IsHooksPath(".githooks"))
+
+	assert.Equal(t, "pre-commit", managers[1].Name)
+	assert.Equal(t, []string{"pre-push", "commit-msg"}, managers[1].GetHookNames())
+
+	assert.Equal(t, "lefthook", managers[2].Name)
+	assert.Equal(t, path.Join(repo, ".lefthook.yml"), managers[2].Config)
+	assert.Equal(t, []string{"post-merge"}, managers[2].GetHookNames())
+	assert.Equal(t, "ns:gh-external/lefthook", managers[2].GetNamespacePath())
+
+	assert.Equal(t, 2, len(filterExternalHookManagers(managers, []string{"lefthook", "husky"})))
+}
+
+func TestImportExternalHooks(t *testing.T) {
+	repo, e := os.MkdirTemp("", "")
+	assert.Nil(t, e)
+	defer os.RemoveAll(repo)
+
+	assert.Nil(t, os.MkdirAll(path.Join(repo, ".husky"), 0775))
+	assert.Nil(t, os.WriteFile(path.Join(repo, ".husky", "pre-commit"), []byte("npm test"), 0664))
+
+	managers := DetectExternalHookManagers(repo)
+	assert.Equal(t, 1, len(managers))
+
+	hooksDir := GetGithooksDir(repo)
+	files, err := managers[0].ImportHooks(hooksDir)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{path.Join(hooksDir, "pre-commit", "husky.yaml")}, files)
+
+	config, err := loadRunnerConfig(files[0])
+	assert.Nil(t, err)
+	assert.Equal(t, "sh", config.Cmd)
+	assert.Equal(t, []string{"-e", ".husky/pre-commit"}, config.Args)
+
+	// Existing configs are not overwritten.
+	files, err = managers[0].ImportHooks(hooksDir)
+	assert.Nil(t, err)
+	assert.Empty(t, files)
+}
+
+func TestExternalGeneratedHooks(t *testing.T) {
+	repo, e := os.MkdirTemp("", "")
+	assert.Nil(t, e)
+	defer os.RemoveAll(repo)
+
+	assert.Nil(t, os.MkdirAll(path.Join(repo, ".husky"), 0775))
+	assert.Nil(t, os.WriteFile(path.Join(repo, "lefthook.yml"), []byte("pre-commit: {}"), 0664))
+
+	managers := DetectExternalHookManagers(repo)
+	assert.Equal(t, 2, len(managers))
+
+	isGenerated := func(m *ExternalHookManager, content string) bool {
+		file := path.Join(repo, "hook")
+		assert.Nil(t, os.WriteFile(file, []byte(content), 0664))
+
+		return m.IsGeneratedHook(file)
+	}
+
+	assert.True(t, isGenerated(&managers[0],
+		"#!/bin/sh\n# husky\n# Created by Husky v4.3.8 (https://github.com/typicode/husky#readme)\n"))
+	assert.False(t, isGenerated(&managers[0], "#!/bin/sh\n# Run husky-like checks.\nnpm test\n"))
+
+	assert.True(t, isGenerated(&managers[1], "#!/bin/sh\n\ncall_lefthook()\n{\n  lefthook \"$@\"\n}\n"))
+	assert.False(t, isGenerated(&managers[1], "#!/bin/sh\n# Migrated from Lefthook.\nmake lint\n"))
+}
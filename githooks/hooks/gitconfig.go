@@ -11,6 +11,9 @@ const (
 	GitCKAutoUpdateEnabled        = "githooks.autoUpdateEnabled"
 	GitCKAutoUpdateCheckTimestamp = "githooks.autoUpdateCheckTimestamp"
 	GitCKAutoUpdateUsePrerelease  = "githooks.autoUpdateUsePrerelease"
+	GitCKUpdateChannel            = "githooks.updateChannel"
+	GitCKUpdateSkippedVersion     = "githooks.updateSkippedVersion"
+	GitCKMachineID                = "githooks.machineID"
 
 	GitCKBugReportInfo = "githooks.bugReportInfo"
 
@@ -27,6 +30,9 @@ const (
 	GitCKUseCoreHooksPath        = "githooks.useCoreHooksPath"
 	GitCKPathForUseCoreHooksPath = "githooks.pathForUseCoreHooksPath"
 
+	GitCKUseRepoHooksPath        = "githooks.useRepoHooksPath"
+	GitCKPathForUseRepoHooksPath = "githooks.pathForUseRepoHooksPath"
+
 	GitCKPreviousSearchDir = "githooks.previousSearchDir"
 	GitCKNumThreads        = "githooks.numThreads"
 
@@ -35,10 +41,18 @@ const (
 	GitCKBuildImagesOnSharedUpdate = "githooks.buildImagesOnSharedUpdate"
 )
 
+// Git config keys for system config.
+const (
+	GitCKTrustPolicy = "githooks.trustPolicy"
+)
+
 // Git config keys for local config.
 const (
 	GitCKRegistered = "githooks.registered"
 	GitCKTrustAll   = "githooks.trustAll"
+
+	GitCKPreviousCoreHooksPath = "githooks.previousCoreHooksPath"
+	GitCKExternalHookManagers  = "githooks.externalHookManagers"
 )
 
 // Git config keys for local/global config.
@@ -48,12 +62,18 @@ const (
 	GitCKShared                        = "githooks.shared"
 	GitCKSharedUpdateTriggers          = "githooks.sharedHooksUpdateTriggers"
 	GitCKAutoUpdateSharedHooksDisabled = "githooks.autoUpdateSharedHooksDisabled"
+	GitCKSharedVerifySignature         = "githooks.sharedVerifySignature"
+	GitCKSharedAllowedSigningKeys      = "githooks.sharedAllowedSigningKeys"
 
 	GitCKSkipNonExistingSharedHooks = "githooks.skipNonExistingSharedHooks"
 	GitCKSkipUntrustedHooks         = "githooks.skipUntrustedHooks"
+	GitCKTrustSharedRevisions       = "githooks.trustSharedRevisions"
+	GitCKTrustExpiry                = "githooks.trustExpiry"
 
 	GitCKRunnerIsNonInteractive = "githooks.runnerIsNonInteractive"
 
+	GitCKOffline = "githooks.offline"
+
 	GitCKContainerizedHooksEnabled     = "githooks.containerizedHooksEnabled"
 	GitCKContainerManager              = "githooks.containerManager"
 	GitCKContainerImageUpdateAutomatic = "githooks.containerImageUpdateAutomatic"
@@ -74,6 +94,9 @@ func GetGlobalGitConfigKeys() []string {
 		GitCKAutoUpdateEnabled,
 		GitCKAutoUpdateCheckTimestamp,
 		GitCKAutoUpdateUsePrerelease,
+		GitCKUpdateChannel,
+		GitCKUpdateSkippedVersion,
+		GitCKMachineID,
 
 		GitCKBugReportInfo,
 
@@ -90,6 +113,9 @@ func GetGlobalGitConfigKeys() []string {
 		GitCKUseCoreHooksPath,
 		GitCKPathForUseCoreHooksPath,
 
+		GitCKUseRepoHooksPath,
+		GitCKPathForUseRepoHooksPath,
+
 		GitCKNumThreads,
 
 		GitCKAliasHooks,
@@ -98,11 +124,16 @@ func GetGlobalGitConfigKeys() []string {
 		GitCKShared,
 		GitCKSharedUpdateTriggers,
 		GitCKAutoUpdateSharedHooksDisabled,
+		GitCKSharedVerifySignature,
+		GitCKSharedAllowedSigningKeys,
 
 		GitCKSkipNonExistingSharedHooks,
 		GitCKSkipUntrustedHooks,
+		GitCKTrustSharedRevisions,
+		GitCKTrustExpiry,
 
 		GitCKRunnerIsNonInteractive,
+		GitCKOffline,
 
 		GitCKContainerManager,
 		GitCKContainerizedHooksEnabled,
@@ -114,23 +145,47 @@ func GetLocalGitConfigKeys() []string {
 	return []string{
 		GitCKRegistered,
 		GitCKTrustAll,
+		GitCKPreviousCoreHooksPath,
+		GitCKExternalHookManagers,
 
 		GitCKMaintainedHooks,
 
 		GitCKShared,
 		GitCKSharedUpdateTriggers,
 		GitCKAutoUpdateSharedHooksDisabled,
+		GitCKSharedVerifySignature,
+		GitCKSharedAllowedSigningKeys,
 
 		GitCKSkipNonExistingSharedHooks,
 		GitCKSkipUntrustedHooks,
+		GitCKTrustSharedRevisions,
+		GitCKTrustExpiry,
 
 		GitCKRunnerIsNonInteractive,
+		GitCKOffline,
 
 		GitCKContainerManager,
 		GitCKContainerizedHooksEnabled,
 	}
 }
 
+// GetTrustGitConfigKeys gets all git config keys belonging to the trust settings.
+func GetTrustGitConfigKeys() []string {
+	return []string{
+		GitCKTrustAll,
+		GitCKTrustSharedRevisions,
+		GitCKTrustExpiry,
+	}
+}
+
+// GetLegacyGitConfigKeys gets all git config keys which are not used anymore.
+func GetLegacyGitConfigKeys() []string {
+	return []string{
+		"githooks.checksumCacheDir",
+		"githooks.maintainOnlyServerHooks",
+	}
+}
+
 // var filterRegex = regexp.MustCompile(`^(githooks\.|alias.hooks|core.hook|init.template)`)
 
 // FilterGitConfigCache filters  for filtering the Git config cache.
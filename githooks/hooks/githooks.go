@@ -114,12 +114,14 @@ func GetBugReportingInfo() (info string) {
 }
 
 // CheckGithooksSetup tests if 'core.hooksPath' is in alignment with 'git.GitCKUseCoreHooksPath'.
+// A 'core.hooksPath' pointing to 'GitCKPathForUseRepoHooksPath' is valid
+// for install mode 'repo-hooks-path'.
 func CheckGithooksSetup(gitx *git.Context) (err error) {
 	useCoreHooksPath := gitx.GetConfig(GitCKUseCoreHooksPath, git.Traverse)
 	coreHooksPath, coreHooksPathSet := gitx.LookupConfig(git.GitCKCoreHooksPath, git.Traverse)
 
 	if coreHooksPathSet {
-		if useCoreHooksPath != git.GitCVTrue {
+		if useCoreHooksPath != git.GitCVTrue && !IsRepoHooksPath(gitx, coreHooksPath) {
 			err = cm.ErrorF(
 				"Git config 'core.hooksPath' is set and has value:\n"+
 					"'%s',\n"+
@@ -138,6 +140,18 @@ func CheckGithooksSetup(gitx *git.Context) (err error) {
 	return
 }
 
+// IsRepoHooksPath checks if `hooksPath` is the hooks directory
+// of the install mode 'repo-hooks-path'.
+func IsRepoHooksPath(gitx *git.Context, hooksPath string) bool {
+	if gitx.GetConfig(GitCKUseRepoHooksPath, git.GlobalScope) != git.GitCVTrue {
+		return false
+	}
+
+	dir := gitx.GetConfig(GitCKPathForUseRepoHooksPath, git.GlobalScope)
+
+	return strs.IsNotEmpty(dir) && path.Clean(dir) == path.Clean(hooksPath)
+}
+
 // GetGithooksDir gets the hooks directory for Githooks inside a repository (bare, non-bare).
 func GetGithooksDir(repoDir string) string {
 	return path.Join(repoDir, HooksDirName)
@@ -177,6 +191,12 @@ func GetBinaryDir(installDir string) string {
 	return path.Join(installDir, "bin")
 }
 
+// GetRollbackDir returns the directory inside the install directory
+// which keeps the previous version for a rollback.
+func GetRollbackDir(installDir string) string {
+	return path.Join(installDir, "rollback")
+}
+
 // GetTemporaryDir returns the Githooks temporary directory inside the install directory.
 func GetTemporaryDir(installDir string) string {
 	cm.DebugAssert(strs.IsNotEmpty(installDir))
@@ -311,6 +331,59 @@ func IsContainerizedHooksEnabled(gitx *git.Context, checkEnv bool) bool {
 	return enabled == git.GitCVTrue
 }
 
+// IsOfflineMode returns if Githooks should skip all network operations
+// (shared hooks updates, image pulls, update checks) given by the
+// config `scope` or optional also by the env. variable `GITHOOKS_OFFLINE`.
+func IsOfflineMode(gitx *git.Context, scope git.ConfigScope, checkEnv bool) bool {
+	if checkEnv {
+		env := os.Getenv("GITHOOKS_OFFLINE")
+		if env != "" &&
+			env != "0" &&
+			env != "false" && env != "off" {
+			return true
+		}
+	}
+
+	return gitx.GetConfig(GitCKOffline, scope) == git.GitCVTrue
+}
+
+// EnvVariableSkip is the env. variable with a comma-separated list of
+// namespace paths or glob patterns of hooks to skip.
+const EnvVariableSkip = "GITHOOKS_SKIP"
+
+// GetEnvSkipPatterns gets the patterns of hooks to skip given by the env. variable `GITHOOKS_SKIP`.
+// Relative patterns are made absolute with the namespace `hookNamespace`.
+func GetEnvSkipPatterns(hookNamespace string) (patterns HookPatterns, err error) {
+	for _, p := range strings.Split(os.Getenv(EnvVariableSkip), ",") {
+		p = strings.TrimSpace(p)
+		if strs.IsEmpty(p) {
+			continue
+		}
+
+		if !IsHookPatternValid(p) {
+			err = cm.CombineErrors(err, cm.ErrorF("Pattern '%s' in '%s' is malformed.", p, EnvVariableSkip))
+
+			continue
+		}
+
+		patterns.AddPatterns(p)
+	}
+
+	patterns.MakeRelativePatternsAbsolute(hookNamespace, "")
+
+	return
+}
+
+// SetOfflineMode sets the offline mode setting.
+func SetOfflineMode(gitx *git.Context, enable bool, reset bool, scope git.ConfigScope) error {
+	switch {
+	case reset:
+		return gitx.UnsetConfig(GitCKOffline, scope)
+	default:
+		return gitx.SetConfig(GitCKOffline, enable, scope)
+	}
+}
+
 // IsRunnerNonInteractive tells if the runner should run in non-interactive mode
 // meaning all non-fatal prompts will be skipped with default answering
 // and fatal prompts still need to be configured to pass.
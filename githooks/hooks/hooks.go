@@ -35,8 +35,8 @@ type Hook struct {
 	// Has priority 2 for execution determination.
 	Trusted bool
 
-	// SHA1 hash of the hook. (if determined)
-	SHA1 string
+	// SHA256 checksum of the hook. (if determined)
+	Checksum string
 
 	// BatchName denotes the parallel batch
 	BatchName string
@@ -133,7 +133,7 @@ func GetHookTagNameMappings() []string {
 type IgnoreCallback = func(namespacePath string) (ignored bool)
 
 // TrustCallback is the callback type for trusting hooks.
-type TrustCallback = func(hookPath string) (trusted bool, sha1 string)
+type TrustCallback = func(hookPath string) (trusted bool, checksum string)
 
 // GetAllHooksIn gets all hooks with name `hookName`
 // in hooks dir `hookDir`.
@@ -201,7 +201,7 @@ func GetAllHooksIn(
 				NamespacePath: namespacedPath,
 				Active:        !ignored,
 				Trusted:       trusted,
-				SHA1:          sha,
+				Checksum:      sha,
 				BatchName:     batchName})
 
 		return nil
@@ -446,10 +446,10 @@ func AllHooksSuccessful(results []HookResult) bool {
 	return true
 }
 
-// AssertSHA1 ensures that the hook has its SHA1 computed.
-func (h *Hook) AssertSHA1() (err error) {
-	if strs.IsEmpty(h.SHA1) {
-		h.SHA1, err = cm.GetSHA1HashFile(h.Path)
+// AssertChecksum ensures that the hook has its SHA256 checksum computed.
+func (h *Hook) AssertChecksum() (err error) {
+	if strs.IsEmpty(h.Checksum) {
+		h.Checksum, err = GetChecksum(h.Path)
 	}
 
 	return
@@ -0,0 +1,188 @@
+package hooks
+
+import (
+	"os"
+	"strings"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
+	strs "github.com/gabyx/githooks/githooks/strings"
+)
+
+// IgnoreCondition is the condition under which conditional ignore patterns apply.
+// All set fields need to be fulfilled.
+type IgnoreCondition struct {
+	// Glob pattern matching the current branch.
+	Branch string `yaml:"branch,omitempty"`
+
+	// Environment variable `<name>` which needs to be set and not empty or
+	// `<name>=<glob>` where the value needs to match the glob pattern.
+	Env string `yaml:"env,omitempty"`
+
+	// Glob pattern matching the arguments of the hook joined by spaces.
+	HookArgs string `yaml:"hook-args,omitempty"`
+}
+
+// ConditionalHookPatterns are ignore patterns which only apply
+// if the condition `When` is fulfilled.
+type ConditionalHookPatterns struct {
+	Patterns       []string        `yaml:"patterns"`
+	NamespacePaths []string        `yaml:"namespace-paths"`
+	When           IgnoreCondition `yaml:"when"`
+
+	fulfilled bool
+}
+
+// IgnoreConditionContext is the state against which ignore conditions are evaluated.
+type IgnoreConditionContext struct {
+	// The current branch. Empty if detached.
+	Branch string
+
+	// The arguments of the hook.
+	HookArgs []string
+	// If the hook arguments are known, which is only
+	// the case when hooks are run.
+	HasHookArgs bool
+}
+
+// NewIgnoreConditionContext creates the context to evaluate
+// ignore conditions in the repository of `gitx`.
+// The hook arguments `hookArgs` are only taken into account if `hasHookArgs` is set.
+func NewIgnoreConditionContext(gitx *git.Context, hookArgs []string, hasHookArgs bool) *IgnoreConditionContext {
+	branch, _ := gitx.GetCurrentBranch()
+
+	return &IgnoreConditionContext{
+		Branch:      branch,
+		HookArgs:    hookArgs,
+		HasHookArgs: hasHookArgs}
+}
+
+// IsSet reports if any condition is set.
+func (c *IgnoreCondition) IsSet() bool {
+	return strs.IsNotEmpty(c.Branch) || strs.IsNotEmpty(c.Env) || strs.IsNotEmpty(c.HookArgs)
+}
+
+// String returns a description of the condition.
+func (c *IgnoreCondition) String() string {
+	var conds []string
+
+	if strs.IsNotEmpty(c.Branch) {
+		conds = append(conds, strs.Fmt("branch: '%s'", c.Branch))
+	}
+
+	if strs.IsNotEmpty(c.Env) {
+		conds = append(conds, strs.Fmt("env: '%s'", c.Env))
+	}
+
+	if strs.IsNotEmpty(c.HookArgs) {
+		conds = append(conds, strs.Fmt("hook-args: '%s'", c.HookArgs))
+	}
+
+	return strings.Join(conds, ", ")
+}
+
+func (c *IgnoreCondition) splitEnv() (name string, pattern string, hasPattern bool) {
+	name, pattern, hasPattern = strings.Cut(c.Env, "=")
+	name = strings.TrimSpace(name)
+
+	return
+}
+
+// validate validates the condition.
+func (c *IgnoreCondition) validate() (err error) {
+	if !c.IsSet() {
+		return cm.Error("Conditional ignore patterns need at least one condition in 'when'.")
+	}
+
+	check := func(pattern string, what string) {
+		if _, e := cm.GlobMatch(pattern, ""); e != nil {
+			err = cm.CombineErrors(err, cm.ErrorF("Pattern '%s' for %s in ignore condition is malformed.", pattern, what))
+		}
+	}
+
+	if strs.IsNotEmpty(c.Branch) {
+		check(c.Branch, "'branch'")
+	}
+
+	if strs.IsNotEmpty(c.Env) {
+		name, pattern, hasPattern := c.splitEnv()
+		if strs.IsEmpty(name) {
+			err = cm.CombineErrors(err, cm.ErrorF("Environment variable in ignore condition '%s' is empty.", c.Env))
+		}
+
+		if hasPattern {
+			check(pattern, "'env'")
+		}
+	}
+
+	if strs.IsNotEmpty(c.HookArgs) {
+		check(c.HookArgs, "'hook-args'")
+	}
+
+	return
+}
+
+// Evaluate reports if the condition is fulfilled in context `ctx`.
+// Conditions on hook arguments are never fulfilled if the arguments are not known.
+func (c *IgnoreCondition) Evaluate(ctx *IgnoreConditionContext) bool {
+	if ctx == nil || !c.IsSet() {
+		return false
+	}
+
+	if strs.IsNotEmpty(c.Branch) {
+		if matched, _ := cm.GlobMatch(c.Branch, ctx.Branch); strs.IsEmpty(ctx.Branch) || !matched {
+			return false
+		}
+	}
+
+	if strs.IsNotEmpty(c.Env) {
+		name, pattern, hasPattern := c.splitEnv()
+		value, exists := os.LookupEnv(name)
+
+		if hasPattern {
+			if matched, _ := cm.GlobMatch(pattern, value); !exists || !matched {
+				return false
+			}
+		} else if strs.IsEmpty(value) {
+			return false
+		}
+	}
+
+	if strs.IsNotEmpty(c.HookArgs) {
+		if !ctx.HasHookArgs {
+			return false
+		}
+
+		if matched, _ := cm.GlobMatch(c.HookArgs, strings.Join(ctx.HookArgs, " ")); !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsFulfilled reports if the condition was fulfilled the last time
+// it was evaluated by `EvaluateConditions`.
+func (c *ConditionalHookPatterns) IsFulfilled() bool {
+	return c.fulfilled
+}
+
+// Matches returns true if the condition is fulfilled and `namespacePath`
+// matches any of the patterns and otherwise `false`.
+func (c *ConditionalHookPatterns) Matches(namespacePath string) bool {
+	return c.fulfilled && matchPatterns(c.Patterns, c.NamespacePaths, namespacePath, nil)
+}
+
+// EvaluateConditions evaluates all conditions of the conditional ignore patterns.
+// Conditional ignore patterns never match before they are evaluated.
+func (h *HookPatterns) EvaluateConditions(ctx *IgnoreConditionContext) {
+	for i := range h.Conditional {
+		h.Conditional[i].fulfilled = h.Conditional[i].When.Evaluate(ctx)
+	}
+}
+
+// EvaluateConditions evaluates all conditions of the conditional ignore patterns.
+func (h *RepoIgnorePatterns) EvaluateConditions(ctx *IgnoreConditionContext) {
+	h.HooksDir.EvaluateConditions(ctx)
+	h.User.EvaluateConditions(ctx)
+}
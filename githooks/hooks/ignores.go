@@ -9,19 +9,25 @@ import (
 )
 
 // hookIgnoreFile is the format of the ignore patterns file.
-// A path is ignored if matched by `Patterns` or `NamespacePaths`.
+// A path is ignored if matched by `Patterns` or `NamespacePaths`
+// or by any conditional patterns in `Conditional` whose condition is fulfilled.
 type hookIgnoreFile struct {
 	// Git ignores patterns matching hook namespace paths.
 	Patterns []string `yaml:"patterns"`
 	// Specific hook namespace paths (uses full match).
 	NamespacePaths []string `yaml:"namespace-paths"`
 
+	// Patterns which only apply under a condition.
+	Conditional []ConditionalHookPatterns `yaml:"conditional,omitempty"`
+
 	// The version of the file.
 	Version int `yaml:"version"`
 }
 
 // hookIgnoreFileVersion is the ignore file version.
-var hookIgnoreFileVersion = 1
+// Version 1: Initial.
+// Version 2: Added `conditional`.
+var hookIgnoreFileVersion = 2
 
 // createHookIgnoreFile creates the data for the hook ignore file.
 func createHookIgnoreFile() hookIgnoreFile {
@@ -32,6 +38,9 @@ func createHookIgnoreFile() hookIgnoreFile {
 type HookPatterns struct {
 	Patterns       []string
 	NamespacePaths []string
+
+	// Patterns which only apply if their condition is fulfilled.
+	Conditional []ConditionalHookPatterns
 }
 
 // RepoIgnorePatterns is the list of possible ignore patterns in a repository.
@@ -52,7 +61,12 @@ func CombineIgnorePatterns(patterns ...*HookPatterns) HookPatterns {
 
 // GetCount gets the count of all patterns.
 func (h *HookPatterns) GetCount() int {
-	return len(h.Patterns) + len(h.NamespacePaths)
+	count := len(h.Patterns) + len(h.NamespacePaths)
+	for i := range h.Conditional {
+		count += len(h.Conditional[i].Patterns) + len(h.Conditional[i].NamespacePaths)
+	}
+
+	return count
 }
 
 // AddPatterns adds pattern to the patterns.
@@ -106,6 +120,7 @@ func (h *HookPatterns) RemoveNamespacePaths(namespacePath ...string) (removed in
 func (h *HookPatterns) Add(p *HookPatterns) {
 	h.AddPatterns(p.Patterns...)
 	h.AddNamespacePaths(p.NamespacePaths...)
+	h.Conditional = append(h.Conditional, p.Conditional...)
 }
 
 // AddUnique adds pattern uniquely from patterns `p` to itself.
@@ -126,9 +141,10 @@ func (h *HookPatterns) Remove(p *HookPatterns) (removed int) {
 
 // RemoveAll removes all patterns.
 func (h *HookPatterns) RemoveAll() (removed int) {
-	removed = len(h.Patterns) + len(h.NamespacePaths)
+	removed = h.GetCount()
 	h.Patterns = nil
 	h.NamespacePaths = nil
+	h.Conditional = nil
 
 	return
 }
@@ -164,6 +180,11 @@ func (h *HookPatterns) MakeRelativePatternsAbsolute(hookNamespace string, rootPa
 
 	replace(h.Patterns)
 	replace(h.NamespacePaths)
+
+	for i := range h.Conditional {
+		replace(h.Conditional[i].Patterns)
+		replace(h.Conditional[i].NamespacePaths)
+	}
 }
 
 // Reserve reserves 'nPatterns'.
@@ -203,9 +224,77 @@ func checkPatternInversion(p string) (int, bool) {
 }
 
 // Matches returns true if `namespacePath` matches any of the patterns and otherwise `false`.
-func (h *HookPatterns) Matches(namespacePath string) (matched bool) {
+func (h *HookPatterns) Matches(namespacePath string) bool {
+	matched, _ := h.MatchesCondition(namespacePath)
+
+	return matched
+}
+
+// MatchesCondition returns true if `namespacePath` matches any of the patterns and otherwise `false`.
+// If only conditional patterns matched, the condition of the first matching ones is returned.
+// Inversions "!" only apply within the unconditional patterns or
+// within the patterns of the same condition.
+func (h *HookPatterns) MatchesCondition(namespacePath string) (bool, *IgnoreCondition) {
+	if matchPatterns(h.Patterns, h.NamespacePaths, namespacePath, nil) {
+		return true, nil
+	}
+
+	for i := range h.Conditional {
+		if h.Conditional[i].Matches(namespacePath) {
+			return true, &h.Conditional[i].When
+		}
+	}
+
+	return false, nil
+}
+
+// PatternMatch is an ignore pattern which changed the match result of a namespace path.
+type PatternMatch struct {
+	Pattern   string           // The pattern or namespace path.
+	IsPath    bool             // If `Pattern` is a namespace path (full match).
+	Inverted  bool             // If the pattern is inverted by "!" and reverted a match.
+	Condition *IgnoreCondition // The condition of conditional patterns.
+}
+
+// ExplainMatches is the same as `MatchesCondition` but
+// also returns all patterns which changed the match result in evaluation order.
+func (h *HookPatterns) ExplainMatches(namespacePath string) (matched bool, matches []PatternMatch) {
+	var cond *IgnoreCondition
+
+	onMatch := func(pattern string, isPath bool, inverted bool) {
+		matches = append(matches,
+			PatternMatch{Pattern: pattern, IsPath: isPath, Inverted: inverted, Condition: cond})
+	}
+
+	if matched = matchPatterns(h.Patterns, h.NamespacePaths, namespacePath, onMatch); matched {
+		return
+	}
+
+	for i := range h.Conditional {
+		c := &h.Conditional[i]
+		if !c.fulfilled {
+			continue
+		}
+
+		cond = &c.When
+		if matched = matchPatterns(c.Patterns, c.NamespacePaths, namespacePath, onMatch); matched {
+			return
+		}
+	}
+
+	return
+}
 
-	for _, p := range h.Patterns {
+// matchPatterns returns true if `namespacePath` matches any of the patterns `patterns`
+// or namespace paths `namespacePaths` and otherwise `false`.
+// The optional callback `onMatch` is called for each pattern which changed the result.
+func matchPatterns(
+	patterns []string,
+	namespacePaths []string,
+	namespacePath string,
+	onMatch func(pattern string, isPath bool, inverted bool)) (matched bool) {
+
+	for _, p := range patterns {
 
 		// Note: Only forward slashes need to be used here in `hookPath`
 		cm.DebugAssert(!strings.Contains(namespacePath, `\`),
@@ -225,6 +314,10 @@ func (h *HookPatterns) Matches(namespacePath string) (matched bool) {
 			continue
 		}
 
+		if onMatch != nil && isMatch && (matched == inverted) {
+			onMatch(p, false, inverted)
+		}
+
 		if inverted {
 			matched = matched && !isMatch
 		} else {
@@ -234,26 +327,40 @@ func (h *HookPatterns) Matches(namespacePath string) (matched bool) {
 
 	// The full matches can only change the result to `true`
 	// They have no inversion "!" prefix.
-	matched = matched || strs.Includes(h.NamespacePaths, namespacePath)
+	if !matched && strs.Includes(namespacePaths, namespacePath) {
+		matched = true
+
+		if onMatch != nil {
+			onMatch(namespacePath, true, false)
+		}
+	}
 
 	return
 }
 
 // IsEmpty checks if there are any patterns stored.
 func (h *HookPatterns) IsEmpty() bool {
-	return len(h.Patterns)+len(h.NamespacePaths) == 0
+	return h.GetCount() == 0
 }
 
 // IsIgnored returns `true` if the hooksPath is ignored by either the worktree patterns or the user patterns
 // and otherwise `false`. The second value is `true` if it was ignored by the user patterns.
 func (h *RepoIgnorePatterns) IsIgnored(namespacePath string) (bool, bool) {
-	if h.HooksDir.Matches(namespacePath) {
-		return true, false
-	} else if h.User.Matches(namespacePath) {
-		return true, true
+	ignored, byUser, _ := h.IsIgnoredCondition(namespacePath)
+
+	return ignored, byUser
+}
+
+// IsIgnoredCondition is the same as `IsIgnored` but also returns the condition
+// of the conditional patterns which made the hook ignored (if any).
+func (h *RepoIgnorePatterns) IsIgnoredCondition(namespacePath string) (bool, bool, *IgnoreCondition) {
+	if matched, cond := h.HooksDir.MatchesCondition(namespacePath); matched {
+		return true, false, cond
+	} else if matched, cond := h.User.MatchesCondition(namespacePath); matched {
+		return true, true, cond
 	}
 
-	return false, false
+	return false, false, nil
 }
 
 // GetHookIgnoreFileHooksDir gets ignores files inside the hook directory.
@@ -364,6 +471,19 @@ func LoadIgnorePatterns(file string) (patterns HookPatterns, err error) {
 
 	patterns.Patterns = strs.Filter(patterns.Patterns, patternIsValid)
 
+	for i := range data.Conditional {
+		c := &data.Conditional[i]
+
+		if e := c.When.validate(); e != nil {
+			err = cm.CombineErrors(err, cm.ErrorF("Condition '%v' in file '%s' is invalid.", i, file), e)
+
+			continue
+		}
+
+		c.Patterns = strs.Filter(c.Patterns, patternIsValid)
+		patterns.Conditional = append(patterns.Conditional, *c)
+	}
+
 	return
 }
 
@@ -379,12 +499,20 @@ func IsHookPatternValid(pattern string) bool {
 }
 
 // StoreIgnorePatterns stores patterns.
+// The file is written with version 1 if there are no conditional patterns,
+// such that older Githooks versions can still read it.
 func StoreIgnorePatterns(patterns HookPatterns, file string) (err error) {
 
+	version := hookIgnoreFileVersion
+	if len(patterns.Conditional) == 0 {
+		version = 1
+	}
+
 	data := hookIgnoreFile{
-		Version:        hookIgnoreFileVersion,
+		Version:        version,
 		Patterns:       strs.MakeUnique(patterns.Patterns),
-		NamespacePaths: strs.MakeUnique(patterns.NamespacePaths)}
+		NamespacePaths: strs.MakeUnique(patterns.NamespacePaths),
+		Conditional:    patterns.Conditional}
 
 	return cm.StoreYAML(file, &data)
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 
+	cm "github.com/gabyx/githooks/githooks/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -58,3 +59,138 @@ version: 999999
 		assert.Contains(t, e.Error(), "Githooks only supports version >= 1")
 	}
 }
+
+func TestIgnoreConditions(t *testing.T) {
+	f, e := os.CreateTemp("", "")
+	assert.Nil(t, e)
+
+	defer os.Remove(f.Name())
+	_, e = io.WriteString(f,
+		`
+patterns:
+  - "pre-commit/always"
+conditional:
+  - patterns:
+      - "pre-commit/heavy*"
+    when:
+      branch: "release/*"
+  - namespace-paths:
+      - "ns:gh-self/pre-commit/lint"
+    when:
+      env: GH_TEST_CI
+      hook-args: "*--amend*"
+version: 2
+`)
+	assert.Nil(t, e)
+
+	patterns, e := LoadIgnorePatterns(f.Name())
+	assert.Nil(t, e)
+	assert.Equal(t, 3, patterns.GetCount())
+	patterns.MakeRelativePatternsAbsolute("gh-self", "")
+
+	// Not evaluated: conditional patterns never match.
+	assert.True(t, patterns.Matches("ns:gh-self/pre-commit/always"))
+	assert.False(t, patterns.Matches("ns:gh-self/pre-commit/heavy-lint"))
+
+	patterns.EvaluateConditions(&IgnoreConditionContext{Branch: "main"})
+	assert.False(t, patterns.Matches("ns:gh-self/pre-commit/heavy-lint"))
+
+	patterns.EvaluateConditions(&IgnoreConditionContext{Branch: "release/1.0"})
+	matched, cond := patterns.MatchesCondition("ns:gh-self/pre-commit/heavy-lint")
+	assert.True(t, matched)
+	assert.Equal(t, "branch: 'release/*'", cond.String())
+
+	matched, cond = patterns.MatchesCondition("ns:gh-self/pre-commit/always")
+	assert.True(t, matched)
+	assert.Nil(t, cond)
+
+	t.Setenv("GH_TEST_CI", "")
+	ctx := IgnoreConditionContext{HookArgs: []string{"--amend"}, HasHookArgs: true}
+	patterns.EvaluateConditions(&ctx)
+	assert.False(t, patterns.Matches("ns:gh-self/pre-commit/lint"))
+
+	t.Setenv("GH_TEST_CI", "true")
+	patterns.EvaluateConditions(&ctx)
+	assert.True(t, patterns.Matches("ns:gh-self/pre-commit/lint"))
+
+	// Hook arguments are not known.
+	ctx.HasHookArgs = false
+	patterns.EvaluateConditions(&ctx)
+	assert.False(t, patterns.Matches("ns:gh-self/pre-commit/lint"))
+
+	// Round trip.
+	assert.Nil(t, StoreIgnorePatterns(patterns, f.Name()))
+	patterns, e = LoadIgnorePatterns(f.Name())
+	assert.Nil(t, e)
+	assert.Equal(t, 2, len(patterns.Conditional))
+	assert.Equal(t, "*--amend*", patterns.Conditional[1].When.HookArgs)
+
+	var data hookIgnoreFile
+	assert.Nil(t, cm.LoadYAML(f.Name(), &data))
+	assert.Equal(t, 2, data.Version)
+
+	// Without conditional patterns version 1 is stored.
+	assert.Nil(t, StoreIgnorePatterns(HookPatterns{Patterns: patterns.Patterns}, f.Name()))
+	assert.Nil(t, cm.LoadYAML(f.Name(), &data))
+	assert.Equal(t, 1, data.Version)
+
+	// Conditions without any condition or with malformed patterns are invalid.
+	assert.Nil(t, os.WriteFile(f.Name(), []byte(`
+conditional:
+  - patterns: ["a"]
+  - patterns: ["b"]
+    when:
+      branch: "[a"
+  - patterns: ["c"]
+    when:
+      env: "=value"
+version: 2
+`), 0600)) // nolint: gomnd
+	patterns, e = LoadIgnorePatterns(f.Name())
+	assert.NotNil(t, e)
+	assert.Equal(t, 0, len(patterns.Conditional))
+}
+
+func TestIgnoreExplainMatches(t *testing.T) {
+	pattern := HookPatterns{
+		Patterns:       []string{"ns:a/**", "ns:a/pre-commit/*", "!ns:a/pre-commit/keep*", "!ns:a/other"},
+		NamespacePaths: []string{"ns:a/pre-commit/keep-2"},
+		Conditional: []ConditionalHookPatterns{
+			{Patterns: []string{"ns:a/pre-commit/keep-3"}, When: IgnoreCondition{Branch: "main"}}}}
+
+	matched, matches := pattern.ExplainMatches("ns:a/pre-commit/lint")
+	assert.True(t, matched)
+	assert.Equal(t, []PatternMatch{{Pattern: "ns:a/**"}}, matches)
+
+	matched, matches = pattern.ExplainMatches("ns:a/pre-commit/keep-1")
+	assert.False(t, matched)
+	assert.Equal(t, []PatternMatch{
+		{Pattern: "ns:a/**"},
+		{Pattern: "!ns:a/pre-commit/keep*", Inverted: true}}, matches)
+
+	matched, matches = pattern.ExplainMatches("ns:a/pre-commit/keep-2")
+	assert.True(t, matched)
+	assert.Equal(t, 3, len(matches))
+	assert.Equal(t, PatternMatch{Pattern: "ns:a/pre-commit/keep-2", IsPath: true}, matches[2])
+
+	pattern.EvaluateConditions(&IgnoreConditionContext{Branch: "main"})
+	matched, matches = pattern.ExplainMatches("ns:a/pre-commit/keep-3")
+	assert.True(t, matched)
+	assert.Equal(t, 3, len(matches))
+	assert.Equal(t, &pattern.Conditional[0].When, matches[2].Condition)
+}
+
+func TestEnvSkipPatterns(t *testing.T) {
+	t.Setenv(EnvVariableSkip, " ns:lint/** , pre-commit/slow.sh,,")
+
+	patterns, e := GetEnvSkipPatterns("my-hooks")
+	assert.Nil(t, e)
+	assert.Equal(t, []string{"ns:lint/**", "ns:my-hooks/pre-commit/slow.sh"}, patterns.Patterns)
+	assert.True(t, patterns.Matches("ns:lint/pre-commit/check"))
+	assert.True(t, patterns.Matches("ns:my-hooks/pre-commit/slow.sh"))
+	assert.False(t, patterns.Matches("ns:my-hooks/pre-commit/fast.sh"))
+
+	t.Setenv(EnvVariableSkip, "[a")
+	_, e = GetEnvSkipPatterns("my-hooks")
+	assert.NotNil(t, e)
+}
@@ -4,6 +4,7 @@ import (
 	"io"
 	"os"
 	"path"
+	"regexp"
 	"strings"
 
 	ref "github.com/distribution/distribution/reference"
@@ -211,6 +212,11 @@ func UpdateImages(
 		return cm.CombineErrors(cm.Error("Creating container manager failed."), err)
 	}
 
+	policy, err := LoadTrustPolicy(gitx)
+	if err != nil {
+		return
+	}
+
 	var imagesConfig ImagesConfigFile
 
 	imagesConfig, err = loadImagesConfigFile(configFile)
@@ -246,6 +252,18 @@ func UpdateImages(
 		}
 
 		if img.Build == nil {
+			if allowed, registry, e := policy.IsImageAllowed(pullSrc); e != nil {
+				err = cm.CombineErrors(err, e)
+
+				continue
+			} else if !allowed {
+				err = cm.CombineErrors(err,
+					cm.ErrorF("Pulling image '%s' in '%s' from registry '%s'\n"+
+						"is not allowed by the trust policy '%s'.", pullSrc, configFile, registry, policy.File))
+
+				continue
+			}
+
 			e := pullImage(
 				log,
 				mgr,
@@ -262,6 +280,20 @@ func UpdateImages(
 			}
 
 		} else if img.Pull == nil {
+			dockerfile := path.Join(repositoryDir, img.Build.Dockerfile)
+			if allowed, baseImage, registry, e := policy.IsBuildAllowed(dockerfile); e != nil {
+				err = cm.CombineErrors(err, e)
+
+				continue
+			} else if !allowed {
+				err = cm.CombineErrors(err,
+					cm.ErrorF("Building image '%s' in '%s' from base image '%s' of registry '%s'\n"+
+						"is not allowed by the trust policy '%s'.",
+						imageRef, configFile, baseImage, registry, policy.File))
+
+				continue
+			}
+
 			e := buildImage(
 				log,
 				mgr,
@@ -288,6 +320,165 @@ func UpdateImages(
 	return
 }
 
+// GetImageReferences gets all image references which are built or pulled
+// by the images config file in the hooks directory `hooksDir`.
+func GetImageReferences(hooksDir string) (refs []string, err error) {
+	return getImageReferences(hooksDir, func(*ImageConfig) bool { return true })
+}
+
+// GetBuiltImageReferences gets all image references which are built (not pulled)
+// by the images config file in the hooks directory `hooksDir`.
+func GetBuiltImageReferences(hooksDir string) (refs []string, err error) {
+	return getImageReferences(hooksDir,
+		func(img *ImageConfig) bool { return img.Build != nil && img.Pull == nil })
+}
+
+// GetLocallyBuiltImageReferences gets all image references which are built (not pulled)
+// by the images config file in the hooks directory `hooksDir` and which
+// exist locally as built by Githooks.
+func GetLocallyBuiltImageReferences(gitx *git.Context, hooksDir string) (refs []string, err error) {
+	built, err := GetBuiltImageReferences(hooksDir)
+	if err != nil || len(built) == 0 {
+		return
+	}
+
+	mgr, err := container.NewManager(gitx.GetConfig(GitCKContainerManager, git.Traverse))
+	if err != nil {
+		return nil, cm.CombineErrors(cm.Error("Creating container manager failed."), err)
+	}
+
+	for _, imageRef := range built {
+		if isBuilt, e := IsImageBuiltLocally(mgr, imageRef); e != nil {
+			err = cm.CombineErrors(err, e)
+		} else if isBuilt {
+			refs = append(refs, imageRef)
+		}
+	}
+
+	return
+}
+
+// IsImageBuiltLocally checks if the image with reference `imageRef`
+// exists locally and has been built by Githooks.
+func IsImageBuiltLocally(mgr container.IManager, imageRef string) (bool, error) {
+	exists, err := mgr.ImageExists(imageRef)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	version, err := mgr.ImageLabel(imageRef, container.ImageLabelVersion)
+	if err != nil {
+		return false, cm.CombineErrors(cm.ErrorF("Could not inspect image '%s'.", imageRef), err)
+	}
+
+	return strs.IsNotEmpty(version), nil
+}
+
+func getImageReferences(hooksDir string, filter func(*ImageConfig) bool) (refs []string, err error) {
+	configFile := GetRepoImagesFile(hooksDir)
+	if !cm.IsFile(configFile) {
+		return
+	}
+
+	namespace, err := GetHooksNamespace(hooksDir)
+	if err != nil {
+		return
+	}
+
+	imagesConfig, err := loadImagesConfigFile(configFile)
+	if err != nil {
+		return
+	}
+
+	for imageRef, img := range imagesConfig.Images {
+		if !filter(&img) {
+			continue
+		}
+
+		imageRef, e := addImageReferenceSuffix(imageRef, configFile, namespace)
+		if e != nil {
+			err = cm.CombineErrors(err, e)
+
+			continue
+		}
+
+		refs = append(refs, imageRef)
+	}
+
+	return
+}
+
+var dockerfileFromRe = regexp.MustCompile(`(?i)^\s*FROM\s+(?:--\S+\s+)*(\S+)(?:\s+AS\s+(\S+))?`)
+var dockerfileArgRe = regexp.MustCompile(`(?i)^\s*ARG\s+([A-Za-z_][A-Za-z0-9_]*)(?:=(\S*))?`)
+var dockerfileCopyFromRe = regexp.MustCompile(`(?i)^\s*COPY\s+(?:--\S+\s+)*?--from=(\S+)`)
+var dockerfileRunMountFromRe = regexp.MustCompile(`(?i)--mount=\S*?\bfrom=([^,\s]+)`)
+var dockerfileStageIndexRe = regexp.MustCompile(`^\d+$`)
+
+// GetDockerfileBaseImages gets all base image references in the `FROM` instructions,
+// `COPY --from=<image>` and `RUN --mount=from=<image>` of the Dockerfile `dockerfile`.
+// Global build arguments with default values are expanded.
+// References to previous stages and `scratch` are skipped.
+func GetDockerfileBaseImages(dockerfile string) (images []string, err error) {
+	content, err := os.ReadFile(dockerfile)
+	if err != nil {
+		return nil, cm.CombineErrors(cm.ErrorF("Could not read Dockerfile '%s'.", dockerfile), err)
+	}
+
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\\\n", " ")
+
+	args := make(map[string]string)
+	stages := make(map[string]bool)
+	seenFrom := false
+
+	addImage := func(image string) {
+		image = os.Expand(image, func(name string) string { return args[name] })
+		if !strings.EqualFold(image, "scratch") &&
+			!stages[strings.ToLower(image)] &&
+			!dockerfileStageIndexRe.MatchString(image) {
+			images = append(images, image)
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if m := dockerfileArgRe.FindStringSubmatch(line); m != nil {
+			if !seenFrom {
+				args[m[1]] = strings.Trim(m[2], `"'`)
+			}
+
+			continue
+		}
+
+		if m := dockerfileCopyFromRe.FindStringSubmatch(line); m != nil {
+			addImage(m[1])
+
+			continue
+		}
+
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(line)), "RUN") {
+			for _, m := range dockerfileRunMountFromRe.FindAllStringSubmatch(line, -1) {
+				addImage(m[1])
+			}
+
+			continue
+		}
+
+		m := dockerfileFromRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		seenFrom = true
+
+		addImage(m[1])
+
+		if strs.IsNotEmpty(m[2]) {
+			stages[strings.ToLower(m[2])] = true
+		}
+	}
+
+	return images, nil
+}
+
 // addImageReferenceSuffix adds the `namespace` to a image name reference at the place `${namespace}`.
 func addImageReferenceSuffix(imageRef string, file string, namespace string) (string, error) {
 	if !strs.IsEmpty(namespace) {
@@ -14,6 +14,8 @@ const (
 	NamespaceRepositoryHook = "gh-self"
 	// NamespaceReplacedHook is the namespace for replaced hooks.
 	NamespaceReplacedHook = "gh-replaced"
+	// NamespaceExternalHook is the namespace for hooks of other hook managers.
+	NamespaceExternalHook = "gh-external"
 
 	// NamespacePrefix prefixes the namespace part in the namespace path of a hook.
 	NamespacePrefix = "ns:"
@@ -0,0 +1,276 @@
+package hooks
+
+import (
+	"path/filepath"
+	"time"
+
+	ref "github.com/distribution/distribution/reference"
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
+	strs "github.com/gabyx/githooks/githooks/strings"
+)
+
+// DefaultTrustPolicyFile is the system-wide trust policy file
+// used if `githooks.trustPolicy` is not set in the system Git config.
+const DefaultTrustPolicyFile = "/etc/githooks/policy.yaml"
+
+// TrustPolicyShared whitelists hooks of shared repositories as trusted.
+type TrustPolicyShared struct {
+	// Glob pattern matching the URL of the shared repository.
+	URL string `yaml:"url"`
+
+	// Optional glob pattern matching the namespace of the shared repository.
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// TrustPolicy is an organization-wide trust policy
+// which overrules the trust settings of users.
+type TrustPolicy struct {
+	// The file this policy was loaded from. Empty if no policy exists.
+	File string `yaml:"-"`
+
+	// Shared repositories whose hooks are trusted automatically.
+	TrustedShared []TrustPolicyShared `yaml:"trustedShared"`
+
+	// Glob patterns on remote URLs of repositories
+	// which are not allowed to be trusted completely (`trust-all`).
+	ForbidTrustAll []string `yaml:"forbidTrustAll"`
+
+	// Glob patterns on registries which containerized hooks can use.
+	// All registries are allowed if empty.
+	AllowedRegistries []string `yaml:"allowedRegistries"`
+
+	// The maximal duration after which trusted hooks expire, e.g. `720h`.
+	TrustExpiry string `yaml:"trustExpiry"`
+	trustExpiry time.Duration
+
+	// Glob patterns on namespace paths of hooks
+	// which cannot be skipped by `GITHOOKS_SKIP`.
+	ForbidSkip []string `yaml:"forbidSkip"`
+
+	// The version of the file.
+	Version int `yaml:"version"`
+}
+
+// Version for TrustPolicy.
+// Version 1: Initial.
+// Version 2: Added `trustExpiry`.
+// Version 3: Added `forbidSkip`.
+const trustPolicyVersion int = 3
+
+// GetTrustPolicyFile gets the path of the trust policy file.
+func GetTrustPolicyFile(gitx *git.Context) string {
+	file := gitx.GetConfig(GitCKTrustPolicy, git.SystemScope)
+	if strs.IsEmpty(file) {
+		return DefaultTrustPolicyFile
+	}
+
+	return filepath.ToSlash(file)
+}
+
+// LoadTrustPolicy loads the organization-wide trust policy.
+// An empty policy is returned if the policy file does not exist.
+func LoadTrustPolicy(gitx *git.Context) (policy TrustPolicy, err error) {
+	file := GetTrustPolicyFile(gitx)
+	if !cm.IsFile(file) {
+		return
+	}
+
+	policy.Version = trustPolicyVersion
+	if err = cm.LoadYAML(file, &policy); err != nil {
+		return TrustPolicy{}, cm.CombineErrors(cm.ErrorF("Could not load trust policy '%s'.", file), err)
+	}
+
+	if policy.Version < 1 || policy.Version > trustPolicyVersion {
+		return TrustPolicy{}, cm.ErrorF(
+			"File '%s' has version '%v'. "+
+				"This version of Githooks only supports version >= 1 and <= '%v'.",
+			file, policy.Version, trustPolicyVersion)
+	}
+
+	policy.File = file
+	err = policy.validate()
+
+	return
+}
+
+// validate validates all glob patterns in the policy.
+func (p *TrustPolicy) validate() (err error) {
+	check := func(pattern string, what string) {
+		if _, e := cm.GlobMatch(pattern, ""); e != nil {
+			err = cm.CombineErrors(err,
+				cm.ErrorF("Pattern '%s' for %s in trust policy '%s' is invalid.", pattern, what, p.File))
+		}
+	}
+
+	for i := range p.TrustedShared {
+		if strs.IsEmpty(p.TrustedShared[i].URL) {
+			err = cm.CombineErrors(err,
+				cm.ErrorF("Trusted shared repository entry '%v' in trust policy '%s' has no URL.", i, p.File))
+		}
+
+		check(p.TrustedShared[i].URL, "trusted shared repositories")
+
+		if strs.IsNotEmpty(p.TrustedShared[i].Namespace) {
+			check(p.TrustedShared[i].Namespace, "trusted shared namespaces")
+		}
+	}
+
+	for _, pattern := range p.ForbidTrustAll {
+		check(pattern, "forbidden 'trust-all'")
+	}
+
+	for _, pattern := range p.AllowedRegistries {
+		check(pattern, "allowed registries")
+	}
+
+	for _, pattern := range p.ForbidSkip {
+		check(pattern, "forbidden skips")
+	}
+
+	if strs.IsNotEmpty(p.TrustExpiry) {
+		d, e := time.ParseDuration(p.TrustExpiry)
+		if e != nil || d < 0 {
+			err = cm.CombineErrors(err,
+				cm.ErrorF("Trust expiry '%s' in trust policy '%s' is not a valid duration.", p.TrustExpiry, p.File))
+		}
+
+		p.trustExpiry = d
+	}
+
+	return
+}
+
+// GetTrustExpiry gets the maximal duration after which trusted hooks expire.
+// Zero means no expiry is enforced.
+func (p *TrustPolicy) GetTrustExpiry() time.Duration {
+	return p.trustExpiry
+}
+
+// IsSet reports if a trust policy is in effect.
+func (p *TrustPolicy) IsSet() bool {
+	return strs.IsNotEmpty(p.File)
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := cm.GlobMatch(pattern, value); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsSharedTrusted reports if the hooks of the shared repository with
+// URL `url` and namespace `namespace` are trusted by the policy.
+func (p *TrustPolicy) IsSharedTrusted(url string, namespace string) bool {
+	for i := range p.TrustedShared {
+		e := &p.TrustedShared[i]
+
+		if matched, _ := cm.GlobMatch(e.URL, url); !matched {
+			continue
+		}
+
+		if strs.IsEmpty(e.Namespace) {
+			return true
+		}
+
+		if matched, _ := cm.GlobMatch(e.Namespace, namespace); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsTrustAllForbidden reports if the policy forbids trusting
+// all hooks of the repository in `gitx` because of one of its remote URLs.
+// Reports the matching remote URL.
+func (p *TrustPolicy) IsTrustAllForbidden(gitx *git.Context) (forbidden bool, remoteURL string) {
+	if len(p.ForbidTrustAll) == 0 {
+		return
+	}
+
+	for _, remote := range gitx.GetConfigRegex(`^remote\..*\.url$`, git.LocalScope) {
+		if matchesAny(p.ForbidTrustAll, remote.Value) {
+			return true, remote.Value
+		}
+	}
+
+	return
+}
+
+// IsSkipForbidden reports if the policy forbids skipping the hook
+// with namespace path `namespacePath` by `GITHOOKS_SKIP`.
+func (p *TrustPolicy) IsSkipForbidden(namespacePath string) bool {
+	return matchesAny(p.ForbidSkip, namespacePath)
+}
+
+// GetImageRegistry gets the registry of the image reference `imageRef`.
+// References without a registry resolve to `docker.io`.
+func GetImageRegistry(imageRef string) (string, error) {
+	named, err := ref.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return "", cm.CombineErrors(cm.ErrorF("Could not parse image reference '%s'.", imageRef), err)
+	}
+
+	return ref.Domain(named), nil
+}
+
+// IsImageAllowed reports if the image reference `imageRef`
+// is from a registry allowed by the policy.
+// Reports the registry of the image.
+func (p *TrustPolicy) IsImageAllowed(imageRef string) (allowed bool, registry string, err error) {
+	registry, err = GetImageRegistry(imageRef)
+	if err != nil {
+		return
+	}
+
+	if len(p.AllowedRegistries) == 0 {
+		return true, registry, nil
+	}
+
+	allowed = matchesAny(p.AllowedRegistries, registry)
+
+	return
+}
+
+// IsBuildAllowed reports if all base images of the Dockerfile `dockerfile`
+// are from registries allowed by the policy.
+// Reports the first base image which is not allowed and its registry.
+func (p *TrustPolicy) IsBuildAllowed(dockerfile string) (allowed bool, image string, registry string, err error) {
+	if len(p.AllowedRegistries) == 0 {
+		return true, "", "", nil
+	}
+
+	images, err := GetDockerfileBaseImages(dockerfile)
+	if err != nil {
+		return
+	}
+
+	for _, image = range images {
+		allowed, registry, err = p.IsImageAllowed(image)
+		if err != nil || !allowed {
+			return
+		}
+	}
+
+	return true, "", "", nil
+}
+
+// GetHookImageReference gets the image reference of a
+// hook run configuration `hookPath` of a hook in namespace `hookNamespace`.
+// Returns an empty reference if the hook is not run containerized.
+func GetHookImageReference(hookPath string, hookNamespace string) (string, error) {
+	if filepath.Ext(hookPath) != ".yaml" || cm.IsExecutable(hookPath) {
+		return "", nil
+	}
+
+	config, err := loadRunnerConfig(hookPath)
+	if err != nil || strs.IsEmpty(config.Image.Reference) {
+		return "", err
+	}
+
+	return addImageReferenceSuffix(config.Image.Reference, hookPath, hookNamespace)
+}
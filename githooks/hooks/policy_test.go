@@ -0,0 +1,139 @@
+package hooks
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrustPolicy(t *testing.T) {
+	dir, err := os.MkdirTemp("", "githooks-policy")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	policyFile := path.Join(dir, "policy.yaml")
+	systemConfig := path.Join(dir, "gitconfig")
+	t.Setenv("GIT_CONFIG_SYSTEM", systemConfig)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "")
+
+	gitx := git.NewCtxAt(dir)
+	assert.Nil(t, gitx.Check("init"))
+	assert.Nil(t, gitx.Check("config", "--file", systemConfig, GitCKTrustPolicy, policyFile))
+	assert.Nil(t, gitx.Check("remote", "add", "origin", "https://github.com/other/repo.git"))
+
+	// No policy file.
+	policy, err := LoadTrustPolicy(gitx)
+	assert.Nil(t, err)
+	assert.False(t, policy.IsSet())
+	allowed, registry, err := policy.IsImageAllowed("alpine:3.17")
+	assert.Nil(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, "docker.io", registry)
+
+	assert.Nil(t, cm.StoreYAML(policyFile,
+		&TrustPolicy{
+			TrustedShared: []TrustPolicyShared{
+				{URL: "https://github.com/my-org/*"},
+				{URL: "https://gitlab.com/**", Namespace: "my-org-*"}},
+			ForbidTrustAll:    []string{"https://github.com/other/*"},
+			AllowedRegistries: []string{"registry.my-org.com", "*.my-org.io"},
+			TrustExpiry:       "24h",
+			ForbidSkip:        []string{"ns:security/**"},
+			Version:           3}))
+
+	policy, err = LoadTrustPolicy(gitx)
+	assert.Nil(t, err)
+	assert.True(t, policy.IsSet())
+	assert.Equal(t, policyFile, policy.File)
+	assert.Equal(t, 24*time.Hour, policy.GetTrustExpiry())
+
+	expiry, err := GetEffectiveTrustExpiry(gitx, &policy)
+	assert.Nil(t, err)
+	assert.Equal(t, 24*time.Hour, expiry)
+
+	assert.Nil(t, SetTrustExpiry(gitx, 2*time.Hour, false, git.LocalScope))
+	expiry, err = GetEffectiveTrustExpiry(gitx, &policy)
+	assert.Nil(t, err)
+	assert.Equal(t, 2*time.Hour, expiry)
+
+	assert.True(t, policy.IsSharedTrusted("https://github.com/my-org/hooks.git", "any"))
+	assert.False(t, policy.IsSharedTrusted("https://github.com/other/hooks.git", "any"))
+	assert.True(t, policy.IsSharedTrusted("https://gitlab.com/group/hooks.git", "my-org-checks"))
+	assert.False(t, policy.IsSharedTrusted("https://gitlab.com/group/hooks.git", "checks"))
+
+	forbidden, remoteURL := policy.IsTrustAllForbidden(gitx)
+	assert.True(t, forbidden)
+	assert.Equal(t, "https://github.com/other/repo.git", remoteURL)
+
+	assert.Nil(t, gitx.Check("remote", "set-url", "origin", "https://github.com/my-org/repo.git"))
+	forbidden, _ = policy.IsTrustAllForbidden(gitx)
+	assert.False(t, forbidden)
+
+	allowed, _, err = policy.IsImageAllowed("registry.my-org.com/hooks/lint:1.0")
+	assert.Nil(t, err)
+	assert.True(t, allowed)
+
+	allowed, _, err = policy.IsImageAllowed("eu.my-org.io/lint:1.0")
+	assert.Nil(t, err)
+	assert.True(t, allowed)
+
+	allowed, registry, err = policy.IsImageAllowed("alpine:3.17")
+	assert.Nil(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, "docker.io", registry)
+
+	dockerfile := path.Join(dir, "Dockerfile")
+	assert.Nil(t, os.WriteFile(dockerfile, []byte(
+		"ARG BASE=registry.my-org.com/base\n"+
+			"FROM --platform=linux/amd64 ${BASE}:1.0 AS build\n"+
+			"RUN echo \\\n  build\n"+
+			"FROM build AS final\n"+
+			"FROM scratch\n"), 0644))
+
+	images, err := GetDockerfileBaseImages(dockerfile)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"registry.my-org.com/base:1.0"}, images)
+
+	allowed, _, _, err = policy.IsBuildAllowed(dockerfile)
+	assert.Nil(t, err)
+	assert.True(t, allowed)
+
+	assert.Nil(t, os.WriteFile(dockerfile, []byte(
+		"FROM registry.my-org.com/base:1.0 AS build\n"+
+			"from alpine:3.17\n"), 0644))
+
+	allowed, image, registry, err := policy.IsBuildAllowed(dockerfile)
+	assert.Nil(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, "alpine:3.17", image)
+	assert.Equal(t, "docker.io", registry)
+
+	assert.Nil(t, os.WriteFile(dockerfile, []byte(
+		"FROM registry.my-org.com/base:1.0 AS build\n"+
+			"COPY --from=build /a /a\n"+
+			"COPY --from=0 /b /b\n"+
+			"COPY --chown=1000 --from=evil.io/tools:1 /c /c\n"+
+			"RUN --mount=type=bind,from=evil.io/cache:2,target=/d make\n"), 0644))
+
+	images, err = GetDockerfileBaseImages(dockerfile)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"registry.my-org.com/base:1.0", "evil.io/tools:1", "evil.io/cache:2"}, images)
+
+	allowed, image, _, err = policy.IsBuildAllowed(dockerfile)
+	assert.Nil(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, "evil.io/tools:1", image)
+
+	assert.True(t, policy.IsSkipForbidden("ns:security/pre-commit/scan"))
+	assert.False(t, policy.IsSkipForbidden("ns:lint/pre-commit/check"))
+
+	// Wrong version.
+	assert.Nil(t, cm.StoreYAML(policyFile, &TrustPolicy{Version: 4}))
+	_, err = LoadTrustPolicy(gitx)
+	assert.NotNil(t, err)
+}
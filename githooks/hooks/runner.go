@@ -133,7 +133,14 @@ func GetHookRunCmd(
 			return nil, err
 		}
 
-		containerExec, err := mgr.NewHookRunExec(reference, gitx.GetCwd(), rootDir, &exec)
+		// Locally built images and all images in offline mode are never pulled.
+		built, err := GetBuiltImageReferences(hooksDir)
+		if err != nil {
+			return nil, cm.CombineErrors(err, cm.ErrorF("Could not get built images in '%s'.", hooksDir))
+		}
+		noPull := strs.Includes(built, reference) || IsOfflineMode(gitx, git.Traverse, true)
+
+		containerExec, err := mgr.NewHookRunExec(reference, gitx.GetCwd(), rootDir, &exec, noPull)
 
 		if err != nil {
 			return nil, cm.CombineErrors(err, cm.Error("Could not create container hook executor."))
@@ -0,0 +1,139 @@
+package hooks
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/container"
+	"github.com/gabyx/githooks/githooks/git"
+	strs "github.com/gabyx/githooks/githooks/strings"
+)
+
+// SharedGarbage contains all shared clones which are not
+// referenced anymore and their container images.
+type SharedGarbage struct {
+	// All shared clone directories which are not referenced.
+	CloneDirs []string
+
+	// All container images which are only used by the unreferenced clones.
+	Images []string
+}
+
+// FindSharedGarbage finds all shared clones in the install directory which are
+// not referenced by any registered repository, by the repository `repoDir` (can be empty)
+// or the global Git config.
+func FindSharedGarbage(installDir string, gitx *git.Context, repoDir string) (garbage SharedGarbage, err error) {
+	referenced, err := getReferencedSharedCloneDirs(installDir, gitx, repoDir)
+	if err != nil {
+		return
+	}
+
+	entries, err := os.ReadDir(GetSharedDir(installDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+
+		return
+	}
+
+	var usedImages []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		cloneDir := path.Join(GetSharedDir(installDir), e.Name())
+		images, _ := GetImageReferences(GetSharedGithooksDir(cloneDir))
+
+		if strs.Includes(referenced, cloneDir) {
+			usedImages, _ = strs.AppendUnique(usedImages, images...)
+		} else {
+			garbage.CloneDirs = append(garbage.CloneDirs, cloneDir)
+			garbage.Images, _ = strs.AppendUnique(garbage.Images, images...)
+		}
+	}
+
+	garbage.Images = strs.Filter(garbage.Images,
+		func(img string) bool { return !strs.Includes(usedImages, img) })
+
+	sort.Strings(garbage.CloneDirs)
+	sort.Strings(garbage.Images)
+
+	return
+}
+
+// RemoveSharedGarbage removes all unreferenced shared clones and their container images.
+// Images are only removed if `mgr` is not `nil`.
+func RemoveSharedGarbage(garbage *SharedGarbage, mgr container.IManager) (err error) {
+	for _, dir := range garbage.CloneDirs {
+		if e := os.RemoveAll(dir); e != nil {
+			err = cm.CombineErrors(err, cm.ErrorF("Could not remove shared clone '%s'.", dir), e)
+		}
+	}
+
+	if mgr == nil {
+		return
+	}
+
+	for _, img := range garbage.Images {
+		exists, e := mgr.ImageExists(img)
+		if e != nil || !exists {
+			continue
+		}
+
+		if e := mgr.ImageRemove(img); e != nil {
+			err = cm.CombineErrors(err, cm.ErrorF("Could not remove image '%s'.", img), e)
+		}
+	}
+
+	return
+}
+
+// getReferencedSharedCloneDirs gets all shared clone directories which are referenced
+// by any registered repository, by the repository `repoDir` (can be empty)
+// or the global Git config.
+func getReferencedSharedCloneDirs(installDir string, gitx *git.Context, repoDir string) (dirs []string, err error) {
+
+	add := func(repos []SharedRepo, e error) {
+		err = cm.CombineErrors(err, e)
+
+		for i := range repos {
+			if repos[i].IsCloned {
+				dirs, _ = strs.AppendUnique(dirs, filepath.ToSlash(repos[i].RepositoryDir))
+			}
+		}
+	}
+
+	addRepo := func(repoDir string, repoGitx *git.Context) {
+		add(LoadRepoSharedHooks(installDir, repoDir))
+		add(LoadConfigSharedHooks(installDir, repoGitx, git.LocalScope))
+	}
+
+	var registered RegisterRepos
+	if e := registered.Load(installDir, true, true); e != nil {
+		return nil, e
+	}
+
+	for _, gitDir := range registered.GitDirs {
+		repoGitx := git.NewCtxSanitizedAt(gitDir)
+
+		root := gitDir
+		if !repoGitx.IsBareRepo() {
+			root = path.Dir(gitDir)
+		}
+
+		addRepo(root, repoGitx)
+	}
+
+	if strs.IsNotEmpty(repoDir) {
+		addRepo(repoDir, gitx)
+	}
+
+	add(LoadConfigSharedHooks(installDir, gitx, git.GlobalScope))
+
+	return
+}
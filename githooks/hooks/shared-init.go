@@ -0,0 +1,73 @@
+package hooks
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gabyx/githooks/githooks/build"
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
+)
+
+const sharedTemplateDir = "embedded/shared-template"
+
+// InitSharedRepo scaffolds a shared hook repository in `repoDir` with
+// namespace `namespace`. It initializes a Git repository if not yet existing.
+// Existing files are never overwritten. Returns all created files.
+func InitSharedRepo(repoDir string, namespace string) (created []string, err error) {
+	if sanitizeNamespace.MatchString(namespace) {
+		return nil, cm.ErrorF("Namespace '%s' must not contain white spaces or slashes.", namespace)
+	}
+
+	files, err := build.AssetDir(sharedTemplateDir)
+	if err != nil {
+		return nil, cm.CombineErrors(cm.Error("Could not get embedded shared repository template."), err)
+	}
+
+	files[path.Join(HooksDirNameShared, ".namespace")] = []byte(namespace + "\n")
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+
+		if p := path.Join(repoDir, name); cm.IsFile(p) {
+			err = cm.CombineErrors(err, cm.ErrorF("File '%s' already exists.", p))
+		}
+	}
+
+	if err != nil {
+		return
+	}
+
+	if !git.NewCtxAt(repoDir).IsGitRepo() {
+		if err = git.Init(repoDir, false); err != nil {
+			return
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		p := path.Join(repoDir, name)
+
+		if err = os.MkdirAll(path.Dir(p), cm.DefaultFileModeDirectory); err != nil {
+			return
+		}
+
+		if err = os.WriteFile(p, files[name], cm.DefaultFileModeFile); err != nil {
+			return
+		}
+
+		if strings.HasSuffix(name, ".sh") {
+			if err = cm.MakeExecutable(p); err != nil {
+				return
+			}
+		}
+
+		created = append(created, p)
+	}
+
+	return
+}
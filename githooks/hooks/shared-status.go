@@ -0,0 +1,171 @@
+package hooks
+
+import (
+	"path"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
+	strs "github.com/gabyx/githooks/githooks/strings"
+)
+
+// States of a shared repository.
+const (
+	SharedStatePending = "pending" // Not yet cloned.
+	SharedStateActive  = "active"  // Cloned and usable.
+	SharedStateLocal   = "local"   // A local directory which is used directly.
+	SharedStateInvalid = "invalid" // Not usable.
+)
+
+// SharedRepoStatus is the status of a shared repository.
+type SharedRepoStatus struct {
+	URL           string `json:"url"`
+	Type          string `json:"type"`
+	RepositoryDir string `json:"repositoryDir"`
+	State         string `json:"state"`
+
+	// The branch requested in the URL (can be empty)
+	// and the checked out branch.
+	Branch        string `json:"branch"`
+	CurrentBranch string `json:"currentBranch"`
+
+	// If the URL requests a tag (`Branch`) which is checked out as a detached `HEAD`.
+	Pinned bool `json:"pinned"`
+
+	// The clone URL and the configured `remote.origin.url`.
+	ExpectedRemoteURL string `json:"expectedRemoteURL"`
+	RemoteURL         string `json:"remoteURL"`
+
+	// The checked out commit and how many commits
+	// it is ahead/behind the remote branch.
+	Commit string `json:"commit"`
+	Ahead  int    `json:"ahead"`
+	Behind int    `json:"behind"`
+
+	// Files with local modifications (staged, unstaged or untracked).
+	ModifiedFiles []string `json:"modifiedFiles"`
+
+	// If a Git lock file exists, e.g. an update is in progress or has been aborted.
+	Locked bool `json:"locked"`
+
+	// If the remote has been fetched before computing the status.
+	Fetched bool `json:"fetched"`
+
+	// All errors while computing the status.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// IsBranchMismatch reports if the checked out branch is not the requested one.
+func (s *SharedRepoStatus) IsBranchMismatch() bool {
+	return !s.Pinned && strs.IsNotEmpty(s.Branch) && s.Branch != s.CurrentBranch
+}
+
+// IsRemoteURLMismatch reports if the `remote.origin.url` is not the clone URL.
+func (s *SharedRepoStatus) IsRemoteURLMismatch() bool {
+	return s.State == SharedStateActive && s.RemoteURL != s.ExpectedRemoteURL
+}
+
+// HasDrift reports if the shared repository has drifted from its remote
+// or its configuration in any way.
+func (s *SharedRepoStatus) HasDrift() bool {
+	return s.Ahead != 0 || s.Behind != 0 ||
+		len(s.ModifiedFiles) != 0 ||
+		s.IsBranchMismatch() || s.IsRemoteURLMismatch()
+}
+
+// GetSharedRepoStatus gets the status of the shared repository `repo`.
+// If `fetch` is set, the remote branch is fetched first.
+func GetSharedRepoStatus(repo *SharedRepo, sharedType SharedHookType, fetch bool) (status SharedRepoStatus) {
+	status = SharedRepoStatus{
+		URL:               repo.OriginalURL,
+		Type:              GetSharedHookTypeString(sharedType),
+		RepositoryDir:     repo.RepositoryDir,
+		Branch:            repo.Branch,
+		ExpectedRemoteURL: repo.URL}
+
+	addError := func(err error) {
+		if err != nil {
+			status.Errors = append(status.Errors, err.Error())
+		}
+	}
+
+	switch {
+	case !repo.IsCloned:
+		status.State = SharedStateLocal
+		if !cm.IsDirectory(repo.RepositoryDir) {
+			status.State = SharedStateInvalid
+		}
+
+		return
+
+	case !cm.IsDirectory(repo.RepositoryDir):
+		status.State = SharedStatePending
+
+		return
+	}
+
+	gitx := git.NewCtxSanitizedAt(repo.RepositoryDir)
+	if !gitx.IsGitRepo() {
+		status.State = SharedStateInvalid
+
+		return
+	}
+
+	status.State = SharedStateActive
+
+	for _, lock := range []string{"index.lock", "HEAD.lock", "shallow.lock"} {
+		if cm.IsFile(path.Join(repo.RepositoryDir, ".git", lock)) {
+			status.Locked = true
+		}
+	}
+
+	var err error
+	status.RemoteURL, status.CurrentBranch, err = gitx.GetRemoteURLAndBranch("origin")
+
+	// A clone of a tag has a detached `HEAD` and no current branch.
+	status.Pinned = strs.IsNotEmpty(status.Branch) && git.IsTag(gitx, status.Branch)
+	if !status.Pinned {
+		addError(err)
+	}
+
+	status.Commit, err = git.GetCommitSHA(gitx, git.HEAD)
+	addError(err)
+
+	status.ModifiedFiles, err = gitx.GetModifiedFiles()
+	addError(err)
+
+	if status.Pinned {
+		if fetch && !status.IsRemoteURLMismatch() {
+			err = gitx.FetchTag("origin", status.Branch)
+			addError(err)
+			status.Fetched = err == nil
+		}
+
+		status.Ahead, status.Behind, err = gitx.GetAheadBehind(git.HEAD,
+			strs.Fmt("refs/tags/%s^{commit}", status.Branch))
+		addError(err)
+
+		return
+	}
+
+	branch := status.CurrentBranch
+	if strs.IsNotEmpty(status.Branch) {
+		branch = status.Branch
+	}
+
+	if strs.IsEmpty(branch) {
+		addError(cm.ErrorF("Could not determine the branch to compare against."))
+
+		return
+	}
+
+	if fetch && !status.IsRemoteURLMismatch() {
+		err = gitx.FetchBranch("origin", branch, "")
+		addError(err)
+		status.Fetched = err == nil
+	}
+
+	status.Ahead, status.Behind, err = gitx.GetAheadBehind(git.HEAD, "refs/remotes/origin/"+branch)
+	addError(err)
+
+	return
+}
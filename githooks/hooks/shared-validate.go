@@ -0,0 +1,176 @@
+package hooks
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	strs "github.com/gabyx/githooks/githooks/strings"
+)
+
+// ValidateSharedRepo validates all Githooks configuration files in the
+// shared hook repository `repoDir` and reports all errors at once.
+// Returns the hooks directory and all checked files.
+func ValidateSharedRepo(repoDir string) (hooksDir string, checked []string, errs []error) {
+	hooksDir = GetSharedGithooksDir(repoDir)
+
+	addError := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Namespace.
+	namespace, err := GetHooksNamespace(hooksDir)
+	addError(err)
+
+	if nsFile := getNamespaceFile(hooksDir); cm.IsFile(nsFile) {
+		checked = append(checked, nsFile)
+		data, _ := os.ReadFile(nsFile)
+
+		switch {
+		case strings.TrimSpace(string(data)) != namespace:
+			addError(cm.ErrorF("Namespace '%s' in '%s' must not contain white spaces or slashes.",
+				strings.TrimSpace(string(data)), nsFile))
+		case namespace == NamespaceRepositoryHook || namespace == NamespaceReplacedHook ||
+			namespace == NamespaceExternalHook:
+			addError(cm.ErrorF("Namespace '%s' in '%s' is reserved.", namespace, nsFile))
+		}
+	}
+
+	// Ignore files.
+	ignoreFiles := append(
+		[]string{GetHookIgnoreFileHooksDir(hooksDir, "")},
+		GetHookIgnoreFilesHooksDir(hooksDir, ManagedHookNames)...)
+
+	for _, file := range ignoreFiles {
+		if !cm.IsFile(file) {
+			continue
+		}
+
+		checked = append(checked, file)
+		_, err := LoadIgnorePatterns(file)
+		addError(wrapValidateError(err, file))
+	}
+
+	// Images config file.
+	if file := GetRepoImagesFile(hooksDir); cm.IsFile(file) {
+		checked = append(checked, file)
+
+		for _, e := range validateImagesConfig(repoDir, file, namespace) {
+			addError(e)
+		}
+	}
+
+	// Hook run configurations.
+	for _, file := range getRunnerConfigFiles(hooksDir) {
+		checked = append(checked, file)
+
+		for _, e := range validateRunnerConfig(repoDir, file, namespace) {
+			addError(e)
+		}
+	}
+
+	return
+}
+
+func wrapValidateError(err error, file string) error {
+	if err == nil {
+		return nil
+	}
+
+	return cm.CombineErrors(cm.ErrorF("File '%s' is invalid.", file), err)
+}
+
+func validateImagesConfig(repoDir string, file string, namespace string) (errs []error) {
+	config, err := loadImagesConfigFile(file)
+	if err != nil {
+		return []error{wrapValidateError(err, file)}
+	}
+
+	for imageRef, img := range config.Images {
+		if _, e := addImageReferenceSuffix(imageRef, file, namespace); e != nil {
+			errs = append(errs, e)
+		}
+
+		if img.Pull != nil {
+			if _, e := addImageReferenceSuffix(img.Pull.Reference, file, namespace); e != nil {
+				errs = append(errs, e)
+			}
+		}
+
+		if img.Build == nil {
+			continue
+		}
+
+		if strs.IsEmpty(img.Build.Dockerfile) {
+			errs = append(errs, cm.ErrorF("Image '%s' in '%s' has no 'dockerfile' to build.", imageRef, file))
+		} else if p := path.Join(repoDir, img.Build.Dockerfile); !cm.IsFile(p) {
+			errs = append(errs, cm.ErrorF("Dockerfile '%s' of image '%s' in '%s' does not exist.",
+				img.Build.Dockerfile, imageRef, file))
+		}
+
+		if strs.IsNotEmpty(img.Build.Context) && !cm.IsDirectory(path.Join(repoDir, img.Build.Context)) {
+			errs = append(errs, cm.ErrorF("Build context '%s' of image '%s' in '%s' does not exist.",
+				img.Build.Context, imageRef, file))
+		}
+	}
+
+	return
+}
+
+func validateRunnerConfig(repoDir string, file string, namespace string) (errs []error) {
+	config, err := loadRunnerConfig(file)
+	if err != nil {
+		return []error{wrapValidateError(err, file)}
+	}
+
+	if strs.IsEmpty(config.Cmd) {
+		errs = append(errs, cm.ErrorF("Hook run configuration '%s' has no 'cmd'.", file))
+	} else if strings.ContainsAny(config.Cmd, "/\\") &&
+		!strings.Contains(config.Cmd, "${") &&
+		!filepath.IsAbs(config.Cmd) &&
+		!cm.IsFile(path.Join(repoDir, config.Cmd)) {
+		errs = append(errs, cm.ErrorF("Command '%s' in '%s' does not exist\n"+
+			"relative to the repository root.", config.Cmd, file))
+	}
+
+	if strs.IsNotEmpty(config.Image.Reference) {
+		if _, e := addImageReferenceSuffix(config.Image.Reference, file, namespace); e != nil {
+			errs = append(errs, e)
+		}
+	}
+
+	return
+}
+
+// getRunnerConfigFiles gets all hook run configurations in `hooksDir`.
+func getRunnerConfigFiles(hooksDir string) (files []string) {
+	for _, hookName := range ManagedHookNames {
+		dirOrFile := path.Join(hooksDir, hookName)
+
+		if cm.IsDirectory(dirOrFile) {
+			_ = cm.WalkPaths(dirOrFile, func(p string, info os.FileInfo) error {
+				if strings.HasPrefix(path.Base(p), ".") {
+					if info.IsDir() {
+						return filepath.SkipDir
+					}
+
+					return nil
+				}
+
+				if !info.IsDir() && path.Ext(p) == ".yaml" && !cm.IsExecutable(p) {
+					files = append(files, p)
+				}
+
+				return nil
+			})
+		} else if file := dirOrFile + ".yaml"; cm.IsFile(file) {
+			files = append(files, file)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,168 @@
+package hooks
+
+import (
+	"strings"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
+	strs "github.com/gabyx/githooks/githooks/strings"
+)
+
+// Signature types for verifying shared repositories.
+const (
+	SharedSignatureCommit = "commit"
+	SharedSignatureTag    = "tag"
+)
+
+// SharedRepoVerify holds the signature verification settings of a shared repository.
+type SharedRepoVerify struct {
+	// Signature type which is required:
+	// - `commit`: The checked out commit needs to be signed.
+	// - `tag`: A tag pointing to the checked out commit needs to be signed.
+	Signature string `yaml:"signature"`
+
+	// All allowed keys which can have signed the revision.
+	// GPG key ids or fingerprints or SSH key fingerprints or principals.
+	AllowedKeys []string `yaml:"allowedKeys"`
+}
+
+// Validate validates the verification settings.
+func (v *SharedRepoVerify) Validate() error {
+	if v.Signature != SharedSignatureCommit && v.Signature != SharedSignatureTag {
+		return cm.ErrorF("Signature type '%s' is not supported. Use '%s' or '%s'.",
+			v.Signature, SharedSignatureCommit, SharedSignatureTag)
+	}
+
+	if len(v.AllowedKeys) == 0 {
+		return cm.ErrorF("Signature verification needs at least one allowed key.")
+	}
+
+	return nil
+}
+
+// IsKeyAllowed reports if any of the keys `keys` matches an allowed key.
+// GPG long key ids match as suffixes of fingerprints.
+func (v *SharedRepoVerify) IsKeyAllowed(keys []string) bool {
+	normalize := func(s string) string {
+		return strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+	}
+
+	for _, k := range keys {
+		k = normalize(k)
+
+		for _, a := range v.AllowedKeys {
+			a = normalize(a)
+			if strs.IsEmpty(a) {
+				continue
+			}
+
+			if k == a || (len(a) >= 16 && strings.HasSuffix(k, a)) { // nolint: gomnd
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// VerifySignature verifies that the checked out revision in the shared
+// repository is signed by an allowed key in `verify`.
+func (s *SharedRepo) VerifySignature(verify *SharedRepoVerify) (err error) {
+	if err = verify.Validate(); err != nil {
+		return
+	}
+
+	gitx := git.NewCtxSanitizedAt(s.RepositoryDir)
+
+	switch verify.Signature {
+	case SharedSignatureCommit:
+		var keys []string
+		if keys, err = gitx.VerifyCommit(git.HEAD); err != nil {
+			return
+		}
+
+		if !verify.IsKeyAllowed(keys) {
+			return cm.ErrorF("Commit 'HEAD' in '%s' is signed by '%q'\n"+
+				"which is not an allowed key.", s.RepositoryDir, keys)
+		}
+
+	case SharedSignatureTag:
+		tags, e := git.GetTags(gitx, git.HEAD)
+		if e != nil {
+			return e
+		}
+
+		for _, tag := range tags {
+			keys, e := gitx.VerifyTag(tag)
+			if e == nil && verify.IsKeyAllowed(keys) {
+				return nil
+			}
+		}
+
+		return cm.ErrorF("No tag '%q' pointing to 'HEAD' in '%s'\n"+
+			"is signed by an allowed key.", tags, s.RepositoryDir)
+	}
+
+	return
+}
+
+// GetSharedRepoVerifyConfig gets the signature verification settings for
+// all shared repositories from the Git config. Returns `nil` if not set.
+func GetSharedRepoVerifyConfig(gitx *git.Context, scope git.ConfigScope) *SharedRepoVerify {
+	signature := gitx.GetConfig(GitCKSharedVerifySignature, scope)
+	if strs.IsEmpty(signature) {
+		return nil
+	}
+
+	return &SharedRepoVerify{
+		Signature:   signature,
+		AllowedKeys: gitx.GetConfigAll(GitCKSharedAllowedSigningKeys, scope)}
+}
+
+// SetSharedRepoVerifyConfig sets the signature verification settings for
+// all shared repositories in the Git config. If `verify` is `nil`, the settings are reset.
+func SetSharedRepoVerifyConfig(gitx *git.Context, verify *SharedRepoVerify, scope git.ConfigScope) error {
+	if err := gitx.UnsetConfig(GitCKSharedVerifySignature, scope); err != nil {
+		return err
+	}
+
+	if err := gitx.UnsetConfig(GitCKSharedAllowedSigningKeys, scope); err != nil {
+		return err
+	}
+
+	if verify == nil {
+		return nil
+	}
+
+	if err := verify.Validate(); err != nil {
+		return err
+	}
+
+	if err := gitx.SetConfig(GitCKSharedVerifySignature, verify.Signature, scope); err != nil {
+		return err
+	}
+
+	for _, k := range verify.AllowedKeys {
+		if err := gitx.AddConfig(GitCKSharedAllowedSigningKeys, k, scope); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetVerify returns all signature verification settings which need to pass for this
+// shared repository. The settings `config` from the Git config always apply first.
+// Settings from the repository's shared hooks configuration can only add a check and
+// never replace or loosen the Git config.
+func (s *SharedRepo) GetVerify(config *SharedRepoVerify) (verify []*SharedRepoVerify) {
+	if config != nil {
+		verify = append(verify, config)
+	}
+
+	if s.Verify != nil {
+		verify = append(verify, s.Verify)
+	}
+
+	return
+}
@@ -23,6 +23,8 @@ type SharedRepo struct {
 	IsLocal bool // If the original URL points to a local directory.
 
 	RepositoryDir string // The shared hook repository directory.
+
+	Verify *SharedRepoVerify // Optional signature verification settings.
 }
 
 // SharedHookType is the enum type of the shared hook type.
@@ -73,16 +75,20 @@ func GetSharedRepoTagNames() []string {
 type sharedHookConfig struct {
 	// Urls for shared repositories.
 	Urls []string `yaml:"urls"`
+	// Signature verification settings for shared repositories
+	// given by its url.
+	Verify map[string]SharedRepoVerify `yaml:"verify,omitempty"`
 	// The version of the file.
 	Version int `yaml:"version"`
 }
 
 // Version for sharedHookConfig.
 // Version 1: Initial.
-const sharedHookConfigVersion int = 1
+// Version 2: Added signature verification settings `verify`.
+const sharedHookConfigVersion int = 2
 
 func createSharedHookConfig() sharedHookConfig {
-	return sharedHookConfig{Version: sharedHookConfigVersion}
+	return sharedHookConfig{Version: 1}
 }
 
 func loadRepoSharedHooks(file string) (config sharedHookConfig, err error) {
@@ -110,12 +116,28 @@ func loadRepoSharedHooks(file string) (config sharedHookConfig, err error) {
 
 	config.Urls = strs.MakeUnique(config.Urls)
 
+	for url, verify := range config.Verify {
+		if e := verify.Validate(); e != nil {
+			err = cm.CombineErrors(err,
+				cm.ErrorF("Signature verification for '%s' in file '%s' is invalid.", url, file), e)
+		}
+	}
+
+	if err != nil {
+		return
+	}
+
 	return config, nil
 }
 
+// saveRepoSharedHooks stores the shared repositories config.
+// The file is written with version 1 if there are no signature verification settings,
+// such that older Githooks versions can still read it.
 func saveRepoSharedHooks(file string, config *sharedHookConfig) error {
-	// We always store the new version.
 	config.Version = sharedHookConfigVersion
+	if len(config.Verify) == 0 {
+		config.Version = 1
+	}
 
 	config.Urls = strs.MakeUnique(config.Urls)
 
@@ -214,7 +236,9 @@ func parseSharedURLBranch(sharedURL string) (prefix string, branch string, err e
 	return
 }
 
-func parseSharedURL(installDir string, url string) (h SharedRepo, err error) {
+// ParseSharedURL parses the shared repository url `url` with an optional
+// branch suffix `@<branch>`. Clones are located in the install directory `installDir`.
+func ParseSharedURL(installDir string, url string) (h SharedRepo, err error) {
 
 	h = SharedRepo{IsCloned: true, IsLocal: false, OriginalURL: url}
 	doSplit := true
@@ -262,8 +286,12 @@ func parseData(installDir string, config *sharedHookConfig) (hooks []SharedRepo,
 			continue
 		}
 
-		hook, e := parseSharedURL(installDir, url)
+		hook, e := ParseSharedURL(installDir, url)
 		if e == nil {
+			if verify, exists := config.Verify[url]; exists {
+				hook.Verify = &verify
+			}
+
 			hooks = append(hooks, hook)
 		}
 
@@ -285,6 +313,7 @@ func (c *sharedHookConfig) AddURL(url string) (added bool) {
 // RemoveURL removes an url from the config.
 func (c *sharedHookConfig) RemoveURL(url string) (removed int) {
 	c.Urls, removed = strs.Remove(c.Urls, url)
+	delete(c.Verify, url)
 
 	return
 }
@@ -354,7 +383,7 @@ func ModifyRepoSharedHooks(repoDir string, url string, remove bool) (modified bo
 	file := GetRepoSharedFile(repoDir)
 
 	// Try parse it...
-	h, err := parseSharedURL("unneeded", url) // we dont need the install dir...
+	h, err := ParseSharedURL("unneeded", url) // we dont need the install dir...
 	if err != nil {
 		err = cm.CombineErrors(err, cm.ErrorF("Cannot parse url '%s'.", url))
 
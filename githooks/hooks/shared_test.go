@@ -3,8 +3,11 @@ package hooks
 import (
 	"io"
 	"os"
+	"path"
 	"testing"
 
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -79,3 +82,210 @@ version: 999999
 		assert.Contains(t, e.Error(), "Githooks only supports version >= 1")
 	}
 }
+
+func TestSharedVerifyAllowedKeys(t *testing.T) {
+	v := SharedRepoVerify{
+		Signature:   SharedSignatureCommit,
+		AllowedKeys: []string{"3b2c1d0e9f8a7b6c", "SHA256:abc"}}
+
+	assert.Nil(t, v.Validate())
+
+	assert.True(t, v.IsKeyAllowed([]string{"2C9A0C7E1B4C3A9F7D6E5F4A3B2C1D0E9F8A7B6C"}))
+	assert.True(t, v.IsKeyAllowed([]string{"SHA256:abc"}))
+	assert.False(t, v.IsKeyAllowed([]string{"SHA256:abcd"}))
+	assert.False(t, v.IsKeyAllowed(nil))
+
+	v.Signature = "unknown"
+	assert.Error(t, v.Validate())
+	v.Signature = SharedSignatureTag
+	v.AllowedKeys = nil
+	assert.Error(t, v.Validate())
+}
+
+func TestSharedConfigVerify(t *testing.T) {
+	f, e := os.CreateTemp("", "")
+	assert.Nil(t, e)
+
+	defer os.Remove(f.Name())
+	_, e = io.WriteString(f,
+		`
+urls:
+  - "https://github.com/shared/hooks.git@v1.0.0"
+  - "https://github.com/shared/other.git"
+verify:
+  "https://github.com/shared/hooks.git@v1.0.0":
+    signature: tag
+    allowedKeys: ["3B2C1D0E9F8A7B6C"]
+version: 2
+`)
+	assert.Nil(t, e)
+
+	config, e := loadRepoSharedHooks(f.Name())
+	assert.Nil(t, e)
+
+	repos, e := parseData("install", &config)
+	assert.Nil(t, e)
+	assert.Len(t, repos, 2)
+	assert.NotNil(t, repos[0].Verify)
+	assert.Equal(t, SharedSignatureTag, repos[0].Verify.Signature)
+	assert.Nil(t, repos[1].Verify)
+
+	// The Git config always applies, the repository can only add checks.
+	userVerify := &SharedRepoVerify{Signature: SharedSignatureCommit, AllowedKeys: []string{"A1B2C3D4E5F60718"}}
+	assert.Equal(t, []*SharedRepoVerify{userVerify, repos[0].Verify}, repos[0].GetVerify(userVerify))
+	assert.Equal(t, []*SharedRepoVerify{userVerify}, repos[1].GetVerify(userVerify))
+	assert.Equal(t, []*SharedRepoVerify{repos[0].Verify}, repos[0].GetVerify(nil))
+	assert.Empty(t, repos[1].GetVerify(nil))
+
+	assert.Nil(t, saveRepoSharedHooks(f.Name(), &config))
+	config, e = loadRepoSharedHooks(f.Name())
+	assert.Nil(t, e)
+	assert.Equal(t, 2, config.Version)
+
+	config.RemoveURL("https://github.com/shared/hooks.git@v1.0.0")
+	assert.Empty(t, config.Verify)
+
+	// Without signature verification settings version 1 is stored.
+	assert.Nil(t, saveRepoSharedHooks(f.Name(), &config))
+	config, e = loadRepoSharedHooks(f.Name())
+	assert.Nil(t, e)
+	assert.Equal(t, 1, config.Version)
+}
+
+func TestSharedStatusDrift(t *testing.T) {
+	s := SharedRepoStatus{
+		State:             SharedStateActive,
+		CurrentBranch:     "main",
+		RemoteURL:         "https://github.com/shared/hooks.git",
+		ExpectedRemoteURL: "https://github.com/shared/hooks.git"}
+	assert.False(t, s.HasDrift())
+
+	s.Branch = "release"
+	assert.True(t, s.IsBranchMismatch())
+	assert.True(t, s.HasDrift())
+
+	s.Branch = ""
+	s.Behind = 2
+	assert.True(t, s.HasDrift())
+
+	s.Behind = 0
+	s.RemoteURL = "https://github.com/other/hooks.git"
+	assert.True(t, s.IsRemoteURLMismatch())
+	assert.True(t, s.HasDrift())
+
+	s.RemoteURL = s.ExpectedRemoteURL
+	s.ModifiedFiles = []string{"pre-commit/format"}
+	assert.True(t, s.HasDrift())
+}
+
+func TestSharedStatusPinnedTag(t *testing.T) {
+	dir, err := os.MkdirTemp("", "githooks-shared")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	remoteDir := path.Join(dir, "remote")
+	assert.Nil(t, git.Init(remoteDir, false))
+
+	remote := git.NewCtxAt(remoteDir)
+	commit := func(tag string) {
+		assert.Nil(t, remote.Check("-c", "user.name=test", "-c", "user.email=test@test.com",
+			"-c", "commit.gpgsign=false", "commit", "--allow-empty", "-m", "Commit"))
+		assert.Nil(t, remote.Check("tag", "-f", tag))
+	}
+	commit("v1.0.0")
+
+	repo := SharedRepo{
+		OriginalURL:   "file://" + remoteDir + "@v1.0.0",
+		URL:           "file://" + remoteDir,
+		Branch:        "v1.0.0",
+		RepositoryDir: path.Join(dir, "clone"),
+		IsCloned:      true}
+	assert.Nil(t, git.Clone(repo.RepositoryDir, repo.URL, repo.Branch, 0))
+
+	s := GetSharedRepoStatus(&repo, SharedHookTypeV.Repo, true)
+	assert.Empty(t, s.Errors)
+	assert.True(t, s.Pinned)
+	assert.True(t, s.Fetched)
+	assert.False(t, s.IsBranchMismatch())
+	assert.False(t, s.HasDrift())
+
+	// Moving the tag on the remote is drift.
+	commit("v1.0.0")
+
+	s = GetSharedRepoStatus(&repo, SharedHookTypeV.Repo, true)
+	assert.Empty(t, s.Errors)
+	assert.True(t, s.Pinned)
+	assert.Equal(t, 1, s.Behind)
+	assert.True(t, s.HasDrift())
+}
+
+func TestFindSharedGarbage(t *testing.T) {
+	installDir, err := os.MkdirTemp("", "install")
+	assert.Nil(t, err)
+	defer os.RemoveAll(installDir)
+
+	repoDir, err := os.MkdirTemp("", "repo")
+	assert.Nil(t, err)
+	defer os.RemoveAll(repoDir)
+
+	assert.Nil(t, git.Init(repoDir, false))
+	assert.Nil(t, os.MkdirAll(GetGithooksDir(repoDir), cm.DefaultFileModeDirectory))
+	assert.Nil(t, os.WriteFile(GetRepoSharedFile(repoDir),
+		[]byte("urls:\n  - https://github.com/shared/used.git\n"), cm.DefaultFileModeFile))
+	assert.Nil(t, RegisterRepo(path.Join(repoDir, ".git"), installDir, false, false))
+
+	used := GetSharedCloneDir(installDir, "https://github.com/shared/used.git")
+	unused := GetSharedCloneDir(installDir, "https://github.com/shared/unused.git")
+
+	for _, dir := range []string{used, unused} {
+		hooksDir := path.Join(dir, HooksDirName)
+		assert.Nil(t, os.MkdirAll(hooksDir, cm.DefaultFileModeDirectory))
+		assert.Nil(t, os.WriteFile(GetRepoImagesFile(hooksDir),
+			[]byte("version: 1\nimages:\n  shared-image:1.0:\n    pull:\n      reference: alpine\n  "+
+				path.Base(dir)[:8]+":1.0:\n    pull:\n      reference: alpine\n"), cm.DefaultFileModeFile))
+	}
+
+	garbage, err := FindSharedGarbage(installDir, git.NewCtxAt(repoDir), "")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{unused}, garbage.CloneDirs)
+	assert.Equal(t, []string{path.Base(unused)[:8] + ":1.0"}, garbage.Images)
+
+	assert.Nil(t, RemoveSharedGarbage(&garbage, nil))
+	assert.False(t, cm.IsDirectory(unused))
+	assert.True(t, cm.IsDirectory(used))
+}
+
+func TestInitAndValidateSharedRepo(t *testing.T) {
+	repoDir, err := os.MkdirTemp("", "shared")
+	assert.Nil(t, err)
+	defer os.RemoveAll(repoDir)
+
+	created, err := InitSharedRepo(repoDir, "my-hooks")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, created)
+	assert.True(t, cm.IsExecutable(path.Join(repoDir, "githooks/scripts/example.sh")))
+
+	ns, err := GetHooksNamespace(GetSharedGithooksDir(repoDir))
+	assert.Nil(t, err)
+	assert.Equal(t, "my-hooks", ns)
+
+	// Never overwrite existing files.
+	_, err = InitSharedRepo(repoDir, "my-hooks")
+	assert.NotNil(t, err)
+
+	hooksDir, checked, errs := ValidateSharedRepo(repoDir)
+	assert.Equal(t, path.Join(repoDir, HooksDirNameShared), hooksDir)
+	assert.Empty(t, errs)
+	assert.Contains(t, checked, path.Join(hooksDir, ".images.yaml"))
+	assert.Contains(t, checked, path.Join(hooksDir, "pre-commit", "example.yaml"))
+
+	// Report all errors at once.
+	assert.Nil(t, os.Remove(path.Join(hooksDir, "container", "Dockerfile")))
+	assert.Nil(t, os.WriteFile(path.Join(hooksDir, "pre-commit", "other.yaml"),
+		[]byte("version: 99\ncmd: ./missing.sh\n"), cm.DefaultFileModeFile))
+	assert.Nil(t, os.WriteFile(path.Join(hooksDir, ".ignore.yaml"),
+		[]byte("version: 1\npatterns: [\"[\"]\n"), cm.DefaultFileModeFile))
+
+	_, _, errs = ValidateSharedRepo(repoDir)
+	assert.Len(t, errs, 3)
+}
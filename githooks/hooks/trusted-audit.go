@@ -0,0 +1,225 @@
+package hooks
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/user"
+	"path"
+	"time"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	strs "github.com/gabyx/githooks/githooks/strings"
+)
+
+// Trust decisions recorded in the trust audit log.
+const (
+	TrustDecisionTrusted   = "trusted"
+	TrustDecisionDisabled  = "disabled"
+	TrustDecisionRevoked   = "revoked"
+	TrustDecisionDeclined  = "declined"
+	TrustDecisionForgotten = "forgotten"
+)
+
+// TrustDecisions are all trust decisions recorded in the trust audit log.
+var TrustDecisions = []string{
+	TrustDecisionTrusted,
+	TrustDecisionDisabled,
+	TrustDecisionRevoked,
+	TrustDecisionDeclined,
+	TrustDecisionForgotten}
+
+// TrustAuditEntry is an entry in the trust audit log
+// recording a change of the trust state.
+type TrustAuditEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	User      string    `json:"user"`
+
+	// The repository the trust state changed in.
+	Repository string `json:"repository"`
+
+	// The namespace path of the hook.
+	// Empty if the decision applies to all hooks of the repository (`trust-all`).
+	NamespacePath string `json:"namespacePath"`
+
+	// The SHA256 checksum of the hook. (if known)
+	Checksum string `json:"checksum,omitempty"`
+
+	// The URL of the shared repository and the description of its revision
+	// if the decision applies to all hooks of a shared repository (`trust shared`).
+	Shared   string `json:"shared,omitempty"`
+	Revision string `json:"revision,omitempty"`
+
+	// The decision, one of `trusted`, `disabled`, `revoked`, `declined` or `forgotten`.
+	Decision string `json:"decision"`
+}
+
+// Description returns a short description of the subject of this entry.
+func (e *TrustAuditEntry) Description() string {
+	if strs.IsNotEmpty(e.Shared) {
+		if strs.IsEmpty(e.Revision) {
+			return strs.Fmt("shared hooks '%s'", e.Shared)
+		}
+
+		return strs.Fmt("shared hooks '%s' at %s", e.Shared, e.Revision)
+	}
+
+	if strs.IsEmpty(e.NamespacePath) {
+		return "all hooks"
+	}
+
+	if strs.IsEmpty(e.Checksum) {
+		return strs.Fmt("'%s'", e.NamespacePath)
+	}
+
+	return strs.Fmt("'%s' [sha256: '%s']", e.NamespacePath, e.Checksum)
+}
+
+// TrustAuditFilter filters entries of the trust audit log.
+// Empty fields match everything.
+type TrustAuditFilter struct {
+	Repository    string    // Glob pattern matching the repository.
+	NamespacePath string    // Glob pattern matching the namespace path.
+	Decision      string    // The decision.
+	User          string    // The user.
+	Since         time.Time // Only entries after this time.
+}
+
+// Matches reports if the entry `e` passes this filter.
+func (f *TrustAuditFilter) Matches(e *TrustAuditEntry) bool {
+	if strs.IsNotEmpty(f.Repository) {
+		if matched, _ := cm.GlobMatch(f.Repository, e.Repository); !matched {
+			return false
+		}
+	}
+
+	if strs.IsNotEmpty(f.NamespacePath) {
+		if matched, _ := cm.GlobMatch(f.NamespacePath, e.NamespacePath); !matched {
+			return false
+		}
+	}
+
+	return (strs.IsEmpty(f.Decision) || f.Decision == e.Decision) &&
+		(strs.IsEmpty(f.User) || f.User == e.User) &&
+		(f.Since.IsZero() || !e.Timestamp.Before(f.Since))
+}
+
+// GetTrustAuditLogFile gets the trust audit log file in the install directory.
+func GetTrustAuditLogFile(installDir string) string {
+	return path.Join(installDir, "trust-audit.log")
+}
+
+func getAuditUser() string {
+	if u, err := user.Current(); err == nil && strs.IsNotEmpty(u.Username) {
+		return u.Username
+	}
+
+	if u := os.Getenv("USER"); strs.IsNotEmpty(u) {
+		return u
+	}
+
+	return os.Getenv("USERNAME")
+}
+
+// NewTrustAuditEntry creates a new trust audit entry for the current user.
+func NewTrustAuditEntry(repository string, namespacePath string, checksum string, decision string) TrustAuditEntry {
+	cm.DebugAssertF(strs.Includes(TrustDecisions, decision),
+		"Wrong trust decision '%s'.", decision)
+
+	return TrustAuditEntry{
+		Timestamp:     time.Now().UTC().Truncate(time.Second),
+		User:          getAuditUser(),
+		Repository:    repository,
+		NamespacePath: namespacePath,
+		Checksum:      checksum,
+		Decision:      decision}
+}
+
+// NewTrustAuditSharedEntry creates a new trust audit entry for the current user
+// for all hooks of the shared repository `url` at the revision `revision`.
+func NewTrustAuditSharedEntry(repository string, url string, revision string, decision string) TrustAuditEntry {
+	e := NewTrustAuditEntry(repository, "", "", decision)
+	e.Shared = url
+	e.Revision = revision
+
+	return e
+}
+
+// AppendTrustAuditEntries appends entries to the trust audit log in the install directory.
+// The log contains one JSON object per line.
+func AppendTrustAuditEntries(installDir string, entries ...TrustAuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if strs.IsEmpty(installDir) {
+		return cm.Error("No install directory to write the trust audit log to.")
+	}
+
+	var buf bytes.Buffer
+	for i := range entries {
+		data, err := json.Marshal(&entries[i])
+		if err != nil {
+			return err
+		}
+
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	file := GetTrustAuditLogFile(installDir)
+	if err := os.MkdirAll(path.Dir(file), cm.DefaultFileModeDirectory); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, cm.DefaultFileModeFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Write everything at once to not interleave with concurrent writers.
+	_, err = f.Write(buf.Bytes())
+
+	return err
+}
+
+// LoadTrustAuditEntries loads all entries from the trust audit log in
+// the install directory which match the filter `filter`.
+func LoadTrustAuditEntries(installDir string, filter *TrustAuditFilter) (entries []TrustAuditEntry, err error) {
+	file := GetTrustAuditLogFile(installDir)
+	if !cm.IsFile(file) {
+		return
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	lineNr := 0
+	for scanner.Scan() {
+		lineNr++
+
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var entry TrustAuditEntry
+		if e := json.Unmarshal(line, &entry); e != nil {
+			err = cm.CombineErrors(err, cm.ErrorF("Could not parse line '%v' in trust audit log '%s'.", lineNr, file))
+
+			continue
+		}
+
+		if filter == nil || filter.Matches(&entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, cm.CombineErrors(err, scanner.Err())
+}
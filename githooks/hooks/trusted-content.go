@@ -0,0 +1,192 @@
+package hooks
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	strs "github.com/gabyx/githooks/githooks/strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Trusted contents larger than this are not kept.
+const maxTrustedContentSize = 1 << 20 // 1 MiB
+
+// HookChange describes the change of a hook since it was last trusted.
+type HookChange struct {
+	// The SHA256 checksum of the last trusted content.
+	// Empty if the hook was never trusted before.
+	PreviousChecksum string
+
+	// The unified diff from the last trusted content to the current one.
+	Diff string
+
+	// The image references of a hook run configuration
+	// in the last trusted and the current content.
+	PreviousImage string
+	Image         string
+}
+
+// IsNew reports if no previously trusted content of the hook is known.
+func (c *HookChange) IsNew() bool {
+	return strs.IsEmpty(c.PreviousChecksum)
+}
+
+// IsImageChanged reports if the image reference of the hook run configuration changed.
+func (c *HookChange) IsImageChanged() bool {
+	return !c.IsNew() && c.PreviousImage != c.Image
+}
+
+// getContentDir gets the directory which keeps the copy of the last trusted
+// content of the file `filePath`. Copies are indexed by the hook path.
+func (t *ChecksumStore) getContentDir(filePath string) string {
+	h, _ := cm.GetSHA256Hash(strings.NewReader(filepath.ToSlash(filePath)))
+
+	return path.Join(t.checksumDir, ".contents", h[0:2], h[2:])
+}
+
+func (t *ChecksumStore) getContentFile(filePath string, checksum string) string {
+	return path.Join(t.getContentDir(filePath), checksum)
+}
+
+// storeContent keeps a copy of the trusted content of `filePath` with
+// SHA256 checksum `checksum`. Non-existing or too large files are skipped.
+// The copy of a previously trusted content of `filePath` is removed.
+func (t *ChecksumStore) storeContent(checksum string, filePath string) error {
+	info, err := os.Stat(filePath)
+	if err != nil || info.IsDir() || info.Size() > maxTrustedContentSize {
+		return nil //nolint: nilerr
+	}
+
+	file := t.getContentFile(filePath, checksum)
+	if !cm.IsFile(file) {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(path.Dir(file), cm.DefaultFileModeDirectory); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(file, data, cm.DefaultFileModeFile); err != nil {
+			return err
+		}
+	}
+
+	// Remove superseded copies.
+	entries, err := os.ReadDir(path.Dir(file))
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Name() == checksum {
+			continue
+		}
+
+		if err := os.RemoveAll(path.Join(path.Dir(file), entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeContent removes the copy of the trusted content of `filePath` with checksum `checksum`.
+func (t *ChecksumStore) removeContent(checksum string, filePath string) error {
+	file := t.getContentFile(filePath, checksum)
+	if !cm.IsFile(file) {
+		return nil
+	}
+
+	if err := os.Remove(file); err != nil {
+		return err
+	}
+
+	// Remove the empty directory.
+	_ = os.Remove(path.Dir(file))
+
+	return nil
+}
+
+// getLastTrustedContent gets the last trusted content of the file `filePath`
+// and its SHA256 checksum.
+func (t *ChecksumStore) getLastTrustedContent(filePath string) (checksum string, content string, err error) {
+	if strs.IsEmpty(t.checksumDir) {
+		return
+	}
+
+	dir := t.getContentDir(filePath)
+	if !cm.IsDirectory(dir) {
+		return
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || len(entry.Name()) != SHA256Length {
+			continue
+		}
+
+		// The content is only considered if the checksum is still trusted.
+		if !cm.IsFile(t.getChecksumFile(entry.Name())) {
+			continue
+		}
+
+		checksum = entry.Name()
+	}
+
+	if strs.IsEmpty(checksum) {
+		return
+	}
+
+	data, err := os.ReadFile(t.getContentFile(filePath, checksum))
+
+	return checksum, string(data), err
+}
+
+// GetHookChange gets the change of the hook `filePath` since its content was last trusted.
+func (t *ChecksumStore) GetHookChange(filePath string) (change HookChange, err error) {
+	filePath = filepath.ToSlash(filePath)
+
+	checksum, previous, err := t.getLastTrustedContent(filePath)
+	if err != nil || strs.IsEmpty(checksum) {
+		return
+	}
+
+	current, err := os.ReadFile(filePath)
+	if err != nil {
+		return
+	}
+
+	change.PreviousChecksum = checksum
+	change.Diff, err = difflib.GetUnifiedDiffString(
+		difflib.UnifiedDiff{
+			A:        difflib.SplitLines(previous),
+			B:        difflib.SplitLines(string(current)),
+			FromFile: "trusted",
+			ToFile:   "current",
+			Context:  3}) // nolint: gomnd
+
+	if err != nil || path.Ext(filePath) != ".yaml" {
+		return
+	}
+
+	// Report the image references of hook run configurations.
+	if config, e := loadRunnerConfig(t.getContentFile(filePath, checksum)); e == nil {
+		change.PreviousImage = config.Image.Reference
+	}
+
+	if config, e := loadRunnerConfig(filePath); e == nil {
+		change.Image = config.Image.Reference
+	}
+
+	return
+}
@@ -0,0 +1,235 @@
+package hooks
+
+import (
+	"os"
+	"path"
+	"strings"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
+	strs "github.com/gabyx/githooks/githooks/strings"
+)
+
+// TrustedRevision is a trust record for all hooks of a shared repository
+// at a specific revision.
+type TrustedRevision struct {
+	// The original URL of the shared repository.
+	URL string `yaml:"url"`
+
+	// The trusted commit SHA.
+	Commit string `yaml:"commit,omitempty"`
+
+	// Any commit signed by one of these keys is trusted.
+	// GPG key ids or fingerprints or SSH key fingerprints or principals.
+	SignedBy []string `yaml:"signedBy,omitempty"`
+}
+
+// Description returns a short description of this trust record.
+func (r *TrustedRevision) Description() string {
+	if strs.IsNotEmpty(r.Commit) {
+		return strs.Fmt("commit '%s'", r.Commit)
+	}
+
+	return strs.Fmt("signed by '%q'", r.SignedBy)
+}
+
+// trustedRevisionsFile is the format of the file containing all
+// trusted revisions of shared repositories.
+type trustedRevisionsFile struct {
+	Revisions []TrustedRevision `yaml:"revisions"`
+
+	// The version of the file.
+	Version int `yaml:"version"`
+}
+
+// Version for trustedRevisionsFile.
+// Version 1: Initial.
+const trustedRevisionsFileVersion int = 1
+
+func (t *ChecksumStore) getTrustedRevisionsFile() string {
+	return path.Join(t.checksumDir, ".shared-revisions.yaml")
+}
+
+// LoadTrustedRevisions loads all trusted revisions of shared repositories
+// from the search directory.
+func (t *ChecksumStore) LoadTrustedRevisions() (revisions []TrustedRevision, err error) {
+	file := t.getTrustedRevisionsFile()
+	if strs.IsEmpty(t.checksumDir) || !cm.IsFile(file) {
+		return
+	}
+
+	data := trustedRevisionsFile{Version: trustedRevisionsFileVersion}
+	if err = cm.LoadYAML(file, &data); err != nil {
+		return nil, cm.CombineErrors(cm.ErrorF("Could not load file '%s'", file), err)
+	}
+
+	if data.Version < 1 || data.Version > trustedRevisionsFileVersion {
+		return nil, cm.ErrorF(
+			"File '%s' has version '%v'. "+
+				"This version of Githooks only supports version >= 1 and <= '%v'.",
+			file, data.Version, trustedRevisionsFileVersion)
+	}
+
+	return data.Revisions, nil
+}
+
+func (t *ChecksumStore) storeTrustedRevisions(revisions []TrustedRevision) error {
+	if strs.IsEmpty(t.checksumDir) {
+		return cm.Error("No checksum directory.")
+	}
+
+	file := t.getTrustedRevisionsFile()
+
+	if len(revisions) == 0 {
+		if cm.IsFile(file) {
+			return os.Remove(file)
+		}
+
+		return nil
+	}
+
+	if err := os.MkdirAll(t.checksumDir, cm.DefaultFileModeDirectory); err != nil {
+		return err
+	}
+
+	return cm.StoreYAML(file,
+		&trustedRevisionsFile{Revisions: revisions, Version: trustedRevisionsFileVersion})
+}
+
+// SyncTrustedRevisionAdd adds trusted revisions of shared repositories to the search directory.
+func (t *ChecksumStore) SyncTrustedRevisionAdd(revisions ...TrustedRevision) error {
+	existing, err := t.LoadTrustedRevisions()
+	if err != nil {
+		return err
+	}
+
+	for i := range revisions {
+		rev := &revisions[i]
+		cm.DebugAssertF(strs.IsNotEmpty(rev.Commit) || len(rev.SignedBy) != 0,
+			"Wrong trusted revision for '%s'", rev.URL)
+
+		isEqual := func(r *TrustedRevision) bool {
+			return r.URL == rev.URL && r.Commit == rev.Commit &&
+				strings.Join(r.SignedBy, ",") == strings.Join(rev.SignedBy, ",")
+		}
+
+		found := false
+		for j := range existing {
+			if isEqual(&existing[j]) {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			existing = append(existing, *rev)
+		}
+	}
+
+	return t.storeTrustedRevisions(existing)
+}
+
+// SyncTrustedRevisionRemove removes all trusted revisions of the shared repository
+// with URL `url` from the search directory.
+func (t *ChecksumStore) SyncTrustedRevisionRemove(url string) (removed int, err error) {
+	existing, err := t.LoadTrustedRevisions()
+	if err != nil {
+		return
+	}
+
+	kept := make([]TrustedRevision, 0, len(existing))
+	for i := range existing {
+		if existing[i].URL == url {
+			removed++
+		} else {
+			kept = append(kept, existing[i])
+		}
+	}
+
+	if removed == 0 {
+		return
+	}
+
+	return removed, t.storeTrustedRevisions(kept)
+}
+
+// GetTrustedRevisions gets all trusted revisions for the shared repository with URL `url`.
+func (t *ChecksumStore) GetTrustedRevisions(url string) (revisions []TrustedRevision, err error) {
+	all, err := t.LoadTrustedRevisions()
+	if err != nil {
+		return
+	}
+
+	for i := range all {
+		if all[i].URL == url {
+			revisions = append(revisions, all[i])
+		}
+	}
+
+	return
+}
+
+// IsRevisionTrusted checks if the checked out revision of the shared repository `repo`
+// has been trusted, either by its commit or by a signature of a trusted key.
+// A revision is never trusted if the shared repository has local modifications.
+// Reports the matching trust record.
+func (t *ChecksumStore) IsRevisionTrusted(repo *SharedRepo) (trusted bool, rev TrustedRevision, err error) {
+	revisions, err := t.GetTrustedRevisions(repo.OriginalURL)
+	if err != nil || len(revisions) == 0 || !cm.IsDirectory(repo.RepositoryDir) {
+		return
+	}
+
+	gitx := git.NewCtxSanitizedAt(repo.RepositoryDir)
+	if !gitx.IsGitRepo() {
+		return
+	}
+
+	commit, modified, err := GetSharedRevision(repo)
+	if err != nil || len(modified) != 0 {
+		return
+	}
+
+	var keys []string
+	var keysLoaded bool
+
+	for i := range revisions {
+		r := &revisions[i]
+
+		if strs.IsNotEmpty(r.Commit) {
+			if r.Commit == commit {
+				return true, *r, nil
+			}
+
+			continue
+		}
+
+		if !keysLoaded {
+			keysLoaded = true
+			// An unsigned commit is not an error.
+			keys, _ = gitx.VerifyCommit(commit)
+		}
+
+		verify := SharedRepoVerify{Signature: SharedSignatureCommit, AllowedKeys: r.SignedBy}
+		if verify.IsKeyAllowed(keys) {
+			return true, *r, nil
+		}
+	}
+
+	return
+}
+
+// GetSharedRevision gets the checked out commit of the shared repository `repo`
+// and all files with local modifications.
+func GetSharedRevision(repo *SharedRepo) (commit string, modified []string, err error) {
+	gitx := git.NewCtxSanitizedAt(repo.RepositoryDir)
+
+	commit, err = git.GetCommitSHA(gitx, git.HEAD)
+	if err != nil {
+		return
+	}
+
+	modified, err = gitx.GetModifiedFiles()
+
+	return
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"time"
 
 	cm "github.com/gabyx/githooks/githooks/common"
 	"github.com/gabyx/githooks/githooks/git"
@@ -79,21 +80,83 @@ func SkipUntrustedHooks(gitx *git.Context, scope git.ConfigScope) (enabled bool,
 	}
 }
 
+// SetTrustSharedRevisions sets the settings if the hook runner should
+// prompt to trust the whole revision of a shared repository instead of each hook.
+func SetTrustSharedRevisions(gitx *git.Context, enable bool, reset bool, scope git.ConfigScope) error {
+	switch {
+	case reset:
+		return gitx.UnsetConfig(GitCKTrustSharedRevisions, scope)
+	default:
+		return gitx.SetConfig(GitCKTrustSharedRevisions, enable, scope)
+	}
+}
+
+// IsTrustSharedRevisions gets the settings if the hook runner should
+// prompt to trust the whole revision of a shared repository instead of each hook.
+func IsTrustSharedRevisions(gitx *git.Context, scope git.ConfigScope) bool {
+	return gitx.GetConfig(GitCKTrustSharedRevisions, scope) == git.GitCVTrue
+}
+
+// SetTrustExpiry sets the duration after which trusted hooks expire.
+func SetTrustExpiry(gitx *git.Context, expiry time.Duration, reset bool, scope git.ConfigScope) error {
+	switch {
+	case reset:
+		return gitx.UnsetConfig(GitCKTrustExpiry, scope)
+	default:
+		return gitx.SetConfig(GitCKTrustExpiry, expiry.String(), scope)
+	}
+}
+
+// GetTrustExpiry gets the duration after which trusted hooks expire.
+// Zero means trusted hooks never expire.
+func GetTrustExpiry(gitx *git.Context, scope git.ConfigScope) (time.Duration, error) {
+	conf := gitx.GetConfig(GitCKTrustExpiry, scope)
+	if strs.IsEmpty(conf) {
+		return 0, nil
+	}
+
+	expiry, err := time.ParseDuration(conf)
+	if err != nil || expiry < 0 {
+		return 0, cm.ErrorF("Config value '%s' for '%s' is not a valid duration.", conf, GitCKTrustExpiry)
+	}
+
+	return expiry, nil
+}
+
+// GetEffectiveTrustExpiry gets the duration after which trusted hooks expire
+// which is the shorter one of the Git config and the trust policy `policy`.
+func GetEffectiveTrustExpiry(gitx *git.Context, policy *TrustPolicy) (time.Duration, error) {
+	expiry, err := GetTrustExpiry(gitx, git.Traverse)
+	if err != nil {
+		return 0, err
+	}
+
+	if p := policy.GetTrustExpiry(); p > 0 && (expiry <= 0 || p < expiry) {
+		expiry = p
+	}
+
+	return expiry, nil
+}
+
 const (
-	// SHA1Length is the string length of a SHA1 hash.
+	// SHA1Length is the string length of a legacy SHA1 hash.
 	SHA1Length = 40
+
+	// SHA256Length is the string length of a SHA256 hash.
+	SHA256Length = 64
 )
 
-// ChecksumResult defines the SHA1 hash and the path it was computed with together with the
+// ChecksumResult defines the SHA256 hash and the path it was computed with together with the
 // namespaced path.
 type ChecksumResult struct {
-	SHA1          string // SHA1 hash.
+	Checksum      string // SHA256 hash.
 	Path          string // Path.
 	NamespacePath string // Namespaced path.
 }
 
 // ChecksumStore represents a set of checksum which
 // can be consulted to check if a hook is trusted or not.
+// Checksums are SHA256 hashes. Legacy SHA1 hashes are still read.
 type ChecksumStore struct {
 	// checksumDir is the path to the checksum directories containing files
 	// with file name equal to the checksum.
@@ -101,6 +164,10 @@ type ChecksumStore struct {
 
 	// Checksums are the checksums manually added to this store
 	checksums map[string]ChecksumData
+
+	// expiry is the duration after which trusted checksums in the
+	// search directory expire. Zero means no expiry.
+	expiry time.Duration
 }
 
 // ChecksumData represents the data for one checksum which was stored.
@@ -110,57 +177,100 @@ type ChecksumData struct {
 
 type checksumFile struct {
 	Path string
+
+	// The time the checksum was trusted.
+	Trusted time.Time `yaml:"trusted,omitempty"`
+
+	// The time the trust expires. (optional)
+	Expires time.Time `yaml:"expires,omitempty"`
 }
 
 func newChecksumData(paths ...string) ChecksumData {
 	return ChecksumData{paths}
 }
 
+// GetChecksum gets the checksum (SHA256 hash) of a file used in the checksum store.
+func GetChecksum(filePath string) (string, error) {
+	return cm.GetSHA256HashFile(filePath)
+}
+
 // AddChecksums sets the search directory to `path`.
 func (t *ChecksumStore) SetSearchDirectory(path string) {
 	t.checksumDir = path
 }
 
+// SetExpiry sets the duration after which trusted checksums expire.
+// Zero means trusted checksums never expire.
+func (t *ChecksumStore) SetExpiry(expiry time.Duration) {
+	t.expiry = expiry
+}
+
 func (t *ChecksumStore) assertData() {
 	if t.checksums == nil {
 		t.checksums = make(map[string]ChecksumData)
 	}
 }
 
-// AddChecksum adds a SHA1 checksum of a path and returns if it was added (or it existed already).
-func (t *ChecksumStore) AddChecksum(sha1 string, filePath string) bool {
+func (t *ChecksumStore) getChecksumFile(checksum string) string {
+	cm.DebugAssertF(len(checksum) == SHA256Length || len(checksum) == SHA1Length,
+		"Wrong checksum '%s'", checksum)
+
+	return path.Join(t.checksumDir, checksum[0:2], checksum[2:])
+}
+
+// AddChecksum adds a SHA256 checksum of a path and returns if it was added (or it existed already).
+func (t *ChecksumStore) AddChecksum(checksum string, filePath string) bool {
 	t.assertData()
 	filePath = filepath.ToSlash(filePath)
-	if data, exists := t.checksums[sha1]; exists {
+	if data, exists := t.checksums[checksum]; exists {
 		p := &data.Paths
 		*p = append(*p, filePath)
 
 		return true
 	}
 
-	t.checksums[sha1] = newChecksumData(filePath)
+	t.checksums[checksum] = newChecksumData(filePath)
 
 	return false
 }
 
-// SyncChecksumAdd adds SHA1 checksums of a path to the search directory.
+// SyncChecksumAdd adds SHA256 checksums of a path to the search directory.
 func (t *ChecksumStore) SyncChecksumAdd(checksums ...ChecksumResult) error {
+	return t.SyncChecksumAddWithExpiry(0, checksums...)
+}
+
+// SyncChecksumAddWithExpiry adds SHA256 checksums of a path to the search directory
+// which expire after `expiry`. Zero means the checksums only expire by
+// the expiry of the store.
+func (t *ChecksumStore) SyncChecksumAddWithExpiry(expiry time.Duration, checksums ...ChecksumResult) error {
 	if strs.IsEmpty(t.checksumDir) {
 		return cm.Error("No checksum directory.")
 	}
 
+	now := time.Now().UTC().Truncate(time.Second)
+	var expires time.Time
+	if expiry > 0 {
+		expires = now.Add(expiry)
+	}
+
 	for i := range checksums {
 		checksum := &checksums[i]
 
-		cm.DebugAssertF(len(checksum.SHA1) == 40, "Wrong SHA1 hash '%s'", checksum.SHA1) // nolint:gomnd
+		cm.DebugAssertF(len(checksum.Checksum) == SHA256Length, "Wrong SHA256 hash '%s'", checksum.Checksum)
 
-		dir := path.Join(t.checksumDir, checksum.SHA1[0:2])
-		err := os.MkdirAll(dir, cm.DefaultFileModeDirectory)
+		file := t.getChecksumFile(checksum.Checksum)
+		err := os.MkdirAll(path.Dir(file), cm.DefaultFileModeDirectory)
 		if err != nil {
 			return err
 		}
 
-		err = cm.StoreYAML(path.Join(dir, checksum.SHA1[2:]), &checksumFile{checksum.Path})
+		err = cm.StoreYAML(file, &checksumFile{Path: checksum.Path, Trusted: now, Expires: expires})
+		if err != nil {
+			return err
+		}
+
+		// Keep the trusted content to show changes later.
+		err = t.storeContent(checksum.Checksum, checksum.Path)
 		if err != nil {
 			return err
 		}
@@ -169,9 +279,9 @@ func (t *ChecksumStore) SyncChecksumAdd(checksums ...ChecksumResult) error {
 	return nil
 }
 
-// SyncChecksumRemove removes SHA1 checksums
+// SyncChecksumRemove removes checksums (SHA256 or legacy SHA1)
 // of a path from the search directory.
-func (t *ChecksumStore) SyncChecksumRemove(sha1s ...string) (removed int, err error) {
+func (t *ChecksumStore) SyncChecksumRemove(checksums ...string) (removed int, err error) {
 
 	if strs.IsEmpty(t.checksumDir) {
 		err = cm.Error("No checksum directory.")
@@ -179,52 +289,224 @@ func (t *ChecksumStore) SyncChecksumRemove(sha1s ...string) (removed int, err er
 		return
 	}
 
-	for _, sha1 := range sha1s {
-
-		cm.DebugAssertF(len(sha1) == 40, "Wrong SHA1 hash '%s'", sha1) // nolint:gomnd
-
-		dir := path.Join(t.checksumDir, sha1[0:2])
-		file := path.Join(dir, sha1[2:])
+	for _, checksum := range checksums {
+		file := t.getChecksumFile(checksum)
+		if !cm.IsFile(file) {
+			continue
+		}
 
-		if cm.IsFile(file) {
-			if err = os.Remove(file); err != nil {
+		// Remove the trusted content of the path first.
+		var data checksumFile
+		if e := cm.LoadYAML(file, &data); e == nil && strs.IsNotEmpty(data.Path) {
+			if err = t.removeContent(checksum, data.Path); err != nil {
 				return
 			}
+		}
 
-			removed++
+		if err = os.Remove(file); err != nil {
+			return
 		}
+
+		removed++
 	}
 
 	return
 }
 
+// SyncChecksumRemoveFile removes the SHA256 and the legacy SHA1 checksum
+// of the file `filePath` from the search directory.
+func (t *ChecksumStore) SyncChecksumRemoveFile(filePath string) (removed int, err error) {
+	checksum, err := GetChecksum(filePath)
+	if err != nil {
+		return
+	}
+
+	legacy, err := cm.GetSHA1HashFile(filePath)
+	if err != nil {
+		return
+	}
+
+	return t.SyncChecksumRemove(checksum, legacy)
+}
+
+// getExpiryTime gets the time the checksum in `file` expires.
+// Returns zero if it never expires.
+func (t *ChecksumStore) getExpiryTime(file string) (expires time.Time, err error) {
+	var data checksumFile
+	if err = cm.LoadYAML(file, &data); err != nil {
+		return
+	}
+
+	expires = data.Expires
+
+	if t.expiry <= 0 {
+		return
+	}
+
+	// Legacy entries have no trusted time.
+	trusted := data.Trusted
+	if trusted.IsZero() {
+		info, e := os.Stat(file)
+		if e != nil {
+			return expires, e
+		}
+
+		trusted = info.ModTime()
+	}
+
+	if e := trusted.Add(t.expiry); expires.IsZero() || e.Before(expires) {
+		expires = e
+	}
+
+	return
+}
+
+// IsExpired reports if the checksum `checksum` in the search directory
+// has been trusted but its trust has expired.
+func (t *ChecksumStore) IsExpired(checksum string) (bool, error) {
+	if strs.IsEmpty(t.checksumDir) || strs.IsEmpty(checksum) {
+		return false, nil
+	}
+
+	file := t.getChecksumFile(checksum)
+	if !cm.IsFile(file) {
+		return false, nil
+	}
+
+	expires, err := t.getExpiryTime(file)
+	if err != nil {
+		return false, err
+	}
+
+	return !expires.IsZero() && !time.Now().Before(expires), nil
+}
+
+func (t *ChecksumStore) isChecksumTrusted(checksum string) (bool, error) {
+	// Check first search directory ...
+	if strs.IsNotEmpty(t.checksumDir) {
+		exists, err := cm.IsPathExisting(t.getChecksumFile(checksum))
+		if err != nil {
+			return false, err
+		} else if exists {
+			expired, err := t.IsExpired(checksum)
+			if !expired || err != nil {
+				return !expired, err
+			}
+		}
+	}
+
+	// Check all checksums ...
+	_, ok := t.checksums[checksum]
+
+	return ok, nil
+}
+
 // IsTrusted checks if a path has been trusted.
+// It reports the SHA256 checksum of the path.
+// Legacy SHA1 checksums are still considered trusted.
 func (t *ChecksumStore) IsTrusted(filePath string) (bool, string, error) {
 
-	sha1, err := cm.GetSHA1HashFile(filePath)
+	checksum, err := GetChecksum(filePath)
 	if err != nil {
 		return false, "",
 			cm.CombineErrors(cm.ErrorF("Could not get hash for '%s'", filePath), err)
 	}
 
-	// Check first search directory ...
-	if strs.IsNotEmpty(t.checksumDir) {
-		bucket := sha1[0:2]
-		exists, err := cm.IsPathExisting(path.Join(t.checksumDir, bucket, sha1[2:]))
-		if exists {
-			return true, sha1, nil
-		} else if err != nil {
-			return false, sha1, err
+	if trusted, err := t.isChecksumTrusted(checksum); trusted || err != nil {
+		return trusted, checksum, err
+	}
+
+	legacy, err := cm.GetSHA1HashFile(filePath)
+	if err != nil {
+		return false, checksum,
+			cm.CombineErrors(cm.ErrorF("Could not get hash for '%s'", filePath), err)
+	}
+
+	trusted, err := t.isChecksumTrusted(legacy)
+
+	return trusted, checksum, err
+}
+
+// MigrateChecksums migrates all legacy SHA1 checksums in the search directory to
+// SHA256 checksums. Only entries whose files still exist with unchanged content
+// are migrated, all others are kept. Returns the number of migrated and kept entries.
+func (t *ChecksumStore) MigrateChecksums() (migrated int, kept int, err error) {
+	if strs.IsEmpty(t.checksumDir) || !cm.IsDirectory(t.checksumDir) {
+		return
+	}
+
+	buckets, err := os.ReadDir(t.checksumDir)
+	if err != nil {
+		return
+	}
+
+	for _, bucket := range buckets {
+		if !bucket.IsDir() || len(bucket.Name()) != 2 { // nolint: gomnd
+			continue
+		}
+
+		entries, e := os.ReadDir(path.Join(t.checksumDir, bucket.Name()))
+		if e != nil {
+			err = cm.CombineErrors(err, e)
+
+			continue
+		}
+
+		for _, entry := range entries {
+			legacy := bucket.Name() + entry.Name()
+			if entry.IsDir() || len(legacy) != SHA1Length {
+				continue
+			}
+
+			if ok, e := t.migrateChecksum(legacy); ok {
+				migrated++
+			} else {
+				err = cm.CombineErrors(err, e)
+				kept++
+			}
 		}
 	}
 
-	// Check all checksums ...
-	_, ok := t.checksums[sha1]
-	if ok {
-		return true, sha1, nil
+	return
+}
+
+// migrateChecksum migrates the legacy SHA1 checksum `legacy`.
+// Entries whose files do not exist anymore or have changed are kept.
+func (t *ChecksumStore) migrateChecksum(legacy string) (migrated bool, err error) {
+	file := t.getChecksumFile(legacy)
+
+	var data checksumFile
+	if err = cm.LoadYAML(file, &data); err != nil {
+		return
+	}
+
+	if !cm.IsFile(data.Path) {
+		return
+	}
+
+	if sha1, e := cm.GetSHA1HashFile(data.Path); e != nil || sha1 != legacy {
+		return false, e
+	}
+
+	checksum, err := GetChecksum(data.Path)
+	if err != nil {
+		return
+	}
+
+	if err = t.SyncChecksumAdd(ChecksumResult{Checksum: checksum, Path: data.Path}); err != nil {
+		return
+	}
+
+	// Keep the time the legacy checksum was trusted.
+	if info, e := os.Stat(file); e == nil {
+		err = cm.StoreYAML(t.getChecksumFile(checksum),
+			&checksumFile{Path: data.Path, Trusted: info.ModTime().UTC().Truncate(time.Second)})
+		if err != nil {
+			return
+		}
 	}
 
-	return false, sha1, nil
+	return true, os.Remove(file)
 }
 
 // Summary returns a summary of the checksum store.
@@ -0,0 +1,295 @@
+package hooks
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksumStoreMigration(t *testing.T) {
+	dir, err := os.MkdirTemp("", "githooks-trust")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	hookA := path.Join(dir, "hook-a.sh")
+	hookB := path.Join(dir, "hook-b.sh")
+	assert.Nil(t, os.WriteFile(hookA, []byte("echo a"), cm.DefaultFileModeFile))
+	assert.Nil(t, os.WriteFile(hookB, []byte("echo b"), cm.DefaultFileModeFile))
+
+	store, err := GetChecksumStorage(path.Join(dir, "git"))
+	assert.Nil(t, err)
+
+	// Add legacy SHA1 entries.
+	for _, hook := range []string{hookA, hookB} {
+		sha1, err := cm.GetSHA1HashFile(hook)
+		assert.Nil(t, err)
+		file := path.Join(GetChecksumDirectoryGitDir(path.Join(dir, "git")), sha1[0:2], sha1[2:])
+		assert.Nil(t, os.MkdirAll(path.Dir(file), cm.DefaultFileModeDirectory))
+		assert.Nil(t, cm.StoreYAML(file, &checksumFile{Path: hook}))
+	}
+
+	trusted, checksum, err := store.IsTrusted(hookA)
+	assert.Nil(t, err)
+	assert.True(t, trusted)
+	assert.Equal(t, SHA256Length, len(checksum))
+
+	// Changed files are not migrated.
+	assert.Nil(t, os.WriteFile(hookB, []byte("echo changed"), cm.DefaultFileModeFile))
+
+	migrated, kept, err := store.MigrateChecksums()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, migrated)
+	assert.Equal(t, 1, kept)
+
+	assert.True(t, cm.IsFile(store.getChecksumFile(checksum)))
+
+	sha1, err := cm.GetSHA1HashFile(hookA)
+	assert.Nil(t, err)
+	assert.False(t, cm.IsFile(store.getChecksumFile(sha1)))
+
+	trusted, _, err = store.IsTrusted(hookA)
+	assert.Nil(t, err)
+	assert.True(t, trusted)
+
+	trusted, _, err = store.IsTrusted(hookB)
+	assert.Nil(t, err)
+	assert.False(t, trusted)
+}
+
+func TestTrustedRevisions(t *testing.T) {
+	dir, err := os.MkdirTemp("", "githooks-trust")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	repoDir := path.Join(dir, "shared")
+	assert.Nil(t, git.Init(repoDir, false))
+	assert.Nil(t, os.WriteFile(path.Join(repoDir, "hook.sh"), []byte("echo a"), cm.DefaultFileModeFile))
+
+	gitx := git.NewCtxAt(repoDir)
+	assert.Nil(t, gitx.Check("add", "."))
+	assert.Nil(t, gitx.Check("-c", "user.name=test", "-c", "user.email=test@test.com",
+		"-c", "commit.gpgsign=false", "commit", "-m", "Init"))
+
+	commit, err := git.GetCommitSHA(gitx, git.HEAD)
+	assert.Nil(t, err)
+
+	store, err := GetChecksumStorage(path.Join(dir, "git"))
+	assert.Nil(t, err)
+
+	repo := SharedRepo{OriginalURL: "https://shared.git", RepositoryDir: repoDir, IsCloned: true}
+
+	trusted, _, err := store.IsRevisionTrusted(&repo)
+	assert.Nil(t, err)
+	assert.False(t, trusted)
+
+	assert.Nil(t, store.SyncTrustedRevisionAdd(
+		TrustedRevision{URL: repo.OriginalURL, Commit: commit},
+		TrustedRevision{URL: repo.OriginalURL, Commit: commit}))
+
+	revs, err := store.LoadTrustedRevisions()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(revs))
+
+	trusted, rev, err := store.IsRevisionTrusted(&repo)
+	assert.Nil(t, err)
+	assert.True(t, trusted)
+	assert.Equal(t, commit, rev.Commit)
+
+	// Local modifications are never trusted.
+	assert.Nil(t, os.WriteFile(path.Join(repoDir, "hook.sh"), []byte("echo b"), cm.DefaultFileModeFile))
+	trusted, _, err = store.IsRevisionTrusted(&repo)
+	assert.Nil(t, err)
+	assert.False(t, trusted)
+
+	assert.Nil(t, gitx.Check("checkout", "hook.sh"))
+
+	// Unsigned commits are not trusted by keys.
+	removed, err := store.SyncTrustedRevisionRemove(repo.OriginalURL)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, removed)
+
+	assert.Nil(t, store.SyncTrustedRevisionAdd(
+		TrustedRevision{URL: repo.OriginalURL, SignedBy: []string{"0123456789ABCDEF"}}))
+	trusted, _, err = store.IsRevisionTrusted(&repo)
+	assert.Nil(t, err)
+	assert.False(t, trusted)
+}
+
+func TestHookChange(t *testing.T) {
+	dir, err := os.MkdirTemp("", "githooks-trust")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := GetChecksumStorage(path.Join(dir, "git"))
+	assert.Nil(t, err)
+
+	hook := path.Join(dir, "hook.yaml")
+	assert.Nil(t, os.WriteFile(hook,
+		[]byte("version: 3\ncmd: ./run.sh\nimage:\n  reference: lint:1.0\n"), cm.DefaultFileModeFile))
+
+	change, err := store.GetHookChange(hook)
+	assert.Nil(t, err)
+	assert.True(t, change.IsNew())
+
+	checksum, err := GetChecksum(hook)
+	assert.Nil(t, err)
+	assert.Nil(t, store.SyncChecksumAdd(ChecksumResult{Checksum: checksum, Path: hook}))
+
+	assert.Nil(t, os.WriteFile(hook,
+		[]byte("version: 3\ncmd: ./run.sh\nimage:\n  reference: lint:2.0\n"), cm.DefaultFileModeFile))
+
+	change, err = store.GetHookChange(hook)
+	assert.Nil(t, err)
+	assert.False(t, change.IsNew())
+	assert.Equal(t, checksum, change.PreviousChecksum)
+	assert.Contains(t, change.Diff, "-  reference: lint:1.0")
+	assert.Contains(t, change.Diff, "+  reference: lint:2.0")
+	assert.True(t, change.IsImageChanged())
+	assert.Equal(t, "lint:1.0", change.PreviousImage)
+	assert.Equal(t, "lint:2.0", change.Image)
+
+	// Trusting the new content removes the superseded copy.
+	checksum2, err := GetChecksum(hook)
+	assert.Nil(t, err)
+	assert.Nil(t, store.SyncChecksumAdd(ChecksumResult{Checksum: checksum2, Path: hook}))
+
+	copies, err := os.ReadDir(store.getContentDir(hook))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(copies))
+	assert.Equal(t, checksum2, copies[0].Name())
+
+	assert.Nil(t, os.WriteFile(hook,
+		[]byte("version: 3\ncmd: ./run.sh\nimage:\n  reference: lint:3.0\n"), cm.DefaultFileModeFile))
+
+	change, err = store.GetHookChange(hook)
+	assert.Nil(t, err)
+	assert.Equal(t, checksum2, change.PreviousChecksum)
+	assert.Equal(t, "lint:2.0", change.PreviousImage)
+
+	// Removing the checksum also removes the trusted content.
+	_, err = store.SyncChecksumRemove(checksum2)
+	assert.Nil(t, err)
+	change, err = store.GetHookChange(hook)
+	assert.Nil(t, err)
+	assert.True(t, change.IsNew())
+	assert.False(t, cm.IsDirectory(store.getContentDir(hook)))
+}
+
+func TestTrustAuditLog(t *testing.T) {
+	dir, err := os.MkdirTemp("", "githooks-audit")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	entries, err := LoadTrustAuditEntries(dir, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+
+	assert.Nil(t, AppendTrustAuditEntries(dir,
+		NewTrustAuditEntry("/repo-a", "ns:a/pre-commit/lint", "1234", TrustDecisionTrusted),
+		NewTrustAuditEntry("/repo-a", "ns:a/pre-commit/format", "5678", TrustDecisionDisabled)))
+	assert.Nil(t, AppendTrustAuditEntries(dir,
+		NewTrustAuditEntry("/repo-b", "", "", TrustDecisionRevoked),
+		NewTrustAuditSharedEntry("/repo-b", "https://a.com/hooks.git", "commit '1234'", TrustDecisionTrusted)))
+
+	entries, err = LoadTrustAuditEntries(dir, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(entries))
+	assert.Equal(t, "ns:a/pre-commit/lint", entries[0].NamespacePath)
+	assert.Equal(t, "1234", entries[0].Checksum)
+	assert.NotEmpty(t, entries[0].User)
+	assert.Equal(t, "all hooks", entries[2].Description())
+	assert.Equal(t, "shared hooks 'https://a.com/hooks.git' at commit '1234'", entries[3].Description())
+
+	entries, err = LoadTrustAuditEntries(dir, &TrustAuditFilter{Repository: "/repo-a"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(entries))
+
+	entries, err = LoadTrustAuditEntries(dir, &TrustAuditFilter{NamespacePath: "ns:a/**/lint"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(entries))
+
+	entries, err = LoadTrustAuditEntries(dir, &TrustAuditFilter{Decision: TrustDecisionRevoked})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "/repo-b", entries[0].Repository)
+
+	entries, err = LoadTrustAuditEntries(dir, &TrustAuditFilter{Since: time.Now().Add(time.Hour)})
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestChecksumStoreExpiry(t *testing.T) {
+	dir, err := os.MkdirTemp("", "githooks-trust")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	hookA := path.Join(dir, "hook-a.sh")
+	hookB := path.Join(dir, "hook-b.sh")
+	assert.Nil(t, os.WriteFile(hookA, []byte("echo a"), cm.DefaultFileModeFile))
+	assert.Nil(t, os.WriteFile(hookB, []byte("echo b"), cm.DefaultFileModeFile))
+
+	store, err := GetChecksumStorage(path.Join(dir, "git"))
+	assert.Nil(t, err)
+
+	checksumA, err := GetChecksum(hookA)
+	assert.Nil(t, err)
+	checksumB, err := GetChecksum(hookB)
+	assert.Nil(t, err)
+
+	assert.Nil(t, store.SyncChecksumAdd(ChecksumResult{Checksum: checksumA, Path: hookA}))
+	assert.Nil(t, store.SyncChecksumAddWithExpiry(time.Hour, ChecksumResult{Checksum: checksumB, Path: hookB}))
+
+	trusted, _, err := store.IsTrusted(hookA)
+	assert.Nil(t, err)
+	assert.True(t, trusted)
+	trusted, _, err = store.IsTrusted(hookB)
+	assert.Nil(t, err)
+	assert.True(t, trusted)
+
+	// Let the entry of hook A be trusted two hours ago.
+	var data checksumFile
+	assert.Nil(t, cm.LoadYAML(store.getChecksumFile(checksumA), &data))
+	assert.False(t, data.Trusted.IsZero())
+	data.Trusted = data.Trusted.Add(-2 * time.Hour)
+	assert.Nil(t, cm.StoreYAML(store.getChecksumFile(checksumA), &data))
+
+	trusted, _, err = store.IsTrusted(hookA)
+	assert.Nil(t, err)
+	assert.True(t, trusted, "Entries do not expire without expiry.")
+
+	store.SetExpiry(time.Hour)
+
+	trusted, _, err = store.IsTrusted(hookA)
+	assert.Nil(t, err)
+	assert.False(t, trusted)
+	expired, err := store.IsExpired(checksumA)
+	assert.Nil(t, err)
+	assert.True(t, expired)
+
+	trusted, _, err = store.IsTrusted(hookB)
+	assert.Nil(t, err)
+	assert.True(t, trusted)
+
+	// Explicitly expired entries.
+	assert.Nil(t, cm.LoadYAML(store.getChecksumFile(checksumB), &data))
+	assert.False(t, data.Expires.IsZero())
+	data.Expires = time.Now().Add(-time.Minute)
+	assert.Nil(t, cm.StoreYAML(store.getChecksumFile(checksumB), &data))
+
+	store.SetExpiry(0)
+	trusted, _, err = store.IsTrusted(hookB)
+	assert.Nil(t, err)
+	assert.False(t, trusted)
+
+	// Trusting again renews the trust.
+	store.SetExpiry(time.Hour)
+	assert.Nil(t, store.SyncChecksumAdd(ChecksumResult{Checksum: checksumA, Path: hookA}))
+	trusted, _, err = store.IsTrusted(hookA)
+	assert.Nil(t, err)
+	assert.True(t, trusted)
+}
@@ -0,0 +1,84 @@
+package hooks
+
+import (
+	"path"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	strs "github.com/gabyx/githooks/githooks/strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// RepoVersionRequirement is the Githooks version a repository requires.
+type RepoVersionRequirement struct {
+	// Version constraints, e.g. `>= 2.5.0, < 3.0.0`.
+	Required string `yaml:"required"`
+
+	// If hooks fail instead of only warn if
+	// the installed version does not fulfill the constraints.
+	Enforce bool `yaml:"enforce"`
+
+	// The version of the file.
+	Version int `yaml:"version"`
+
+	constraints version.Constraints
+}
+
+// Version for RepoVersionRequirement.
+// Version 1: Initial.
+const repoVersionRequirementVersion int = 1
+
+// GetRepoVersionFile gets the file with the required Githooks version in the repository.
+func GetRepoVersionFile(repoDir string) string {
+	return path.Join(GetGithooksDir(repoDir), ".version.yaml")
+}
+
+// GetRepoVersionFileRel gets the file with the required Githooks version
+// with respect to the repository.
+func GetRepoVersionFileRel() string {
+	return path.Join(HooksDirName, ".version.yaml")
+}
+
+// LoadRepoVersionRequirement loads the required Githooks version of the repository
+// in `repoDir` and reports if it exists.
+func LoadRepoVersionRequirement(repoDir string) (req RepoVersionRequirement, exists bool, err error) {
+	file := GetRepoVersionFile(repoDir)
+	if !cm.IsFile(file) {
+		return
+	}
+
+	if err = cm.LoadYAML(file, &req); err != nil {
+		return RepoVersionRequirement{}, false,
+			cm.CombineErrors(cm.ErrorF("Could not load required version '%s'.", file), err)
+	}
+
+	if req.Version < 1 || req.Version > repoVersionRequirementVersion {
+		return RepoVersionRequirement{}, false, cm.ErrorF(
+			"File '%s' has version '%v'. "+
+				"This version of Githooks only supports version >= 1 and <= '%v'.",
+			file, req.Version, repoVersionRequirementVersion)
+	}
+
+	if strs.IsEmpty(req.Required) {
+		return RepoVersionRequirement{}, false,
+			cm.ErrorF("Required version in '%s' is empty.", file)
+	}
+
+	req.constraints, err = version.NewConstraint(req.Required)
+	if err != nil {
+		return RepoVersionRequirement{}, false,
+			cm.CombineErrors(cm.ErrorF("Required version '%s' in '%s' is invalid.", req.Required, file), err)
+	}
+
+	return req, true, nil
+}
+
+// GetConstraints gets the version constraints.
+func (r *RepoVersionRequirement) GetConstraints() version.Constraints {
+	return r.constraints
+}
+
+// IsFulfilled reports if the version `ver` fulfills the required version.
+func (r *RepoVersionRequirement) IsFulfilled(ver *version.Version) bool {
+	return ver != nil && r.constraints.Check(ver)
+}
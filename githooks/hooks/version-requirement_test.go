@@ -0,0 +1,47 @@
+package hooks
+
+import (
+	"os"
+	"testing"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoVersionRequirement(t *testing.T) {
+	repoDir, err := os.MkdirTemp("", "githooks-version")
+	assert.Nil(t, err)
+	defer os.RemoveAll(repoDir)
+
+	_, exists, err := LoadRepoVersionRequirement(repoDir)
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	assert.Nil(t, os.MkdirAll(GetGithooksDir(repoDir), cm.DefaultFileModeDirectory))
+	file := GetRepoVersionFile(repoDir)
+
+	assert.Nil(t, cm.StoreYAML(file,
+		&RepoVersionRequirement{Required: ">= 2.5.0, < 3.0.0", Enforce: true, Version: 1}))
+
+	req, exists, err := LoadRepoVersionRequirement(repoDir)
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	assert.True(t, req.Enforce)
+
+	assert.True(t, req.IsFulfilled(version.Must(version.NewVersion("2.5.0"))))
+	assert.True(t, req.IsFulfilled(version.Must(version.NewVersion("2.9.1"))))
+	assert.False(t, req.IsFulfilled(version.Must(version.NewVersion("2.4.9"))))
+	assert.False(t, req.IsFulfilled(version.Must(version.NewVersion("3.0.0"))))
+	assert.False(t, req.IsFulfilled(nil))
+
+	// Invalid constraint.
+	assert.Nil(t, cm.StoreYAML(file, &RepoVersionRequirement{Required: "> abc", Version: 1}))
+	_, _, err = LoadRepoVersionRequirement(repoDir)
+	assert.NotNil(t, err)
+
+	// Wrong version.
+	assert.Nil(t, cm.StoreYAML(file, &RepoVersionRequirement{Required: "> 1.0", Version: 2}))
+	_, _, err = LoadRepoVersionRequirement(repoDir)
+	assert.NotNil(t, err)
+}
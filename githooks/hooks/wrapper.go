@@ -1,6 +1,7 @@
 package hooks
 
 import (
+	"bytes"
 	"os"
 	"path"
 	"regexp"
@@ -25,11 +26,31 @@ func GetHookReplacementFileName(fileName string) string {
 	return path.Base(fileName) + ".replaced.githook"
 }
 
+// GetHookExternalBackupFileName returns the file name of the backup
+// of a Git hook generated by an external hook manager.
+func GetHookExternalBackupFileName(fileName string) string {
+	return path.Base(fileName) + ".external.githook"
+}
+
 // GetRunWrapperContent gets the bytes of the hook template.
 func getRunWrapperContent() ([]byte, error) {
 	return build.Asset("embedded/run-wrapper.sh")
 }
 
+// IsRunWrapperUpToDate checks if the run-wrapper `filePath`
+// has the content of the run-wrapper of this version.
+func IsRunWrapperUpToDate(filePath string) (bool, error) {
+	runWrapperContent, err := getRunWrapperContent()
+	cm.AssertNoErrorPanic(err, "Could not get embedded run-wrapper content.")
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(content, runWrapperContent), nil
+}
+
 // WriteRunWrapper writes the run-wrapper to the file `filePath`.
 func WriteRunWrapper(filePath string) (err error) {
 	runWrapperContent, err := getRunWrapperContent()
@@ -265,6 +286,28 @@ func UninstallRunWrappers(dir string, lfsHooksCache LFSHooksCache) (int, error)
 	return uninstallRunWrappers(ManagedHookNames, dir, lfsHooksCache)
 }
 
+// GetRunWrappers gets all run-wrappers in `dir` which
+// `UninstallRunWrappers` would delete.
+func GetRunWrappers(dir string) (files []string, err error) {
+	for _, hookName := range ManagedHookNames {
+
+		dest := path.Join(dir, hookName)
+		if !cm.IsFile(dest) {
+			continue
+		}
+
+		isRunWrapper, e := IsRunWrapper(dest)
+		if e != nil {
+			err = cm.CombineErrors(err,
+				cm.ErrorF("Run-wrapper detection for '%s' failed.", dest))
+		} else if isRunWrapper {
+			files = append(files, dest)
+		}
+	}
+
+	return
+}
+
 func uninstallRunWrappers(
 	hookNames []string,
 	dir string,
@@ -302,6 +345,18 @@ func uninstallRunWrappers(
 					}
 				}
 
+				// Otherwise move the backup of a hook generated by
+				// an external hook manager (if existing) back in place.
+				backupHook := path.Join(path.Dir(dest), GetHookExternalBackupFileName(dest))
+
+				if !cm.IsFile(dest) && cm.IsFile(backupHook) {
+					if e := os.Rename(backupHook, dest); e != nil {
+						err = cm.CombineErrors(err,
+							cm.ErrorF("Could not rename file '%s' to '%s'.",
+								backupHook, dest))
+					}
+				}
+
 			} else {
 				err = cm.CombineErrors(err, cm.ErrorF("Could not delete file '%s'.", dest))
 			}
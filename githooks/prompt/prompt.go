@@ -33,6 +33,8 @@ type IContext interface {
 		exitAnswer string,
 		validator AnswerValidator) ([]string, error)
 
+	ShowText(text string) error
+
 	Close()
 
 	AddFileWriter(sink io.Writer)
@@ -171,6 +171,24 @@ func showMessageGUI(
 	return err
 }
 
+func showTextGUI(
+	p *Context,
+	title string,
+	text string) error {
+
+	opts := settings.Text{}
+	opts.Title = title
+	opts.Text = text
+	opts.WindowIcon = settings.InfoIcon
+	opts.Width = 700
+	opts.Height = 500
+	opts.ForceTopMost = true // only for Windows this is crucial, such that it does not get ignored.
+
+	_, err := gui.ShowText(nil, &opts) // nolint
+
+	return err
+}
+
 func showOptionsGUI(
 	p *Context,
 	title string,
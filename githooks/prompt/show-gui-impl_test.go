@@ -40,4 +40,7 @@ func TestCoverage(t *testing.T) {
 
 	e = promptx.ShowMessage("This is a warning prompt message", true)
 	log.AssertNoErrorF(e, "Error occurred.")
+
+	e = promptx.ShowText("--- trusted\n+++ current\n@@ -1 +1 @@\n-echo 'a'\n+echo 'b'\n")
+	log.AssertNoErrorF(e, "Error occurred.")
 }
@@ -2,9 +2,13 @@ package prompt
 
 import (
 	"errors"
+	"io"
+	"os"
+	"os/exec"
 	"strings"
 
 	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
 	strs "github.com/gabyx/githooks/githooks/strings"
 )
 
@@ -69,6 +73,72 @@ func showMessage(p *Context, text string, asError bool) error {
 	return err
 }
 
+// showText shows a long text `text` to the user:
+// In the terminal through the Git pager or in a scrollable text view of the dialog.
+func showText(p *Context, text string) (err error) {
+	var isDisplayed bool
+
+	if p.useGUI {
+		// Use the GUI dialog.
+		err = showTextGUI(p, formatTitle(p), text)
+		isDisplayed = err == nil
+	} else {
+		// Use the terminal (if possible...)
+		isDisplayed, err = showTextTerminal(p, text)
+	}
+
+	if !isDisplayed {
+		// Show the text in the log output
+		p.log.Info(text)
+	}
+
+	return
+}
+
+// showTextTerminal shows the text `text` in the pager configured in Git
+// (`GIT_PAGER`, `core.pager`, `PAGER`, default `less`) which reads
+// its input from the terminal. Without terminal input, the text is written directly.
+func showTextTerminal(p *Context, text string) (bool, error) {
+	if p.termOut == nil {
+		return false, cm.ErrorF("No terminal output available to show text.")
+	}
+
+	text = strings.TrimSuffix(text, "\n") + "\n"
+
+	pager, err := git.NewCtx().Get("var", "GIT_PAGER")
+	if p.termIn == nil || err != nil || strs.IsEmpty(pager) || pager == "cat" {
+		_, err = io.WriteString(p.termOut, text)
+
+		return err == nil, err
+	}
+
+	// Run the pager like Git does.
+	cmd := exec.Command("sh", "-c", pager)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = p.termOut
+	cmd.Stderr = p.termErr
+	cmd.Env = os.Environ()
+
+	if _, exists := os.LookupEnv("LESS"); !exists {
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+
+	if _, exists := os.LookupEnv("LV"); !exists {
+		cmd.Env = append(cmd.Env, "LV=-c")
+	}
+
+	if e := cmd.Run(); e != nil {
+		p.log.DebugF("Could not run pager '%s': %v", pager, e)
+
+		// Fallback to writing the text directly.
+		_, err = io.WriteString(p.termOut, text)
+
+		return err == nil, err
+	}
+
+	return true, nil
+}
+
 // showOptions shows a prompt to the user with `text`
 // with the options `shortOptions` and optional long options `longOptions`.
 func showOptions(
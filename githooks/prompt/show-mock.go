@@ -34,12 +34,24 @@ func (p Context) ShowOptions(text string,
 			p.termIn = strings.NewReader(strings.ToLower(answer) + "\n")
 			p.termInScanner = bufio.NewScanner(p.termIn)
 		}
+	} else if strings.Contains(text, "Do you trust all hooks at this revision") {
+		answer, defined := os.LookupEnv("ACCEPT_REVISION")
+		if defined {
+			p.termIn = strings.NewReader(strings.ToLower(answer) + "\n")
+			p.termInScanner = bufio.NewScanner(p.termIn)
+		}
 	} else if strings.Contains(text, "There is a new Githooks update available") {
 		answer, defined := os.LookupEnv("EXECUTE_UPDATE")
 		if defined {
 			p.termIn = strings.NewReader(strings.ToLower(answer) + "\n")
 			p.termInScanner = bufio.NewScanner(p.termIn)
 		}
+	} else if strings.Contains(text, "does not match the required version") {
+		answer, defined := os.LookupEnv("INSTALL_REQUIRED_VERSION")
+		if defined {
+			p.termIn = strings.NewReader(strings.ToLower(answer) + "\n")
+			p.termInScanner = bufio.NewScanner(p.termIn)
+		}
 	}
 
 	return showOptions(&p, text, hintText, shortOptions, longOptions...)
@@ -68,3 +80,8 @@ func (p *Context) ShowEntryMulti(
 func (p *Context) ShowMessage(text string, asError bool) (err error) {
 	return showMessage(p, text, asError)
 }
+
+// ShowText shows a long text `text` (e.g. a diff) to the user.
+func (p *Context) ShowText(text string) error {
+	return showText(p, text)
+}
@@ -41,3 +41,8 @@ func (p *Context) ShowEntryMulti(
 	validator AnswerValidator) (answers []string, err error) {
 	return showEntryMulti(p, text, exitAnswer, validator)
 }
+
+// ShowText shows a long text `text` (e.g. a diff) to the user.
+func (p *Context) ShowText(text string) error {
+	return showText(p, text)
+}
@@ -0,0 +1,193 @@
+package updates
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
+	"github.com/gabyx/githooks/githooks/hooks"
+	strs "github.com/gabyx/githooks/githooks/strings"
+	"github.com/gabyx/githooks/githooks/updates/download"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-version"
+)
+
+// UpdateChannel is the channel of versions which are taken for updates.
+type UpdateChannel struct {
+	Name string
+	download.UpdateChannel
+
+	constraints   version.Constraints
+	rolloutPeriod time.Duration
+	machineID     string
+}
+
+// The update channels available if not defined in the deploy settings.
+var defaultUpdateChannels = map[string]download.UpdateChannel{
+	"stable": {},
+	"beta":   {PreRelease: true}}
+
+// GetUpdateChannelName gets the configured update channel.
+func GetUpdateChannelName(gitx *git.Context) string {
+	return gitx.GetConfig(hooks.GitCKUpdateChannel, git.GlobalScope)
+}
+
+// SetUpdateChannelName sets the update channel.
+func SetUpdateChannelName(name string) error {
+	cm.DebugAssertF(strs.IsNotEmpty(name), "Wrong input")
+
+	return git.NewCtx().SetConfig(hooks.GitCKUpdateChannel, name, git.GlobalScope)
+}
+
+// ResetUpdateChannelName resets the update channel.
+func ResetUpdateChannelName() error {
+	return git.NewCtx().UnsetConfig(hooks.GitCKUpdateChannel, git.GlobalScope)
+}
+
+// LoadUpdateChannel loads the update channel `name` from the
+// deploy settings `deploySettingsFile` (can be non-existing).
+// If `name` is empty, the configured channel or the default channel of the
+// deploy settings is taken. Returns `nil` if no channel is used.
+func LoadUpdateChannel(gitx *git.Context, deploySettingsFile string, name string) (*UpdateChannel, error) {
+	var channels *download.UpdateChannels
+
+	if cm.IsFile(deploySettingsFile) {
+		var err error
+		if channels, err = download.LoadUpdateChannels(deploySettingsFile); err != nil {
+			return nil, cm.CombineErrors(
+				cm.ErrorF("Could not load update channels from '%s'.", deploySettingsFile), err)
+		}
+	}
+
+	if strs.IsEmpty(name) {
+		name = GetUpdateChannelName(gitx)
+	}
+
+	if strs.IsEmpty(name) && channels != nil {
+		name = channels.Default
+	}
+
+	if strs.IsEmpty(name) {
+		return nil, nil
+	}
+
+	channel, exists := download.UpdateChannel{}, false
+	if channels != nil {
+		channel, exists = channels.Channels[name]
+	}
+
+	if !exists {
+		channel, exists = defaultUpdateChannels[name]
+	}
+
+	if !exists {
+		return nil, cm.ErrorF("Update channel '%s' is not defined in the deploy settings '%s'.",
+			name, deploySettingsFile)
+	}
+
+	return newUpdateChannel(gitx, name, channel)
+}
+
+func newUpdateChannel(gitx *git.Context, name string, channel download.UpdateChannel) (c *UpdateChannel, err error) {
+	c = &UpdateChannel{Name: name, UpdateChannel: channel}
+
+	var constraints []string
+	if strs.IsNotEmpty(channel.Versions) {
+		constraints = append(constraints, channel.Versions)
+	}
+
+	if strs.IsNotEmpty(channel.Pin) {
+		constraints = append(constraints, "= "+channel.Pin)
+	}
+
+	if len(constraints) != 0 {
+		c.constraints, err = version.NewConstraint(strings.Join(constraints, ","))
+		if err != nil {
+			return nil, cm.CombineErrors(cm.ErrorF("Versions of update channel '%s' are invalid.", name), err)
+		}
+	}
+
+	if channel.Rollout == nil {
+		return
+	}
+
+	if channel.Rollout.Percentage < 0 || channel.Rollout.Percentage > 100 {
+		return nil, cm.ErrorF("Rollout percentage '%v' of update channel '%s' is not in [0, 100].",
+			channel.Rollout.Percentage, name)
+	}
+
+	c.rolloutPeriod, err = time.ParseDuration(channel.Rollout.Period)
+	if err != nil || c.rolloutPeriod < 0 {
+		return nil, cm.CombineErrors(
+			cm.ErrorF("Rollout period '%s' of update channel '%s' is not a valid duration.",
+				channel.Rollout.Period, name), err)
+	}
+
+	c.machineID, err = GetMachineID(gitx)
+
+	return c, err
+}
+
+// Includes reports if the version `ver` fulfills the version constraints of the channel.
+func (c *UpdateChannel) Includes(ver *version.Version) bool {
+	return c.constraints == nil || c.constraints.Check(ver)
+}
+
+// IsRolledOut reports if the version tag `tag` released at `releaseTime`
+// is rolled out to this machine.
+func (c *UpdateChannel) IsRolledOut(tag string, releaseTime time.Time) bool {
+	if c.Rollout == nil || time.Since(releaseTime) >= c.rolloutPeriod {
+		return true
+	}
+
+	return isInRollout(c.machineID, tag, c.Rollout.Percentage)
+}
+
+// isInRollout reports if the machine with id `machineID` belongs to the
+// deterministic fraction `percentage` of all machines taking the version `tag`.
+func isInRollout(machineID string, tag string, percentage int) bool {
+	hash := sha256.Sum256([]byte(machineID + ":" + tag))
+
+	return binary.BigEndian.Uint64(hash[:8])%100 < uint64(percentage) //nolint: gomnd
+}
+
+// getReleaseTime gets the time when the version tag `tag` has been created.
+func getReleaseTime(gitx *git.Context, tag string) (t time.Time, err error) {
+	out, err := gitx.Get("tag", "-l", "--format=%(creatordate:unix)", tag)
+	if err != nil {
+		return
+	}
+
+	secs, err := strconv.ParseInt(out, 10, 64) //nolint: gomnd
+	if err != nil {
+		return t, cm.CombineErrors(cm.ErrorF("Could not get creation time of tag '%s'.", tag), err)
+	}
+
+	return time.Unix(secs, 0), nil
+}
+
+// GetMachineID gets an id for this machine.
+// It is the system's machine id if available, otherwise
+// a random id is created and stored in the global Git config.
+func GetMachineID(gitx *git.Context) (string, error) {
+	for _, file := range []string{"/etc/machine-id", "/var/lib/dbus/machine-id"} {
+		if data, err := os.ReadFile(file); err == nil && strs.IsNotEmpty(strings.TrimSpace(string(data))) {
+			return strings.TrimSpace(string(data)), nil
+		}
+	}
+
+	id := gitx.GetConfig(hooks.GitCKMachineID, git.GlobalScope)
+	if strs.IsNotEmpty(id) {
+		return id, nil
+	}
+
+	id = uuid.New().String()
+
+	return id, gitx.SetConfig(hooks.GitCKMachineID, id, git.GlobalScope)
+}
@@ -0,0 +1,139 @@
+package updates
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gabyx/githooks/githooks/git"
+	strs "github.com/gabyx/githooks/githooks/strings"
+	"github.com/gabyx/githooks/githooks/updates/download"
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateChannels(t *testing.T) {
+	dir, err := os.MkdirTemp("", "githooks-channels")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	gitx := git.NewCtxAt(dir)
+	file := path.Join(dir, "deploy.yaml")
+
+	// Default channels without deploy settings.
+	channel, err := LoadUpdateChannel(gitx, file, "beta")
+	assert.Nil(t, err)
+	assert.Equal(t, "beta", channel.Name)
+	assert.True(t, channel.PreRelease)
+
+	_, err = LoadUpdateChannel(gitx, file, "nightly")
+	assert.NotNil(t, err)
+
+	assert.Nil(t, download.StoreDeploySettings(file,
+		&download.LocalDeploySettings{PathTemplate: "githooks.tar.gz"},
+		&download.UpdateChannels{
+			Default: "pinned",
+			Channels: map[string]download.UpdateChannel{
+				"stable": {Versions: "< 3.0.0"},
+				"pinned": {Pin: "v2.5.0"},
+				"broken": {Versions: "> abc"}}}))
+
+	channel, err = LoadUpdateChannel(gitx, file, "stable")
+	assert.Nil(t, err)
+	assert.True(t, channel.Includes(version.Must(version.NewVersion("2.9.0"))))
+	assert.False(t, channel.Includes(version.Must(version.NewVersion("3.0.0"))))
+
+	channel, err = LoadUpdateChannel(gitx, file, "pinned")
+	assert.Nil(t, err)
+	assert.True(t, channel.Includes(version.Must(version.NewVersion("v2.5.0"))))
+	assert.False(t, channel.Includes(version.Must(version.NewVersion("2.5.1"))))
+
+	_, err = LoadUpdateChannel(gitx, file, "broken")
+	assert.NotNil(t, err)
+
+	// The deploy settings are still loadable.
+	settings, err := download.LoadDeploySettings(file)
+	assert.Nil(t, err)
+	assert.IsType(t, &download.LocalDeploySettings{}, settings)
+}
+
+func TestUpdateRollout(t *testing.T) {
+	channel := UpdateChannel{
+		Name:          "stable",
+		UpdateChannel: download.UpdateChannel{Rollout: &download.UpdateRollout{Percentage: 20, Period: "72h"}},
+		rolloutPeriod: 72 * time.Hour,
+		machineID:     "machine"}
+
+	// Old versions are always rolled out.
+	assert.True(t, channel.IsRolledOut("v1.0.0", time.Now().Add(-73*time.Hour)))
+
+	// The rollout is deterministic.
+	assert.Equal(t,
+		channel.IsRolledOut("v1.0.0", time.Now()),
+		channel.IsRolledOut("v1.0.0", time.Now()))
+
+	count := 0
+	for i := 0; i < 1000; i++ {
+		if isInRollout(strs.Fmt("machine-%v", i), "v1.0.0", 20) {
+			count++
+		}
+	}
+	assert.InDelta(t, 200, count, 50)
+
+	assert.False(t, isInRollout("machine", "v1.0.0", 0))
+	assert.True(t, isInRollout("machine", "v1.0.0", 100))
+}
+
+func TestUpdateNoSkipRollout(t *testing.T) {
+	dir, err := os.MkdirTemp("", "githooks-noskip")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@test.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@test.com")
+
+	gitx := git.NewCtxAt(dir)
+	assert.Nil(t, gitx.Check("init"))
+
+	commit := func(tag string, annotation ...string) string {
+		assert.Nil(t, gitx.Check("commit", "--allow-empty", "-m", tag))
+		if len(annotation) != 0 {
+			assert.Nil(t, gitx.Check(append([]string{"tag", "-a", tag}, annotation...)...))
+		} else {
+			assert.Nil(t, gitx.Check("tag", tag))
+		}
+
+		sha, err := gitx.Get("rev-parse", "HEAD")
+		assert.Nil(t, err)
+
+		return sha
+	}
+
+	first := commit("v1.0.0")
+	commit("v1.1.0", "-m", "Version 1.1.0", "-m", "Update-NoSkip: true")
+
+	// An old release, which is rolled out.
+	t.Setenv("GIT_COMMITTER_DATE", time.Now().Add(-100*time.Hour).Format(time.RFC3339))
+	last := commit("v1.2.0")
+
+	channel := UpdateChannel{
+		Name:          "stable",
+		UpdateChannel: download.UpdateChannel{Rollout: &download.UpdateRollout{Percentage: 0, Period: "72h"}},
+		rolloutPeriod: 72 * time.Hour,
+		machineID:     "machine"}
+
+	// The unskippable version is not rolled out, no update beyond it is offered.
+	commitSHA, _, ver, _, err := getNewUpdateCommit(gitx, first, last, false, &channel)
+	assert.Nil(t, err)
+	assert.Empty(t, commitSHA)
+	assert.Nil(t, ver)
+
+	// Without a rollout the unskippable version is offered.
+	channel.Rollout = nil
+	_, tag, _, _, err := getNewUpdateCommit(gitx, first, last, false, &channel)
+	assert.Nil(t, err)
+	assert.Equal(t, "v1.1.0", tag)
+}
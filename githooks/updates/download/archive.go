@@ -0,0 +1,131 @@
+package download
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"regexp"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/hashicorp/go-version"
+)
+
+const githooksArchiveName = "githooks"
+
+// The released archive name `githooks-<version>-<os>.<arch><ext>`.
+var githooksArchiveVersionRe = regexp.MustCompile(`^githooks-(?P<version>.+)-\w+\.\w+\.(tar\.gz|zip)$`)
+
+// ReleaseArchive is a release archive of the Githooks binaries
+// together with its checksum file and the signature of it.
+type ReleaseArchive struct {
+	File      string // The archive file.
+	Extension string // The extension of the archive, e.g. `.tar.gz`.
+
+	Checksums          string // The checksum file.
+	ChecksumsSignature string // The signature of the checksum file.
+}
+
+// NewReleaseArchive gets the release archive with `extension`
+// stored in `dir` (see `IDeploySettings.DownloadArchive`).
+func NewReleaseArchive(dir string, extension string) ReleaseArchive {
+	return ReleaseArchive{
+		File:               path.Join(dir, githooksArchiveName+extension),
+		Extension:          extension,
+		Checksums:          path.Join(dir, githooksChecksumFile),
+		ChecksumsSignature: path.Join(dir, githooksChecksumSignatureFile)}
+}
+
+// FindReleaseArchive finds the release archive stored in `dir`
+// (see `IDeploySettings.DownloadArchive`).
+func FindReleaseArchive(dir string) (ReleaseArchive, error) {
+	for _, ext := range []string{".tar.gz", ".zip"} {
+		archive := NewReleaseArchive(dir, ext)
+		if cm.IsFile(archive.File) {
+			return archive, nil
+		}
+	}
+
+	return ReleaseArchive{}, cm.ErrorF("Could not find any release archive in '%s'.", dir)
+}
+
+// Verify verifies the signature of the checksum file with
+// the public key `publicPGP` and the checksum of the archive.
+func (a *ReleaseArchive) Verify(publicPGP string) error {
+	checksumBytes, err := os.ReadFile(a.Checksums)
+	if err != nil {
+		return cm.CombineErrors(err, cm.ErrorF("Could not read checksum file '%s'.", a.Checksums))
+	}
+
+	signature, err := os.Open(a.ChecksumsSignature)
+	if err != nil {
+		return cm.CombineErrors(err, cm.ErrorF("Could not open signature file '%s'.", a.ChecksumsSignature))
+	}
+	defer signature.Close()
+
+	err = cm.VerifyFile(bytes.NewReader(checksumBytes), signature, publicPGP)
+	if err != nil {
+		return cm.CombineErrors(err,
+			cm.ErrorF("Signature verification of checksum file '%s' failed. "+
+				"Something is fishy!", a.Checksums))
+	}
+
+	return a.verifyChecksum(checksumBytes)
+}
+
+// VerifyChecksum verifies only the checksum of the archive
+// without the signature of the checksum file.
+func (a *ReleaseArchive) VerifyChecksum() error {
+	checksumBytes, err := os.ReadFile(a.Checksums)
+	if err != nil {
+		return cm.CombineErrors(err, cm.ErrorF("Could not read checksum file '%s'.", a.Checksums))
+	}
+
+	return a.verifyChecksum(checksumBytes)
+}
+
+func (a *ReleaseArchive) verifyChecksum(checksumBytes []byte) error {
+	_, err := checkChecksum(a.File, checksumBytes)
+	if err != nil {
+		return cm.CombineErrors(err, cm.ErrorF("Checksum validation of '%s' failed.", a.File))
+	}
+
+	return nil
+}
+
+// VerifyVersion verifies that the entry of the archive in the checksum file
+// is the released archive of version `versionTag`.
+// This is only trustworthy after the signature has been verified with `Verify`.
+func (a *ReleaseArchive) VerifyVersion(versionTag string) error {
+	checksumBytes, err := os.ReadFile(a.Checksums)
+	if err != nil {
+		return cm.CombineErrors(err, cm.ErrorF("Could not read checksum file '%s'.", a.Checksums))
+	}
+
+	entry, err := checkChecksum(a.File, checksumBytes)
+	if err != nil {
+		return cm.CombineErrors(err, cm.ErrorF("Checksum validation of '%s' failed.", a.File))
+	}
+
+	expected, err := version.NewVersion(versionTag)
+	if err != nil {
+		return cm.CombineErrors(err, cm.ErrorF("Version '%s' is not a valid version.", versionTag))
+	}
+
+	match := githooksArchiveVersionRe.FindStringSubmatch(entry)
+	if match == nil {
+		return cm.ErrorF("Checksum entry '%s' of '%s' is not a release archive.", entry, a.File)
+	}
+
+	ver, err := version.NewVersion(match[githooksArchiveVersionRe.SubexpIndex("version")])
+	if err != nil || !ver.Equal(expected) {
+		return cm.ErrorF("Archive '%s' is the release archive '%s' and not of version '%s'.",
+			a.File, entry, versionTag)
+	}
+
+	return nil
+}
+
+// Extract extracts the archive into `dir`.
+func (a *ReleaseArchive) Extract(dir string) error {
+	return Extract(a.File, a.Extension, dir)
+}
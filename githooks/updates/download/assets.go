@@ -3,11 +3,39 @@ package download
 import (
 	"regexp"
 	"runtime"
+	"strings"
 
 	cm "github.com/gabyx/githooks/githooks/common"
 	strs "github.com/gabyx/githooks/githooks/strings"
 )
 
+// Platform is an operating system and architecture
+// for which the Githooks binaries are released.
+type Platform struct {
+	OS   string // The operating system like `runtime.GOOS`.
+	Arch string // The architecture like `runtime.GOARCH`.
+}
+
+// GetRuntimePlatform gets the platform of the current runtime.
+func GetRuntimePlatform() Platform {
+	return Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+}
+
+// ParsePlatform parses a platform `<os>/<arch>`, e.g. `linux/amd64`.
+func ParsePlatform(s string) (p Platform, err error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 || strs.IsEmpty(parts[0]) || strs.IsEmpty(parts[1]) { //nolint: gomnd
+		return p, cm.ErrorF("Platform '%s' is not of the form '<os>/<arch>'.", s)
+	}
+
+	return Platform{OS: parts[0], Arch: parts[1]}, nil
+}
+
+// String formats the platform as `<os>/<arch>`.
+func (p Platform) String() string {
+	return p.OS + "/" + p.Arch
+}
+
 // Asset holds data for Git web services such as Github or Gitea.
 type Asset struct {
 	FileName string // The file name of the asset.
@@ -37,16 +65,16 @@ var githooksBuildAssetRe = regexp.MustCompile(`githooks-.*-(?P<platform>\w+)\.(?
 const githooksChecksumFile = "githooks.checksums"
 const githooksChecksumSignatureFile = "githooks.checksums.sig"
 
-// getGithooksAsset returns the correct Githooks asset from the list `assets`.
-// The `target.Extension` is also provided.
-func getGithooksAsset(assets []Asset) (target Asset, checksums Checksums, err error) {
+// getGithooksAsset returns the correct Githooks asset for platform `platform`
+// from the list `assets`. The `target.Extension` is also provided.
+func getGithooksAsset(assets []Asset, platform Platform) (target Asset, checksums Checksums, err error) {
 
-	targetOs := runtime.GOOS
+	targetOs := platform.OS
 	if targetOs == "darwin" {
 		targetOs = "macos"
 	}
 
-	targetArch := runtime.GOARCH
+	targetArch := platform.Arch
 
 	// Search checksums and its signature.
 	for i := range assets {
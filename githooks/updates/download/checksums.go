@@ -1,46 +1,15 @@
 package download
 
 import (
-	"bytes"
-	"io"
 	"os"
 	"strings"
 
 	cm "github.com/gabyx/githooks/githooks/common"
 )
 
-// verifyChecksumSignature verifies checksums with the signature and the public key, and returns
-// the checksums content.
-func verifyChecksumSignature(checksums Checksums, publicPGP string) ([]byte, error) {
-
-	checksumFile, err := GetFile(checksums.File.URL)
-	if err != nil {
-		return nil, err
-	}
-	defer checksumFile.Body.Close()
-
-	checksumFileSignature, err := GetFile(checksums.FileSignature.URL)
-	if err != nil {
-		return nil, err
-	}
-	defer checksumFileSignature.Body.Close()
-
-	// Read the checksumFile into memory
-	checksumBytes, err := io.ReadAll(checksumFile.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	err = cm.VerifyFile(bytes.NewReader(checksumBytes), checksumFileSignature.Body, publicPGP)
-	if err != nil {
-		return nil, err
-	}
-
-	return checksumBytes, nil
-}
-
-// checkChecksum checks if the checksum of file matches the checksum.
-func checkChecksum(filePath string, checksumData []byte) (err error) {
+// checkChecksum checks if the checksum of file matches an entry in the checksum data
+// and returns the file name of this entry.
+func checkChecksum(filePath string, checksumData []byte) (entry string, err error) {
 	var file *os.File
 
 	file, err = os.Open(filePath)
@@ -51,12 +20,16 @@ func checkChecksum(filePath string, checksumData []byte) (err error) {
 
 	hash, err := cm.GetSHA256Hash(file)
 	if err != nil {
-		return err
+		return
 	}
 
-	if !strings.Contains(string(checksumData), hash) {
-		return cm.ErrorF("Could not find checksum '%s' in checksum data.", filePath)
+	// Each line is `<checksum>  <file name>`.
+	for _, line := range strings.Split(string(checksumData), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == hash { //nolint: gomnd
+			return strings.TrimPrefix(fields[1], "*"), nil
+		}
 	}
 
-	return nil
+	return "", cm.ErrorF("Could not find checksum '%s' in checksum data.", filePath)
 }
@@ -15,13 +15,59 @@ type deploySettings struct {
 	Github *GithubDeploySettings `yaml:"github"`
 	HTTP   *HTTPDeploySettings   `yaml:"http"`
 	Local  *LocalDeploySettings  `yaml:"local"`
+	Index  *IndexDeploySettings  `yaml:"index"`
+
+	UpdateChannels *UpdateChannels `yaml:"updateChannels,omitempty"`
+}
+
+// Version for deploySettings.
+// Version 1: Initial.
+// Version 2: Added `updateChannels` and `index`.
+const deploySettingsVersion = 2
+
+// UpdateRollout defines a staged rollout of new versions.
+type UpdateRollout struct {
+	// The percentage of machines which take a new version
+	// during the rollout period.
+	Percentage int `yaml:"percentage"`
+
+	// The duration after the release of a version
+	// during which the rollout is staged, e.g. `72h`.
+	Period string `yaml:"period"`
+}
+
+// UpdateChannel defines which versions are taken for updates.
+type UpdateChannel struct {
+	// Version constraints for the versions of this channel, e.g. `< 3.0.0`.
+	Versions string `yaml:"versions,omitempty"`
+
+	// If pre-release versions belong to this channel.
+	PreRelease bool `yaml:"preRelease,omitempty"`
+
+	// The version tag this channel is pinned to, e.g. `v2.5.0`.
+	Pin string `yaml:"pin,omitempty"`
+
+	// The staged rollout of new versions.
+	Rollout *UpdateRollout `yaml:"rollout,omitempty"`
 }
 
-const deploySettingsVersion = 1
+// UpdateChannels are the named update channels in the deploy settings.
+type UpdateChannels struct {
+	// The channel used if none is set in `githooks.updateChannel`.
+	Default string `yaml:"default,omitempty"`
+
+	Channels map[string]UpdateChannel `yaml:"channels"`
+}
 
 // IDeploySettings is the common interface for all deploy settings.
 type IDeploySettings interface {
-	Download(log cm.ILogContext, versionTag string, dir string) error
+	// Download downloads the verified binaries of `versionTag`
+	// for `platform` and extracts them into `dir`.
+	Download(log cm.ILogContext, versionTag string, platform Platform, dir string) error
+
+	// DownloadArchive downloads the verified release archive of `versionTag`
+	// for `platform` together with its signed checksum file into `dir`.
+	DownloadArchive(log cm.ILogContext, versionTag string, platform Platform, dir string) (ReleaseArchive, error)
 }
 
 // LoadDeploySettings load the deploy settings from `file`.
@@ -44,18 +90,33 @@ func LoadDeploySettings(file string) (IDeploySettings, error) {
 		return settings.HTTP, nil
 	case settings.Local != nil:
 		return settings.Local, nil
+	case settings.Index != nil:
+		return settings.Index, nil
 	}
 
 	return nil, nil
 }
 
-// StoreDeploySettings stores the deploy `settings` to `file`.
-func StoreDeploySettings(file string, settings IDeploySettings) error {
+// LoadUpdateChannels loads the update channels from the deploy settings `file`.
+// Returns `nil` if no update channels are defined.
+func LoadUpdateChannels(file string) (*UpdateChannels, error) {
+	var settings deploySettings
+	if err := cm.LoadYAML(file, &settings); err != nil {
+		return nil, err
+	}
+
+	return settings.UpdateChannels, nil
+}
+
+// StoreDeploySettings stores the deploy `settings` and
+// the update channels `channels` (can be `nil`) to `file`.
+func StoreDeploySettings(file string, settings IDeploySettings, channels *UpdateChannels) error {
 
 	var s deploySettings
 
 	// Always store the new version
 	s.Version = deploySettingsVersion
+	s.UpdateChannels = channels
 
 	switch v := settings.(type) {
 	case *GiteaDeploySettings:
@@ -66,6 +127,8 @@ func StoreDeploySettings(file string, settings IDeploySettings) error {
 		s.HTTP = v
 	case *LocalDeploySettings:
 		s.Local = v
+	case *IndexDeploySettings:
+		s.Index = v
 	default:
 		cm.PanicF("Cannot store deploy settings for type '%T'", v)
 	}
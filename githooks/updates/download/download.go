@@ -1,7 +1,9 @@
 package download
 
 import (
+	"io"
 	"net/http"
+	"os"
 
 	cm "github.com/gabyx/githooks/githooks/common"
 )
@@ -22,3 +24,86 @@ func GetFile(url string) (response *http.Response, err error) {
 
 	return
 }
+
+// downloadFile downloads the `url` into `file`.
+func downloadFile(url string, file string) error {
+	response, err := GetFile(url)
+	if err != nil {
+		return cm.CombineErrors(err, cm.ErrorF("Could not download url '%s'.", url))
+	}
+	defer response.Body.Close()
+
+	f, err := os.Create(file)
+	if err != nil {
+		return cm.ErrorF("Could not open file '%s' for download.", file)
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, response.Body)
+	if err != nil {
+		return cm.CombineErrors(err, cm.ErrorF("Could not store download in '%s'.", file))
+	}
+
+	return nil
+}
+
+// downloadVerifiedArchive downloads the asset `target` and the signed checksum
+// file `checksums` into `dir` and validates them.
+func downloadVerifiedArchive(
+	log cm.ILogContext,
+	target Asset,
+	checksums Checksums,
+	dir string,
+	publicPGP string) (archive ReleaseArchive, err error) {
+
+	err = os.MkdirAll(dir, cm.DefaultFileModeDirectory)
+	if err != nil {
+		return archive, cm.ErrorF("Could create dir '%s'.", dir)
+	}
+
+	archive = NewReleaseArchive(dir, target.Extension)
+
+	log.InfoF("Downloading checksum file '%s'.", checksums.File.URL)
+	err = downloadFile(checksums.File.URL, archive.Checksums)
+	if err == nil {
+		err = downloadFile(checksums.FileSignature.URL, archive.ChecksumsSignature)
+	}
+	if err != nil {
+		return
+	}
+
+	log.InfoF("Downloading file '%s'.", target.URL)
+	err = downloadFile(target.URL, archive.File)
+	if err != nil {
+		return
+	}
+
+	log.InfoF("Verify signature of checksum file and validate checksums.")
+	err = archive.Verify(publicPGP)
+
+	return
+}
+
+// downloadVerifiedAsset downloads the asset `target`, validates it with
+// the signed checksum file `checksums` and extracts it into `dir`.
+func downloadVerifiedAsset(
+	log cm.ILogContext,
+	target Asset,
+	checksums Checksums,
+	dir string,
+	publicPGP string) error {
+
+	archive, err := downloadVerifiedArchive(log, target, checksums, dir, publicPGP)
+	if err != nil {
+		return err
+	}
+
+	// Extract the file.
+	err = archive.Extract(dir)
+	if err != nil {
+		return cm.CombineErrors(err,
+			cm.ErrorF("Archive extraction from url '%s' failed.", target.URL))
+	}
+
+	return nil
+}
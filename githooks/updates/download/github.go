@@ -2,8 +2,6 @@ package download
 
 import (
 	"context"
-	"io"
-	"os"
 	"path"
 
 	cm "github.com/gabyx/githooks/githooks/common"
@@ -27,26 +25,46 @@ type GithubDeploySettings struct {
 }
 
 // Download downloads the version with `versionTag` to `dir` from a Github instance.
-func (s *GithubDeploySettings) Download(log cm.ILogContext, versionTag string, dir string) error {
-	return downloadGithub(log, s.Owner, s.Repository, versionTag, dir, s.PublicPGP)
+func (s *GithubDeploySettings) Download(log cm.ILogContext, versionTag string, platform Platform, dir string) error {
+	target, checksums, err := getGithubAsset(s.Owner, s.Repository, versionTag, platform)
+	if err != nil {
+		return err
+	}
+
+	return downloadVerifiedAsset(log, target, checksums, dir, s.PublicPGP)
 }
 
-// Downloads the Githooks release with tag `versionTag` and
-// extracts the matched asset into `dir`.
-// The assert matches the OS and architecture of the current runtime.
-func downloadGithub(
+// DownloadArchive downloads the release archive of `versionTag` into `dir` from a Github instance.
+func (s *GithubDeploySettings) DownloadArchive(
 	log cm.ILogContext,
+	versionTag string,
+	platform Platform,
+	dir string) (ReleaseArchive, error) {
+
+	target, checksums, err := getGithubAsset(s.Owner, s.Repository, versionTag, platform)
+	if err != nil {
+		return ReleaseArchive{}, err
+	}
+
+	return downloadVerifiedArchive(log, target, checksums, dir, s.PublicPGP)
+}
+
+// getGithubAsset gets the asset and the signed checksum file
+// of the Githooks release with tag `versionTag`.
+// The asset matches the OS and architecture of `platform`.
+func getGithubAsset(
 	owner string,
 	repo string,
 	versionTag string,
-	dir string,
-	publicPGP string) error {
+	platform Platform) (target Asset, checksums Checksums, err error) {
 
 	client := github.NewClient(nil)
 	rel, _, err := client.Repositories.GetReleaseByTag(context.Background(),
 		owner, repo, versionTag)
 	if err != nil {
-		return cm.CombineErrors(err, cm.Error("Failed to get release"))
+		err = cm.CombineErrors(err, cm.Error("Failed to get release"))
+
+		return
 	}
 
 	// Wrap into our list
@@ -58,56 +76,11 @@ func downloadGithub(
 				URL:      rel.Assets[i].GetBrowserDownloadURL()})
 	}
 
-	target, checksums, err := getGithooksAsset(assets)
+	target, checksums, err = getGithooksAsset(assets, platform)
 	if err != nil {
-		return cm.CombineErrors(err,
+		err = cm.CombineErrors(err,
 			cm.ErrorF("Could not select asset in repo '%s/%s' at tag '%s'.", owner, repo, versionTag))
 	}
 
-	log.InfoF("Verify signature of checksum file '%s'.", checksums.File.URL)
-	checksumData, err := verifyChecksumSignature(checksums, publicPGP)
-	if err != nil {
-		return cm.CombineErrors(err,
-			cm.ErrorF("Signature verification of update failed."+
-				"Something is fishy!"))
-	}
-
-	log.InfoF("Downloading file '%s'.", target.URL)
-	response, err := GetFile(target.URL)
-	if err != nil {
-		return cm.CombineErrors(err, cm.ErrorF("Could not download url '%s'.", target.URL))
-	}
-	defer response.Body.Close()
-
-	// Store into temp. file.
-	err = os.MkdirAll(dir, cm.DefaultFileModeDirectory)
-	if err != nil {
-		return cm.ErrorF("Could create dir '%s'.", dir)
-	}
-
-	temp, err := os.CreateTemp(dir, "*-"+target.FileName)
-	if err != nil {
-		return cm.ErrorF("Could open temp file '%s' for download.", target.FileName)
-	}
-	_, err = io.Copy(temp, response.Body)
-	if err != nil {
-		return cm.CombineErrors(err, cm.ErrorF("Could not store download in '%s'.", temp.Name()))
-	}
-	temp.Close()
-
-	// Validate checksum.
-	log.InfoF("Validate checksums.")
-	err = checkChecksum(temp.Name(), checksumData)
-	if err != nil {
-		return cm.CombineErrors(err, cm.ErrorF("Checksum validation failed."))
-	}
-
-	// Extract the file.
-	err = Extract(temp.Name(), target.Extension, dir)
-	if err != nil {
-		return cm.CombineErrors(err,
-			cm.ErrorF("Archive extraction from url '%s' failed.", target.URL))
-	}
-
-	return nil
+	return
 }
@@ -9,8 +9,8 @@ type HTTPDeploySettings struct {
 	// Path template string which can contain
 	// - `{{VersionTag}}` : The version tag to download.
 	// - `{{Version}}` : The version to download (removed prefix 'v' of `VersionTag`).
-	// - `{{Os}}` : The `runtime.GOOS` variable with the operating system.
-	// - `{{Arch}}` : The `runtime.GOARCH` for type architecture.
+	// - `{{Os}}` : The operating system (`runtime.GOOS`) of the platform.
+	// - `{{Arch}}` : The architecture (`runtime.GOARCH`) of the platform.
 	// pointing to the compressed archive of the Githooks binaries.
 	// In the same url directory need to be a checksum file
 	// `githooks.checksums`
@@ -26,8 +26,19 @@ type HTTPDeploySettings struct {
 
 // Download downloads the Githooks from a template URL and
 // extracts it into `dir`.
-func (s *HTTPDeploySettings) Download(log cm.ILogContext, versionTag string, dir string) error {
+func (s *HTTPDeploySettings) Download(log cm.ILogContext, versionTag string, platform Platform, dir string) error {
 	cm.Panic("Not implemented.")
 
 	return nil
 }
+
+// DownloadArchive downloads the release archive of `versionTag` from a template URL into `dir`.
+func (s *HTTPDeploySettings) DownloadArchive(
+	log cm.ILogContext,
+	versionTag string,
+	platform Platform,
+	dir string) (ReleaseArchive, error) {
+	cm.Panic("Not implemented.")
+
+	return ReleaseArchive{}, nil
+}
@@ -0,0 +1,237 @@
+package download
+
+import (
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gabyx/githooks/githooks/build"
+	cm "github.com/gabyx/githooks/githooks/common"
+	strs "github.com/gabyx/githooks/githooks/strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// IndexDeploySettings are deploy settings for a release index
+// (JSON or YAML) served over HTTP, e.g. by an artifact repository.
+type IndexDeploySettings struct {
+	// The URL of the release index.
+	// Relative URLs in the index are relative to this URL.
+	URL string
+
+	// If empty, the internal Githooks binary
+	// embedded PGP is taken from `.deploy.pgp`.
+	PublicPGP string
+}
+
+// ReleaseIndexAsset is the archive of the Githooks binaries
+// for one operating system and architecture.
+type ReleaseIndexAsset struct {
+	// The operating system (`runtime.GOOS`, `macos` is accepted for `darwin`).
+	OS string `yaml:"os"`
+	// The architecture (`runtime.GOARCH`).
+	Arch string `yaml:"arch"`
+	// The URL of the `.tar.gz` or `.zip` archive.
+	URL string `yaml:"url"`
+}
+
+// ReleaseIndexVersion is a released version in the release index.
+type ReleaseIndexVersion struct {
+	// The version tag, e.g. `v2.5.0`.
+	Tag string `yaml:"tag"`
+
+	// The URLs of the checksum file and its signature.
+	Checksums          string `yaml:"checksums"`
+	ChecksumsSignature string `yaml:"checksumsSignature"`
+
+	Assets []ReleaseIndexAsset `yaml:"assets"`
+}
+
+// ReleaseIndex is the release index listing all published versions.
+type ReleaseIndex struct {
+	Versions []ReleaseIndexVersion `yaml:"versions"`
+}
+
+// IVersionIndex is the interface for deploy settings
+// which can list the published versions.
+type IVersionIndex interface {
+	GetVersionTags() ([]string, error)
+}
+
+// LoadIndex downloads and parses the release index.
+// All relative URLs are resolved.
+func (s *IndexDeploySettings) LoadIndex() (index ReleaseIndex, err error) {
+	base, err := url.Parse(s.URL)
+	if err != nil {
+		return index, cm.CombineErrors(cm.ErrorF("Could not parse release index url '%s'.", s.URL), err)
+	}
+
+	response, err := GetFile(s.URL)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return
+	}
+
+	// JSON is valid YAML.
+	if err = yaml.Unmarshal(data, &index); err != nil {
+		return index, cm.CombineErrors(cm.ErrorF("Could not parse release index '%s'.", s.URL), err)
+	}
+
+	resolve := func(u *string) {
+		if ref, e := url.Parse(*u); e == nil {
+			*u = base.ResolveReference(ref).String()
+		} else {
+			err = cm.CombineErrors(err, cm.ErrorF("Could not parse url '%s' in release index.", *u))
+		}
+	}
+
+	for i := range index.Versions {
+		v := &index.Versions[i]
+
+		if strs.IsEmpty(v.Tag) || strs.IsEmpty(v.Checksums) || strs.IsEmpty(v.ChecksumsSignature) {
+			err = cm.CombineErrors(err,
+				cm.ErrorF("Version entry '%v' in release index '%s' needs a tag, checksums and a signature.",
+					i, s.URL))
+
+			continue
+		}
+
+		resolve(&v.Checksums)
+		resolve(&v.ChecksumsSignature)
+
+		for j := range v.Assets {
+			resolve(&v.Assets[j].URL)
+		}
+	}
+
+	return
+}
+
+// GetVersionTags gets all version tags published in the release index.
+func (s *IndexDeploySettings) GetVersionTags() ([]string, error) {
+	index, err := s.LoadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, 0, len(index.Versions))
+	for i := range index.Versions {
+		tags = append(tags, index.Versions[i].Tag)
+	}
+
+	return tags, nil
+}
+
+// getAsset gets the asset of the version matching the
+// operating system and architecture of `platform`.
+func (v *ReleaseIndexVersion) getAsset(platform Platform) (target Asset, err error) {
+	for i := range v.Assets {
+		a := &v.Assets[i]
+
+		matchesOS := a.OS == platform.OS || (a.OS == "macos" && platform.OS == "darwin")
+		if !matchesOS || a.Arch != platform.Arch {
+			continue
+		}
+
+		target = Asset{FileName: path.Base(a.URL), URL: a.URL}
+
+		switch {
+		case strings.HasSuffix(target.FileName, ".tar.gz"):
+			target.Extension = ".tar.gz"
+		case strings.HasSuffix(target.FileName, ".zip"):
+			target.Extension = ".zip"
+		default:
+			err = cm.ErrorF("Archive type of file '%s' not supported.", target.FileName)
+		}
+
+		return
+	}
+
+	err = cm.ErrorF("Could not find any asset for os: '%s' and arch: '%s'.",
+		platform.OS, platform.Arch)
+
+	return
+}
+
+// Download downloads the version with `versionTag` for `platform`
+// listed in the release index and extracts it into `dir`.
+func (s *IndexDeploySettings) Download(log cm.ILogContext, versionTag string, platform Platform, dir string) error {
+	target, checksums, publicPGP, err := s.getAsset(versionTag, platform)
+	if err != nil {
+		return err
+	}
+
+	return downloadVerifiedAsset(log, target, checksums, dir, publicPGP)
+}
+
+// DownloadArchive downloads the release archive of `versionTag` for `platform`
+// listed in the release index into `dir`.
+func (s *IndexDeploySettings) DownloadArchive(
+	log cm.ILogContext,
+	versionTag string,
+	platform Platform,
+	dir string) (ReleaseArchive, error) {
+
+	target, checksums, publicPGP, err := s.getAsset(versionTag, platform)
+	if err != nil {
+		return ReleaseArchive{}, err
+	}
+
+	return downloadVerifiedArchive(log, target, checksums, dir, publicPGP)
+}
+
+// getAsset gets the asset, the signed checksum file and the public key
+// of the version with `versionTag` for `platform` listed in the release index.
+func (s *IndexDeploySettings) getAsset(
+	versionTag string,
+	platform Platform) (target Asset, checksums Checksums, publicPGP string, err error) {
+
+	index, err := s.LoadIndex()
+	if err != nil {
+		err = cm.CombineErrors(err, cm.ErrorF("Could not load release index '%s'.", s.URL))
+
+		return
+	}
+
+	for i := range index.Versions {
+		v := &index.Versions[i]
+		if v.Tag != versionTag {
+			continue
+		}
+
+		target, err = v.getAsset(platform)
+		if err != nil {
+			err = cm.CombineErrors(err,
+				cm.ErrorF("Could not select asset in release index '%s' at tag '%s'.", s.URL, versionTag))
+
+			return
+		}
+
+		checksums = Checksums{
+			File:          Asset{FileName: path.Base(v.Checksums), URL: v.Checksums},
+			FileSignature: Asset{FileName: path.Base(v.ChecksumsSignature), URL: v.ChecksumsSignature}}
+
+		publicPGP = s.PublicPGP
+		if strs.IsEmpty(publicPGP) {
+			pgp, e := build.Asset("embedded/.deploy-pgp")
+			if e != nil {
+				err = cm.CombineErrors(e, cm.ErrorF("Could not get embedded deploy PGP."))
+
+				return
+			}
+			publicPGP = string(pgp)
+		}
+
+		return
+	}
+
+	err = cm.ErrorF("Version '%s' is not published in release index '%s'.", versionTag, s.URL)
+
+	return
+}
@@ -0,0 +1,173 @@
+package download
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"runtime"
+	"testing"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+// createArchive creates a `.tar.gz` archive with one file `name`.
+func createArchive(t *testing.T, name string, content string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	assert.Nil(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}))
+	_, err := tw.Write([]byte(content))
+	assert.Nil(t, err)
+
+	assert.Nil(t, tw.Close())
+	assert.Nil(t, gz.Close())
+
+	return buf.Bytes()
+}
+
+// createKey creates a PGP key and returns it with the armored public key.
+func createKey(t *testing.T) (*openpgp.Entity, string) {
+	entity, err := openpgp.NewEntity("Test", "", "test@example.com", nil)
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, entity.PrimaryKey.Serialize(w))
+	assert.Nil(t, w.Close())
+
+	return entity, buf.String()
+}
+
+func TestReleaseIndex(t *testing.T) {
+	entity, publicPGP := createKey(t)
+	_, otherPGP := createKey(t)
+
+	archive := createArchive(t, "githooks-cli", "binary")
+	hash := sha256.Sum256(archive)
+	checksums := []byte(hex.EncodeToString(hash[:]) + "  githooks-2.5.0-linux.amd64.tar.gz\n")
+
+	var sig bytes.Buffer
+	assert.Nil(t, openpgp.DetachSign(&sig, entity, bytes.NewReader(checksums), nil))
+
+	index := fmt.Sprintf(`{
+  "versions": [
+    {
+      "tag": "v2.5.0",
+      "checksums": "v2.5.0/githooks.checksums",
+      "checksumsSignature": "v2.5.0/githooks.checksums.sig",
+      "assets": [
+        { "os": "%[1]s", "arch": "%[2]s", "url": "v2.5.0/githooks.tar.gz" }
+      ]
+    },
+    {
+      "tag": "v2.6.0",
+      "checksums": "v2.6.0/githooks.checksums",
+      "checksumsSignature": "v2.6.0/githooks.checksums.sig",
+      "assets": [
+        { "os": "other", "arch": "%[2]s", "url": "v2.6.0/githooks.tar.gz" }
+      ]
+    }
+  ]
+}`, runtime.GOOS, runtime.GOARCH)
+
+	files := map[string][]byte{
+		"/releases/index.json":                    []byte(index),
+		"/releases/v2.5.0/githooks.tar.gz":        archive,
+		"/releases/v2.5.0/githooks.checksums":     checksums,
+		"/releases/v2.5.0/githooks.checksums.sig": sig.Bytes()}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, exists := files[r.URL.Path]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	log, err := cm.CreateLogContext(false)
+	assert.Nil(t, err)
+
+	settings := IndexDeploySettings{URL: server.URL + "/releases/index.json", PublicPGP: publicPGP}
+
+	idx, err := settings.LoadIndex()
+	assert.Nil(t, err)
+	assert.Equal(t, server.URL+"/releases/v2.5.0/githooks.tar.gz", idx.Versions[0].Assets[0].URL)
+
+	tags, err := settings.GetVersionTags()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"v2.5.0", "v2.6.0"}, tags)
+
+	dir, err := os.MkdirTemp("", "githooks-index")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	err = settings.Download(log, "v2.5.0", GetRuntimePlatform(), dir)
+	assert.Nil(t, err)
+	assert.True(t, cm.IsFile(path.Join(dir, "githooks-cli")))
+
+	stored, err := settings.DownloadArchive(log, "v2.5.0", GetRuntimePlatform(), path.Join(dir, "archive"))
+	assert.Nil(t, err)
+	found, err := FindReleaseArchive(path.Join(dir, "archive"))
+	assert.Nil(t, err)
+	assert.Equal(t, stored, found)
+	assert.Nil(t, found.Verify(publicPGP))
+	assert.NotNil(t, found.Verify(otherPGP))
+	assert.Nil(t, found.VerifyVersion("v2.5.0"))
+	assert.NotNil(t, found.VerifyVersion("v2.6.0"))
+
+	// No asset for this platform.
+	err = settings.Download(log, "v2.6.0", GetRuntimePlatform(), path.Join(dir, "a"))
+	assert.NotNil(t, err)
+
+	// Not published.
+	err = settings.Download(log, "v2.7.0", GetRuntimePlatform(), path.Join(dir, "b"))
+	assert.NotNil(t, err)
+
+	// Wrong public key.
+	settings.PublicPGP = otherPGP
+	err = settings.Download(log, "v2.5.0", GetRuntimePlatform(), path.Join(dir, "c"))
+	assert.NotNil(t, err)
+
+	// Wrong checksum.
+	settings.PublicPGP = publicPGP
+	files["/releases/v2.5.0/githooks.tar.gz"] = createArchive(t, "githooks-cli", "other")
+	err = settings.Download(log, "v2.5.0", GetRuntimePlatform(), path.Join(dir, "d"))
+	assert.NotNil(t, err)
+
+	// Incomplete version entry.
+	files["/releases/index.json"] = []byte("versions:\n  - tag: v2.5.0\n")
+	_, err = settings.GetVersionTags()
+	assert.NotNil(t, err)
+}
+
+func TestIndexDeploySettings(t *testing.T) {
+	dir, err := os.MkdirTemp("", "githooks-index")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	file := path.Join(dir, "deploy.yaml")
+	assert.Nil(t, StoreDeploySettings(file,
+		&IndexDeploySettings{URL: "https://example.com/index.yaml"}, nil))
+
+	settings, err := LoadDeploySettings(file)
+	assert.Nil(t, err)
+	assert.Equal(t, &IndexDeploySettings{URL: "https://example.com/index.yaml"}, settings)
+
+	_, ok := settings.(IVersionIndex)
+	assert.True(t, ok)
+}
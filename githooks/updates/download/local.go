@@ -5,7 +5,6 @@ import (
 	"html/template"
 	"os"
 	"path"
-	"runtime"
 	"strings"
 
 	cm "github.com/gabyx/githooks/githooks/common"
@@ -16,8 +15,8 @@ type LocalDeploySettings struct {
 	// Path template string which can contain
 	// - `{{VersionTag}}` : The version tag to download.
 	// - `{{Version}}` : The version to download (removed prefix 'v' of `VersionTag`).
-	// - `{{Os}}` : The `runtime.GOOS` variable with the operating system.
-	// - `{{Arch}}` : The `runtime.GOARCH` for type architecture.
+	// - `{{Os}}` : The operating system (`runtime.GOOS`) of the platform.
+	// - `{{Arch}}` : The architecture (`runtime.GOARCH`) of the platform.
 	// pointing to the compressed archive of the Githooks binaries.
 	// In the same directory need to be a checksum file
 	// `githooks.checksums`
@@ -33,12 +32,60 @@ type LocalDeploySettings struct {
 
 // Download downloads the Githooks from a template URL and
 // extracts it into `dir`.
-func (s *LocalDeploySettings) Download(log cm.ILogContext, versionTag string, dir string) error {
-	// Copy everything to director `dir`
+func (s *LocalDeploySettings) Download(log cm.ILogContext, versionTag string, platform Platform, dir string) error {
+	archive, err := s.getArchive(versionTag, platform)
+	if err != nil {
+		return err
+	}
+
+	// Extract the file.
+	err = archive.Extract(dir)
+	if err != nil {
+		return cm.CombineErrors(err,
+			cm.ErrorF("Archive extraction from '%s' failed.", archive.File))
+	}
+
+	return nil
+}
+
+// DownloadArchive copies the release archive of `versionTag`
+// together with its signed checksum file into `dir`.
+func (s *LocalDeploySettings) DownloadArchive(
+	log cm.ILogContext,
+	versionTag string,
+	platform Platform,
+	dir string) (ReleaseArchive, error) {
+
+	archive, err := s.getArchive(versionTag, platform)
+	if err != nil {
+		return ReleaseArchive{}, err
+	}
+
+	target := NewReleaseArchive(dir, archive.Extension)
+	err = os.MkdirAll(dir, cm.DefaultFileModeDirectory)
+	if err == nil {
+		err = cm.CopyFileOrDirectory(archive.File, target.File)
+	}
+	if err == nil {
+		err = cm.CopyFileOrDirectory(archive.Checksums, target.Checksums)
+	}
+	if err == nil {
+		err = cm.CopyFileOrDirectory(archive.ChecksumsSignature, target.ChecksumsSignature)
+	}
+	if err != nil {
+		return ReleaseArchive{}, cm.CombineErrors(err,
+			cm.ErrorF("Could not copy archive '%s' to '%s'.", archive.File, dir))
+	}
+
+	return target, nil
+}
+
+// getArchive gets the verified release archive of `versionTag` for `platform`.
+func (s *LocalDeploySettings) getArchive(versionTag string, platform Platform) (archive ReleaseArchive, err error) {
 	pathTmpl := template.Must(template.New("").Parse(s.PathTemplate))
 
 	var buf bytes.Buffer
-	err := pathTmpl.Execute(&buf, struct {
+	err = pathTmpl.Execute(&buf, struct {
 		VersionTag string
 		Version    string
 		Os         string
@@ -46,12 +93,14 @@ func (s *LocalDeploySettings) Download(log cm.ILogContext, versionTag string, di
 	}{
 		VersionTag: versionTag,
 		Version:    strings.TrimPrefix(versionTag, "v"),
-		Os:         runtime.GOOS,
-		Arch:       runtime.GOARCH,
+		Os:         platform.OS,
+		Arch:       platform.Arch,
 	})
 
 	if err != nil {
-		return cm.ErrorF("Could not format path template '%s'.", s.PathTemplate)
+		err = cm.ErrorF("Could not format path template '%s'.", s.PathTemplate)
+
+		return
 	}
 
 	targetFile := buf.String()
@@ -62,41 +111,20 @@ func (s *LocalDeploySettings) Download(log cm.ILogContext, versionTag string, di
 	case strings.HasSuffix(targetFile, ".zip"):
 		targetExtension = ".zip"
 	default:
-		return cm.ErrorF("Archive type of file '%s' not supporeted.", targetFile)
-	}
+		err = cm.ErrorF("Archive type of file '%s' not supporeted.", targetFile)
 
-	targetDir := path.Dir(targetFile)
-
-	// Read the checksumFile into memory
-	checksumFile := path.Join(targetDir, githooksChecksumFile)
-	checksumBytes, err := os.ReadFile(checksumFile)
-	if err != nil {
-		return err
+		return
 	}
 
-	checksumFileSignature := path.Join(targetDir, githooksChecksumSignatureFile)
-	checksumSigF, err := os.Open(checksumFileSignature)
-	if err != nil {
-		return err
-	}
-	err = cm.VerifyFile(bytes.NewReader(checksumBytes), checksumSigF, s.PublicPGP)
-	if err != nil {
-		return err
-	}
+	// The checksum file and its signature are in the same directory.
+	targetDir := path.Dir(targetFile)
+	archive = ReleaseArchive{
+		File:               targetFile,
+		Extension:          targetExtension,
+		Checksums:          path.Join(targetDir, githooksChecksumFile),
+		ChecksumsSignature: path.Join(targetDir, githooksChecksumSignatureFile)}
 
-	// Validate checksum.
-	err = checkChecksum(targetFile, checksumBytes)
-	if err != nil {
-		return cm.CombineErrors(err,
-			cm.ErrorF("Checksum validation failed."))
-	}
+	err = archive.Verify(s.PublicPGP)
 
-	// Extract the file.
-	err = Extract(targetFile, targetExtension, dir)
-	if err != nil {
-		return cm.CombineErrors(err,
-			cm.ErrorF("Archive extraction from '%s' failed.", targetFile))
-	}
-
-	return nil
+	return
 }
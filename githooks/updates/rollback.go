@@ -0,0 +1,193 @@
+package updates
+
+import (
+	"os"
+	"path"
+
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
+	"github.com/gabyx/githooks/githooks/hooks"
+	strs "github.com/gabyx/githooks/githooks/strings"
+)
+
+// RollbackState is the state of the previous version
+// kept in the rollback directory inside the install directory.
+type RollbackState struct {
+	// The previous version of Githooks.
+	BuildVersion string `yaml:"buildVersion"`
+	// The commit SHA of the release clone of the previous version.
+	CommitSHA string `yaml:"commitSHA"`
+
+	// The version of the file.
+	Version int `yaml:"version"`
+}
+
+// Version for RollbackState.
+// Version 1: Initial.
+const rollbackStateVersion int = 1
+
+func getRollbackStateFile(installDir string) string {
+	return path.Join(hooks.GetRollbackDir(installDir), ".rollback.yaml")
+}
+
+func getRollbackBinaryDir(installDir string) string {
+	return path.Join(hooks.GetRollbackDir(installDir), "bin")
+}
+
+// StoreRollbackState keeps the installed binaries and the commit SHA of the
+// release clone of the current installed version `buildVersion`
+// for a later rollback. Any previous rollback state is replaced.
+func StoreRollbackState(installDir string, buildVersion string) (err error) {
+	cloneDir := hooks.GetReleaseCloneDir(installDir)
+	commitSHA, err := git.NewCtxSanitizedAt(cloneDir).Get("rev-parse", git.HEAD)
+	if err != nil {
+		return cm.CombineErrors(cm.ErrorF("Could not get commit SHA of release clone '%s'.", cloneDir), err)
+	}
+
+	binDir := hooks.GetBinaryDir(installDir)
+	binaries, err := cm.GetAllFiles(binDir)
+	if err != nil {
+		return cm.CombineErrors(cm.ErrorF("Could not get binaries in '%s'.", binDir), err)
+	}
+
+	rollbackDir := hooks.GetRollbackDir(installDir)
+	rollbackBinDir := getRollbackBinaryDir(installDir)
+
+	if err = os.RemoveAll(rollbackDir); err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(rollbackBinDir, cm.DefaultFileModeDirectory); err != nil {
+		return
+	}
+
+	for _, binary := range binaries {
+		dest := path.Join(rollbackBinDir, path.Base(binary))
+		if err = cm.CopyFileOrDirectory(binary, dest); err != nil {
+			return cm.CombineErrors(cm.ErrorF("Could not copy '%s' to '%s'.", binary, dest), err)
+		}
+	}
+
+	return cm.StoreYAML(getRollbackStateFile(installDir),
+		&RollbackState{
+			BuildVersion: buildVersion,
+			CommitSHA:    commitSHA,
+			Version:      rollbackStateVersion})
+}
+
+// LoadRollbackState loads the rollback state
+// and reports if a previous version is kept.
+func LoadRollbackState(installDir string) (state RollbackState, exists bool, err error) {
+	file := getRollbackStateFile(installDir)
+	if !cm.IsFile(file) {
+		return
+	}
+
+	if err = cm.LoadYAML(file, &state); err != nil {
+		return
+	}
+
+	if state.Version < 1 || state.Version > rollbackStateVersion {
+		err = cm.ErrorF(
+			"File '%s' has version '%v'. "+
+				"This version of Githooks only supports version >= 1 and <= '%v'.",
+			file, state.Version, rollbackStateVersion)
+
+		return
+	}
+
+	if strs.IsEmpty(state.CommitSHA) || !cm.IsDirectory(getRollbackBinaryDir(installDir)) {
+		err = cm.ErrorF("Rollback state in '%s' is corrupt.", hooks.GetRollbackDir(installDir))
+
+		return
+	}
+
+	exists = true
+
+	return
+}
+
+// Rollback restores the binaries and the release clone of the previous version
+// kept in the rollback directory. Binaries which are not part of the previous version
+// are removed. The version tag `rolledBackTag` is recorded to be skipped by
+// update checks. The rollback state is removed afterwards.
+func Rollback(installDir string, rolledBackTag string) (state RollbackState, err error) {
+	state, exists, err := LoadRollbackState(installDir)
+	if err != nil {
+		return
+	} else if !exists {
+		err = cm.ErrorF("No previous version is kept in '%s'.", hooks.GetRollbackDir(installDir))
+
+		return
+	}
+
+	tempDir, err := hooks.AssertTemporaryDir(installDir)
+	if err != nil {
+		return
+	}
+
+	binaries, err := cm.GetAllFiles(getRollbackBinaryDir(installDir))
+	if err != nil {
+		return
+	}
+
+	binDir := hooks.GetBinaryDir(installDir)
+	installed, err := cm.GetAllFiles(binDir)
+	if err != nil {
+		return
+	}
+
+	restored := strs.NewStringSet(len(binaries))
+	for _, binary := range binaries {
+		restored.Insert(path.Base(binary))
+	}
+
+	for _, binary := range installed {
+		if restored.Exists(path.Base(binary)) {
+			continue
+		}
+
+		// Move the (maybe running) executables out of the way.
+		if err = os.Rename(binary, cm.GetTempPath(tempDir, "-"+path.Base(binary))); err != nil {
+			return state, cm.CombineErrors(cm.ErrorF("Could not remove '%s'.", binary), err)
+		}
+	}
+
+	for _, binary := range binaries {
+		dest := path.Join(binDir, path.Base(binary))
+		// Move the running executables out of the way.
+		if err = cm.CopyFileWithBackup(binary, dest, tempDir, false); err != nil {
+			return state, cm.CombineErrors(cm.ErrorF("Could not restore '%s'.", dest), err)
+		}
+	}
+
+	if err = ResetUpdates(hooks.GetReleaseCloneDir(installDir), state.CommitSHA); err != nil {
+		return
+	}
+
+	if strs.IsNotEmpty(rolledBackTag) {
+		if err = SetSkippedUpdateVersion(rolledBackTag, false); err != nil {
+			return
+		}
+	}
+
+	err = os.RemoveAll(hooks.GetRollbackDir(installDir))
+
+	return
+}
+
+// SetSkippedUpdateVersion sets the version tag `tag` which is skipped by update checks.
+func SetSkippedUpdateVersion(tag string, reset bool) error {
+	gitx := git.NewCtx()
+
+	if reset {
+		return gitx.UnsetConfig(hooks.GitCKUpdateSkippedVersion, git.GlobalScope)
+	}
+
+	return gitx.SetConfig(hooks.GitCKUpdateSkippedVersion, tag, git.GlobalScope)
+}
+
+// GetSkippedUpdateVersion gets the version tag which is skipped by update checks.
+func GetSkippedUpdateVersion(gitx *git.Context) string {
+	return gitx.GetConfig(hooks.GitCKUpdateSkippedVersion, git.GlobalScope)
+}
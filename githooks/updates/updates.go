@@ -10,6 +10,7 @@ import (
 	"github.com/gabyx/githooks/githooks/hooks"
 	"github.com/gabyx/githooks/githooks/prompt"
 	strs "github.com/gabyx/githooks/githooks/strings"
+	"github.com/gabyx/githooks/githooks/updates/download"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/go-version"
@@ -32,6 +33,8 @@ type ReleaseStatus struct {
 	UpdateVersion     *version.Version // The update version.
 	UpdateInfo        []string         // The update info read from the commit.
 
+	Channel *UpdateChannel // The update channel used, `nil` if none.
+
 	Branch       string
 	RemoteBranch string
 }
@@ -76,7 +79,8 @@ func getNewUpdateCommit(
 	gitx *git.Context,
 	firstSHA string,
 	lastSHA string,
-	usePreRelease bool) (commitF string, tagF string, versionF *version.Version, infoF []string, err error) {
+	usePreRelease bool,
+	channel *UpdateChannel) (commitF string, tagF string, versionF *version.Version, infoF []string, err error) {
 
 	// Get all commits in (firstSHA, lastSHA]
 	commits, err := gitx.GetCommits(firstSHA, lastSHA)
@@ -84,6 +88,9 @@ func getNewUpdateCommit(
 		return
 	}
 
+	// The version rolled back last is skipped.
+	skippedTag := GetSkippedUpdateVersion(gitx)
+
 	// Iterate from (firstSHA, lastSHA], the list is reversed.
 	for i := range commits {
 
@@ -98,8 +105,11 @@ func getNewUpdateCommit(
 			return
 		case version == nil || strs.IsEmpty(tag):
 			continue // no version tag on this commit
-		case !usePreRelease && strs.IsNotEmpty(version.Prerelease()):
-			// Skipping prerelease version
+		case tag == skippedTag:
+			continue // rolled back version
+		}
+
+		if !isUpdateCandidate(version, usePreRelease, channel) {
 			continue
 		}
 
@@ -112,6 +122,22 @@ func getNewUpdateCommit(
 			return
 		}
 
+		noSkip := unskipTrailerRe.MatchString(mess)
+
+		if rolledOut, e := isRolledOut(gitx, tag, channel); e != nil {
+			err = e
+
+			return
+		} else if !rolledOut {
+			if noSkip {
+				// We stop before this commit since this update cannot be skipped
+				// and is not yet rolled out.
+				break
+			}
+
+			continue
+		}
+
 		// We have a valid new version on commit 'commit'
 		commitF = commit
 		tagF = tag
@@ -123,7 +149,7 @@ func getNewUpdateCommit(
 			infoF = append(infoF, strs.Fmt("%s : ", version.String())+strings.TrimSpace(info[1]))
 		}
 
-		if unskipTrailerRe.MatchString(mess) {
+		if noSkip {
 			// We stop at this commit since this update cannot be skipped!
 			break
 		}
@@ -132,6 +158,44 @@ func getNewUpdateCommit(
 	return
 }
 
+// isUpdateCandidate reports if the version `ver` is
+// considered for updates in the update channel `channel` (can be `nil`).
+func isUpdateCandidate(
+	ver *version.Version,
+	usePreRelease bool,
+	channel *UpdateChannel) bool {
+
+	if channel != nil && channel.PreRelease {
+		usePreRelease = true
+	}
+
+	switch {
+	case !usePreRelease && strs.IsNotEmpty(ver.Prerelease()):
+		// Skipping prerelease version
+		return false
+	case channel == nil:
+		return true
+	default:
+		// Skipping version not in the update channel.
+		return channel.Includes(ver)
+	}
+}
+
+// isRolledOut reports if the version with tag `tag` is already
+// rolled out to this machine in the update channel `channel` (can be `nil`).
+func isRolledOut(gitx *git.Context, tag string, channel *UpdateChannel) (bool, error) {
+	if channel == nil || channel.Rollout == nil {
+		return true, nil
+	}
+
+	releaseTime, err := getReleaseTime(gitx, tag)
+	if err != nil {
+		return false, err
+	}
+
+	return channel.IsRolledOut(tag, releaseTime), nil
+}
+
 // RemoteCheckAction is the action type for the remote check.
 type RemoteCheckAction string
 
@@ -152,7 +216,8 @@ func FetchUpdates(
 	tag string,
 	checkRemote bool,
 	checkRemoteAction RemoteCheckAction,
-	usePreRelease bool) (status ReleaseStatus, err error) {
+	usePreRelease bool,
+	channel *UpdateChannel) (status ReleaseStatus, err error) {
 
 	cm.AssertOrPanic(strs.IsNotEmpty(cloneDir))
 
@@ -277,7 +342,7 @@ func FetchUpdates(
 
 	resetRemoteTo := ""
 	remoteBranch := DefaultRemote + "/" + branch
-	status, err = getStatus(gitx, url, DefaultRemote, branch, remoteBranch, usePreRelease)
+	status, err = getStatus(gitx, url, DefaultRemote, branch, remoteBranch, usePreRelease, channel)
 
 	status.IsNewClone = isNewClone
 	if status.IsUpdateAvailable {
@@ -298,7 +363,10 @@ func FetchUpdates(
 }
 
 // GetStatus returns the status of the release clone.
-func GetStatus(cloneDir string, checkRemote, skipPrerelease bool) (status ReleaseStatus, err error) {
+func GetStatus(
+	cloneDir string,
+	checkRemote, skipPrerelease bool,
+	channel *UpdateChannel) (status ReleaseStatus, err error) {
 
 	gitx := git.NewCtxSanitizedAt(cloneDir)
 
@@ -335,7 +403,7 @@ func GetStatus(cloneDir string, checkRemote, skipPrerelease bool) (status Releas
 
 	remoteBranch := DefaultRemote + "/" + branch
 
-	return getStatus(gitx, url, DefaultRemote, branch, remoteBranch, skipPrerelease)
+	return getStatus(gitx, url, DefaultRemote, branch, remoteBranch, skipPrerelease, channel)
 }
 
 func getStatus(
@@ -344,7 +412,8 @@ func getStatus(
 	remoteName string,
 	branch string,
 	remoteBranch string,
-	usePreRelease bool) (status ReleaseStatus, err error) {
+	usePreRelease bool,
+	channel *UpdateChannel) (status ReleaseStatus, err error) {
 
 	localSHA, err := gitx.Get("rev-parse", branch)
 	if err != nil {
@@ -371,7 +440,7 @@ func getStatus(
 		// - Skip prerelease versions
 		// - also never skip annotated (Git trailers) "non-skip" versions.
 		updateCommit, updateTag, updateVersion, updateInfo, err =
-			getNewUpdateCommit(gitx, localSHA, remoteSHA, usePreRelease)
+			getNewUpdateCommit(gitx, localSHA, remoteSHA, usePreRelease, channel)
 
 		if err != nil {
 			return
@@ -393,6 +462,8 @@ func getStatus(
 		UpdateTag:         updateTag,
 		UpdateInfo:        updateInfo,
 
+		Channel: channel,
+
 		Branch:       branch,
 		RemoteBranch: remoteBranch}
 
@@ -483,14 +554,28 @@ func RunUpdate(
 		return
 	}
 
+	channel, err := LoadUpdateChannel(git.NewCtxSanitized(), download.GetDeploySettingsFile(installDir), "")
+	if err != nil {
+		err = cm.CombineErrors(cm.Error("Could not load update channel."), err)
+
+		return
+	}
+
 	cloneDir := hooks.GetReleaseCloneDir(installDir)
-	status, err := FetchUpdates(cloneDir, "", "", build.BuildTag, true, ErrorOnWrongRemote, usePreRelease)
+	status, err := FetchUpdates(cloneDir, "", "", build.BuildTag, true, ErrorOnWrongRemote, usePreRelease, channel)
 	if err != nil {
 		err = cm.CombineErrors(cm.Error("Could not fetch updates."), err)
 
 		return
 	}
 
+	err = RestrictToVersionIndex(cloneDir, &status, download.GetDeploySettingsFile(installDir), usePreRelease)
+	if err != nil {
+		err = cm.CombineErrors(cm.Error("Could not check published versions."), err)
+
+		return
+	}
+
 	updateAvailable = status.IsUpdateAvailable
 
 	if status.IsUpdateAvailable {
@@ -549,12 +634,23 @@ func DefaultAcceptUpdateCallback(
 		log.DebugF("Fetch status: '%v'", status)
 		cm.DebugAssert(status.IsUpdateAvailable, "Wrong input.")
 
+		if status.Channel != nil && !status.Channel.Includes(status.UpdateVersion) {
+			log.InfoF("Skipping update to version '%s' which is not in update channel '%s'.",
+				status.UpdateVersion.String(), status.Channel.Name)
+
+			return false
+		}
+
 		versionText := strs.Fmt(
 			"Current Version: '%s'\n"+
 				"New Version: '%s'",
 			build.GetBuildVersion(),
 			status.UpdateVersion.String())
 
+		if status.Channel != nil {
+			versionText += strs.Fmt("\nUpdate Channel: '%s'", status.Channel.Name)
+		}
+
 		isMajorUpdate := build.GetBuildVersion().Segments()[0] < status.UpdateVersion.Segments()[0]
 
 		promptHint := "(Yes/no)"
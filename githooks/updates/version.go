@@ -0,0 +1,180 @@
+package updates
+
+import (
+	"github.com/gabyx/githooks/githooks/build"
+	cm "github.com/gabyx/githooks/githooks/common"
+	"github.com/gabyx/githooks/githooks/git"
+	strs "github.com/gabyx/githooks/githooks/strings"
+	"github.com/gabyx/githooks/githooks/updates/download"
+
+	"github.com/hashicorp/go-version"
+)
+
+// FindVersionTag finds the latest version tag in the release clone
+// `cloneDir` which fulfills the version constraints `constraints`.
+// Returns an empty tag if no version matches.
+func FindVersionTag(
+	cloneDir string,
+	constraints version.Constraints,
+	usePreRelease bool) (tag string, ver *version.Version, err error) {
+
+	gitx := git.NewCtxSanitizedAt(cloneDir)
+
+	tags, err := gitx.GetSplit("tag", "--list", "v*")
+	if err != nil {
+		return
+	}
+
+	for _, t := range tags {
+		v, e := version.NewVersion(t)
+
+		switch {
+		case e != nil:
+			continue
+		case !usePreRelease && strs.IsNotEmpty(v.Prerelease()):
+			continue
+		case !constraints.Check(v):
+			continue
+		case ver == nil || v.GreaterThan(ver):
+			tag = t
+			ver = v
+		}
+	}
+
+	return
+}
+
+// SetUpdateTo sets the update in the status `status` of the release clone
+// `cloneDir` to the version tag `tag` which can also be older than the current version.
+// The remote branch is reset to the commit of `tag`.
+func SetUpdateTo(cloneDir string, status *ReleaseStatus, tag string) (err error) {
+	gitx := git.NewCtxSanitizedAt(cloneDir)
+
+	ver, err := version.NewVersion(tag)
+	if err != nil {
+		return cm.CombineErrors(cm.ErrorF("Tag '%s' is not a version.", tag), err)
+	}
+
+	commitSHA, err := gitx.Get("rev-parse", tag+"^{commit}")
+	if err != nil {
+		return cm.CombineErrors(cm.ErrorF("Version tag '%s' does not exist in '%s'.", tag, cloneDir), err)
+	}
+
+	status.IsUpdateAvailable = commitSHA != status.LocalCommitSHA
+	status.UpdateCommitSHA = commitSHA
+	status.UpdateTag = tag
+	status.UpdateVersion = ver
+	status.UpdateInfo = nil
+
+	if !status.IsUpdateAvailable {
+		return
+	}
+
+	err = gitx.Check("update-ref", "refs/remotes/"+status.RemoteBranch, commitSHA)
+	if err != nil {
+		return
+	}
+
+	status.RemoteCommitSHA = commitSHA
+
+	return
+}
+
+// ResetUpdates resets the local branch in the Githooks clone directory
+// to the commit `commitSHA`. In contrast to `MergeUpdates` this
+// also works for older versions.
+func ResetUpdates(cloneDir string, commitSHA string) error {
+	if !cm.IsDirectory(cloneDir) {
+		return cm.ErrorF("Clone directory '%s' does not exist.", cloneDir)
+	}
+
+	err := git.NewCtxSanitizedAt(cloneDir).Check("reset", "--hard", commitSHA)
+	if err != nil {
+		return cm.CombineErrors(
+			cm.ErrorF("Could not reset release clone '%s' to '%s'.", cloneDir, commitSHA), err)
+	}
+
+	return nil
+}
+
+// RestrictToVersionIndex restricts the update in the status `status` of the release
+// clone `cloneDir` to the latest version published in the release index, if the
+// deploy settings `deploySettingsFile` provide one.
+func RestrictToVersionIndex(
+	cloneDir string,
+	status *ReleaseStatus,
+	deploySettingsFile string,
+	usePreRelease bool) error {
+
+	if !status.IsUpdateAvailable || !cm.IsFile(deploySettingsFile) {
+		return nil
+	}
+
+	settings, err := download.LoadDeploySettings(deploySettingsFile)
+	if err != nil {
+		return err
+	}
+
+	index, ok := settings.(download.IVersionIndex)
+	if !ok {
+		return nil
+	}
+
+	tags, err := index.GetVersionTags()
+	if err != nil {
+		return err
+	}
+
+	gitx := git.NewCtxSanitizedAt(cloneDir)
+	current := build.GetBuildVersion()
+
+	var updateTag string
+	var updateVersion *version.Version
+
+	for _, tag := range tags {
+		if tag == status.UpdateTag {
+			return nil // The update is published.
+		}
+
+		ver, e := version.NewVersion(tag)
+		if e != nil || !ver.GreaterThan(current) || ver.GreaterThan(status.UpdateVersion) ||
+			(updateVersion != nil && !ver.GreaterThan(updateVersion)) {
+			continue
+		}
+
+		// The update must be reachable by a fast-forward.
+		if reachable, e := git.IsRefReachable(gitx, status.UpdateCommitSHA, tag); e != nil || !reachable {
+			continue
+		}
+
+		if !isUpdateCandidate(ver, usePreRelease, status.Channel) {
+			continue
+		}
+
+		if rolledOut, e := isRolledOut(gitx, tag, status.Channel); e != nil {
+			return e
+		} else if rolledOut {
+			updateTag = tag
+			updateVersion = ver
+		}
+	}
+
+	if strs.IsNotEmpty(updateTag) {
+		return SetUpdateTo(cloneDir, status, updateTag)
+	}
+
+	// No update is published.
+	err = gitx.Check("update-ref", "refs/remotes/"+status.RemoteBranch, status.LocalCommitSHA)
+	if err != nil {
+		return err
+	}
+
+	status.IsUpdateAvailable = false
+	status.RemoteCommitSHA = status.LocalCommitSHA
+	status.UpdateCommitSHA = ""
+	status.UpdateTag = ""
+	status.UpdateVersion = nil
+	status.UpdateInfo = nil
+
+	return nil
+}
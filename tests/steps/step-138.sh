@@ -0,0 +1,92 @@
+#!/usr/bin/env bash
+# Test:
+#   Direct runner execution: verify signatures of shared hooks
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+if ! command -v ssh-keygen &>/dev/null; then
+    echo "ssh-keygen is not available."
+    exit 249
+fi
+
+acceptAllTrustPrompts || exit 1
+
+git config --global githooks.testingTreatFileProtocolAsRemote "true"
+
+# Setup SSH signing keys.
+mkdir -p "$GH_TEST_TMP/keys" &&
+    ssh-keygen -q -t ed25519 -N "" -C "signer@test.com" -f "$GH_TEST_TMP/keys/signer" &&
+    echo "signer@test.com $(cat "$GH_TEST_TMP/keys/signer.pub")" >"$GH_TEST_TMP/keys/allowed-signers" &&
+    git config --global gpg.format ssh &&
+    git config --global gpg.ssh.allowedSignersFile "$GH_TEST_TMP/keys/allowed-signers" ||
+    exit 1
+
+mkdir -p "$GH_TEST_TMP/shared/hooks-138.git/pre-commit" &&
+    echo "echo 'From shared hook' >> '$GH_TEST_TMP/test-138.out'" \
+        >"$GH_TEST_TMP/shared/hooks-138.git/pre-commit/say-hello" &&
+    cd "$GH_TEST_TMP/shared/hooks-138.git" &&
+    git init &&
+    git add . &&
+    git commit -m 'Initial commit' ||
+    exit 1
+
+mkdir -p "$GH_TEST_TMP/test138" &&
+    cd "$GH_TEST_TMP/test138" &&
+    git init || exit 1
+
+git config --local githooks.shared "file://$GH_TEST_TMP/shared/hooks-138.git" &&
+    "$GH_TEST_BIN/cli" config verify-shared-signature --set commit "signer@test.com" &&
+    "$GH_TEST_BIN/cli" shared update ||
+    exit 1
+
+# Unsigned commit must be refused.
+if "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit; then
+    echo "! Expected the unsigned shared hooks to be refused"
+    exit 1
+fi
+
+if [ -f "$GH_TEST_TMP/test-138.out" ]; then
+    echo "! Expected the unsigned shared hook not to be run"
+    exit 1
+fi
+
+# Skipping untrusted hooks continues.
+"$GH_TEST_BIN/cli" config skip-untrusted-hooks --enable || exit 1
+if ! OUT=$("$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit 2>&1) ||
+    ! echo "$OUT" | grep -q "could not be verified"; then
+    echo "! Expected the unsigned shared hooks to be skipped"
+    echo "$OUT"
+    exit 1
+fi
+"$GH_TEST_BIN/cli" config skip-untrusted-hooks --reset || exit 1
+
+# Sign the commit in the shared repository.
+cd "$GH_TEST_TMP/shared/hooks-138.git" &&
+    git commit --amend --no-edit -S"$GH_TEST_TMP/keys/signer" &&
+    cd "$GH_TEST_TMP/test138" &&
+    "$GH_TEST_BIN/cli" shared purge &&
+    "$GH_TEST_BIN/cli" shared update ||
+    exit 1
+
+if ! "$GH_TEST_BIN/cli" shared list | grep -q "verified"; then
+    echo "! Expected the shared repository to be verified"
+    exit 1
+fi
+
+"$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit || exit 1
+
+if ! grep -q 'From shared hook' "$GH_TEST_TMP/test-138.out"; then
+    echo "! The signed shared hook was not run"
+    exit 1
+fi
+
+# A key which is not allowed must be refused.
+"$GH_TEST_BIN/cli" config verify-shared-signature --set commit "other@test.com" || exit 1
+if "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit; then
+    echo "! Expected the shared hooks signed by a not allowed key to be refused"
+    exit 1
+fi
+
+"$GH_TEST_BIN/cli" config verify-shared-signature --reset || exit 1
@@ -0,0 +1,77 @@
+#!/usr/bin/env bash
+# Test:
+#   Direct runner execution: offline mode skips shared hooks updates
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+acceptAllTrustPrompts || exit 1
+
+git config --global githooks.testingTreatFileProtocolAsRemote "true"
+
+mkdir -p "$GH_TEST_TMP/shared/hooks-139.git/post-merge" &&
+    echo "echo 'From shared hook' >> '$GH_TEST_TMP/test-139.out'" \
+        >"$GH_TEST_TMP/shared/hooks-139.git/post-merge/say-hello" &&
+    cd "$GH_TEST_TMP/shared/hooks-139.git" &&
+    git init &&
+    git add . &&
+    git commit -m 'Initial commit' ||
+    exit 1
+
+mkdir -p "$GH_TEST_TMP/test139" &&
+    cd "$GH_TEST_TMP/test139" &&
+    git init || exit 1
+
+mkdir -p .githooks &&
+    echo "urls: - file://$GH_TEST_TMP/shared/hooks-139.git" >.githooks/.shared.yaml || exit 1
+
+# Offline mode over the environment.
+if ! OUT=$(GITHOOKS_OFFLINE=1 GITHOOKS_SKIP_NON_EXISTING_SHARED_HOOKS=true \
+    "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/post-merge 2>&1) ||
+    echo "$OUT" | grep -q "Updating shared hooks from" ||
+    ! echo "$OUT" | grep -q "offline mode"; then
+    echo "! Expected the shared hooks update to be skipped"
+    echo "$OUT"
+    exit 1
+fi
+
+if [ "$(echo "$OUT" | grep -c "offline mode")" != "1" ]; then
+    echo "! Expected the offline notice to be shown once"
+    echo "$OUT"
+    exit 1
+fi
+
+if [ -f "$GH_TEST_TMP/test-139.out" ]; then
+    echo "! Expected the shared hook not to exist"
+    exit 1
+fi
+
+# Offline mode over the config.
+"$GH_TEST_BIN/cli" config offline --enable || exit 1
+"$GH_TEST_BIN/cli" config offline --print | grep -q "enabled" || exit 1
+
+if ! "$GH_TEST_BIN/cli" shared update 2>&1 | grep -q "offline mode"; then
+    echo "! Expected the shared update to be skipped"
+    exit 1
+fi
+
+if "$GH_TEST_BIN/cli" shared list | grep -q "active"; then
+    echo "! Expected the shared repository not to be cloned"
+    exit 1
+fi
+
+"$GH_TEST_BIN/cli" config offline --reset || exit 1
+
+# Online again.
+if ! OUT=$("$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/post-merge 2>&1) ||
+    ! echo "$OUT" | grep -q "Updating shared hooks from"; then
+    echo "! Expected the shared hooks to be updated"
+    echo "$OUT"
+    exit 1
+fi
+
+if ! grep -q 'From shared hook' "$GH_TEST_TMP/test-139.out"; then
+    echo "! The shared hook was not run"
+    exit 1
+fi
@@ -0,0 +1,86 @@
+#!/usr/bin/env bash
+# Test:
+#   Shared hooks: status reports drift of shared repositories
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+acceptAllTrustPrompts || exit 1
+
+if ! command -v jq &>/dev/null; then
+    echo "jq is not available."
+    exit 249
+fi
+
+git config --global githooks.testingTreatFileProtocolAsRemote "true"
+
+mkdir -p "$GH_TEST_TMP/shared/hooks-140.git/pre-commit" &&
+    echo "echo 'From shared hook'" >"$GH_TEST_TMP/shared/hooks-140.git/pre-commit/say-hello" &&
+    cd "$GH_TEST_TMP/shared/hooks-140.git" &&
+    git init &&
+    git add . &&
+    git commit -m 'Initial commit' ||
+    exit 1
+
+mkdir -p "$GH_TEST_TMP/test140" &&
+    cd "$GH_TEST_TMP/test140" &&
+    git init || exit 1
+
+URL="file://$GH_TEST_TMP/shared/hooks-140.git"
+
+"$GH_TEST_BIN/cli" shared add --local "$URL" || exit 1
+
+OUT=$("$GH_TEST_BIN/cli" shared status --local --json) || exit 1
+if [ "$(echo "$OUT" | jq -r '.[0].state')" != "pending" ]; then
+    echo "! Expected the shared repository to be pending"
+    echo "$OUT"
+    exit 1
+fi
+
+"$GH_TEST_BIN/cli" shared update || exit 1
+
+OUT=$("$GH_TEST_BIN/cli" shared status --local) || exit 1
+if ! echo "$OUT" | grep -q "drift: no"; then
+    echo "! Expected no drift"
+    echo "$OUT"
+    exit 1
+fi
+
+# New commit in the remote.
+cd "$GH_TEST_TMP/shared/hooks-140.git" &&
+    echo "echo 'Other'" >pre-commit/other &&
+    git add . &&
+    git commit -m 'Second commit' &&
+    cd "$GH_TEST_TMP/test140" ||
+    exit 1
+
+OUT=$("$GH_TEST_BIN/cli" shared status --local --json) || exit 1
+if [ "$(echo "$OUT" | jq -r '.[0].behind')" != "0" ]; then
+    echo "! Expected not to be behind without fetching"
+    echo "$OUT"
+    exit 1
+fi
+
+OUT=$("$GH_TEST_BIN/cli" shared status --local --fetch --json) || exit 1
+if [ "$(echo "$OUT" | jq -r '.[0].behind')" != "1" ] ||
+    [ "$(echo "$OUT" | jq -r '.[0].fetched')" != "true" ]; then
+    echo "! Expected to be behind by one commit"
+    echo "$OUT"
+    exit 1
+fi
+
+# Local modifications and wrong remote.
+CLONE=$(echo "$OUT" | jq -r '.[0].repositoryDir')
+echo "modified" >>"$CLONE/pre-commit/say-hello" &&
+    git -C "$CLONE" config remote.origin.url "file://$GH_TEST_TMP/other.git" ||
+    exit 1
+
+OUT=$("$GH_TEST_BIN/cli" shared status --local) || exit 1
+if ! echo "$OUT" | grep -q "drift: yes" ||
+    ! echo "$OUT" | grep -q "local modifications: '1' files" ||
+    ! echo "$OUT" | grep -q "remote url: 'file://$GH_TEST_TMP/other.git' (expected: '$URL')"; then
+    echo "! Expected drift with local modifications and a wrong remote"
+    echo "$OUT"
+    exit 1
+fi
@@ -0,0 +1,60 @@
+#!/usr/bin/env bash
+# Test:
+#   Shared hooks: garbage collect unreferenced shared repositories
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+acceptAllTrustPrompts || exit 1
+
+git config --global githooks.testingTreatFileProtocolAsRemote "true"
+
+for name in used unused; do
+    mkdir -p "$GH_TEST_TMP/shared/hooks-141-$name.git/pre-commit" &&
+        echo "echo '$name'" >"$GH_TEST_TMP/shared/hooks-141-$name.git/pre-commit/say-hello" &&
+        git -C "$GH_TEST_TMP/shared/hooks-141-$name.git" init &&
+        git -C "$GH_TEST_TMP/shared/hooks-141-$name.git" add . &&
+        git -C "$GH_TEST_TMP/shared/hooks-141-$name.git" commit -m 'Initial commit' ||
+        exit 1
+done
+
+mkdir -p "$GH_TEST_TMP/test141" &&
+    cd "$GH_TEST_TMP/test141" &&
+    git init || exit 1
+
+USED="file://$GH_TEST_TMP/shared/hooks-141-used.git"
+UNUSED="file://$GH_TEST_TMP/shared/hooks-141-unused.git"
+
+"$GH_TEST_BIN/cli" shared add --local "$USED" &&
+    "$GH_TEST_BIN/cli" shared add --local "$UNUSED" &&
+    "$GH_TEST_BIN/cli" shared update || exit 1
+
+USED_DIR=$("$GH_TEST_BIN/cli" shared root-from-url "$USED")
+UNUSED_DIR=$("$GH_TEST_BIN/cli" shared root-from-url "$UNUSED")
+
+if [ ! -d "$USED_DIR" ] || [ ! -d "$UNUSED_DIR" ]; then
+    echo "! Expected both shared repositories to be cloned"
+    exit 1
+fi
+
+"$GH_TEST_BIN/cli" shared remove --local "$UNUSED" || exit 1
+
+OUT=$("$GH_TEST_BIN/cli" shared gc --dry-run) || exit 1
+if ! echo "$OUT" | grep -q "$UNUSED_DIR" || echo "$OUT" | grep -q "$USED_DIR"; then
+    echo "! Expected only the unreferenced shared repository to be listed"
+    echo "$OUT"
+    exit 1
+fi
+
+if [ ! -d "$UNUSED_DIR" ]; then
+    echo "! Expected dry-run not to remove anything"
+    exit 1
+fi
+
+"$GH_TEST_BIN/cli" shared gc || exit 1
+
+if [ -d "$UNUSED_DIR" ] || [ ! -d "$USED_DIR" ]; then
+    echo "! Expected only the unreferenced shared repository to be removed"
+    exit 1
+fi
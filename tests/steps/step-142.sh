@@ -0,0 +1,67 @@
+#!/usr/bin/env bash
+# Test:
+#   Shared hooks: scaffold and validate a shared repository
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+acceptAllTrustPrompts || exit 1
+
+git config --global githooks.testingTreatFileProtocolAsRemote "true"
+
+"$GH_TEST_BIN/cli" shared init --namespace "my-hooks" "$GH_TEST_TMP/shared/hooks-142.git" || exit 1
+
+if [ "$(cat "$GH_TEST_TMP/shared/hooks-142.git/githooks/.namespace")" != "my-hooks" ] ||
+    [ ! -f "$GH_TEST_TMP/shared/hooks-142.git/githooks/.images.yaml" ] ||
+    [ ! -f "$GH_TEST_TMP/shared/hooks-142.git/githooks/container/Dockerfile" ]; then
+    echo "! Expected the shared repository to be scaffolded"
+    exit 1
+fi
+
+# Scaffolding again must not overwrite anything.
+if "$GH_TEST_BIN/cli" shared init "$GH_TEST_TMP/shared/hooks-142.git"; then
+    echo "! Expected scaffolding into an existing shared repository to fail"
+    exit 1
+fi
+
+cd "$GH_TEST_TMP/shared/hooks-142.git" &&
+    "$GH_TEST_BIN/cli" shared validate &&
+    git add . &&
+    git commit -m 'Initial commit' ||
+    exit 1
+
+# Use the scaffolded shared repository without containers.
+mkdir -p "$GH_TEST_TMP/test142" &&
+    cd "$GH_TEST_TMP/test142" &&
+    git init &&
+    "$GH_TEST_BIN/cli" shared add --local "file://$GH_TEST_TMP/shared/hooks-142.git" &&
+    "$GH_TEST_BIN/cli" shared update ||
+    exit 1
+
+echo "a" >a.txt && git add a.txt || exit 1
+if ! OUT=$("$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit 2>&1) ||
+    ! echo "$OUT" | grep -q " - a.txt"; then
+    echo "! Expected the scaffolded example hook to run"
+    echo "$OUT"
+    exit 1
+fi
+
+# Report all errors at once.
+cd "$GH_TEST_TMP/shared/hooks-142.git" || exit 1
+rm githooks/container/Dockerfile &&
+    echo "cmd: ./missing.sh" >githooks/pre-commit/broken.yaml || exit 1
+
+if OUT=$("$GH_TEST_BIN/cli" shared validate 2>&1); then
+    echo "! Expected validation to fail"
+    echo "$OUT"
+    exit 1
+fi
+
+if ! echo "$OUT" | grep -q "has '2' errors" ||
+    ! echo "$OUT" | grep -q "Dockerfile" ||
+    ! echo "$OUT" | grep -q "missing.sh"; then
+    echo "! Expected all errors to be reported"
+    echo "$OUT"
+    exit 1
+fi
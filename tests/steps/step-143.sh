@@ -0,0 +1,55 @@
+#!/usr/bin/env bash
+# Test:
+#   Trust: migrate legacy SHA1 checksums to SHA256
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+mkdir -p "$GH_TEST_TMP/test143" &&
+    cd "$GH_TEST_TMP/test143" &&
+    git init || exit 1
+
+mkdir -p .githooks/pre-commit &&
+    echo "echo 'Hook executed' >> '$GH_TEST_TMP/test143.out'" >.githooks/pre-commit/test &&
+    echo "echo 'Changed hook'" >.githooks/pre-commit/changed || exit 1
+
+# Create legacy SHA1 entries.
+for hook in test changed; do
+    SHA1=$(git hash-object ".githooks/pre-commit/$hook") &&
+        mkdir -p ".git/.githooks.checksums/${SHA1:0:2}" &&
+        echo "path: $(pwd)/.githooks/pre-commit/$hook" >".git/.githooks.checksums/${SHA1:0:2}/${SHA1:2}" ||
+        exit 1
+done
+
+echo "echo 'Changed hook modified'" >.githooks/pre-commit/changed || exit 1
+
+# Legacy entries are still trusted.
+if ! GITHOOKS_SKIP_UNTRUSTED_HOOKS=true ACCEPT_CHANGES=N "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit ||
+    ! grep -q "Hook executed" "$GH_TEST_TMP/test143.out"; then
+    echo "! Expected the hook with a legacy checksum to be trusted"
+    exit 1
+fi
+
+OUT=$("$GH_TEST_BIN/cli" trust migrate 2>&1) || exit 1
+if ! echo "$OUT" | grep -q "Migrated '1'" ||
+    ! echo "$OUT" | grep -q "Kept '1'"; then
+    echo "! Expected one checksum to be migrated and one to be kept"
+    echo "$OUT"
+    exit 1
+fi
+
+SHA1=$(git hash-object ".githooks/pre-commit/test")
+if [ -f ".git/.githooks.checksums/${SHA1:0:2}/${SHA1:2}" ] ||
+    [ "$(find .git/.githooks.checksums -type f -not -path '*/.contents/*' -name '??????????????????????????????????????????????????????????????' | wc -l)" != "1" ]; then
+    echo "! Expected the legacy checksum to be replaced by a SHA256 checksum"
+    find .git/.githooks.checksums -type f
+    exit 1
+fi
+
+rm "$GH_TEST_TMP/test143.out" || exit 1
+if ! GITHOOKS_SKIP_UNTRUSTED_HOOKS=true ACCEPT_CHANGES=N "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit ||
+    ! grep -q "Hook executed" "$GH_TEST_TMP/test143.out"; then
+    echo "! Expected the hook with a migrated checksum to be trusted"
+    exit 1
+fi
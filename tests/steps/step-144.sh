@@ -0,0 +1,90 @@
+#!/usr/bin/env bash
+# Test:
+#   Trust: trust all hooks of a shared repository revision
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+git config --global githooks.testingTreatFileProtocolAsRemote "true"
+
+mkdir -p "$GH_TEST_TMP/shared/hooks-144.git/pre-commit" &&
+    cd "$GH_TEST_TMP/shared/hooks-144.git" &&
+    for i in 1 2 3; do
+        echo "echo 'Shared hook $i' >> '$GH_TEST_TMP/test144.out'" >"pre-commit/hook-$i" || exit 1
+    done &&
+    git init &&
+    git add . &&
+    git commit -m 'Initial commit' ||
+    exit 1
+
+URL="file://$GH_TEST_TMP/shared/hooks-144.git"
+
+mkdir -p "$GH_TEST_TMP/test144" &&
+    cd "$GH_TEST_TMP/test144" &&
+    git init &&
+    "$GH_TEST_BIN/cli" shared add --local "$URL" &&
+    "$GH_TEST_BIN/cli" shared update &&
+    "$GH_TEST_BIN/cli" config trust-shared-revisions --enable ||
+    exit 1
+
+# Trust the whole revision with one prompt.
+if ! ACCEPT_REVISION=Y ACCEPT_CHANGES=N "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit ||
+    [ "$(grep -c "Shared hook" "$GH_TEST_TMP/test144.out")" != "3" ]; then
+    echo "! Expected all shared hooks to be trusted by the revision"
+    exit 1
+fi
+
+if [ ! -f .git/.githooks.checksums/.shared-revisions.yaml ] ||
+    [ "$(find .git/.githooks.checksums -type f | wc -l)" != "1" ]; then
+    echo "! Expected only a trusted revision to be stored"
+    find .git/.githooks.checksums -type f
+    exit 1
+fi
+
+OUT=$("$GH_TEST_BIN/cli" list 2>&1)
+if ! echo "$OUT" | grep -q "\[trusted: commit '" ||
+    echo "$OUT" | grep -q "untrusted"; then
+    echo "! Expected the trusted revision to be listed"
+    echo "$OUT"
+    exit 1
+fi
+
+# A new revision is not trusted.
+cd "$GH_TEST_TMP/shared/hooks-144.git" &&
+    echo "echo 'Shared hook 1 changed' >> '$GH_TEST_TMP/test144.out'" >"pre-commit/hook-1" &&
+    git commit -a -m 'Change hook' &&
+    cd "$GH_TEST_TMP/test144" &&
+    "$GH_TEST_BIN/cli" shared update ||
+    exit 1
+
+if ACCEPT_REVISION=N ACCEPT_CHANGES=N "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit; then
+    echo "! Expected the new revision not to be trusted"
+    exit 1
+fi
+
+# Trust the checked out revision manually.
+"$GH_TEST_BIN/cli" trust shared "$URL" || exit 1
+
+rm "$GH_TEST_TMP/test144.out" || exit 1
+if ! ACCEPT_REVISION=N ACCEPT_CHANGES=N "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit ||
+    ! grep -q "Shared hook 1 changed" "$GH_TEST_TMP/test144.out"; then
+    echo "! Expected the manually trusted revision to be trusted"
+    exit 1
+fi
+
+# Local modifications are never trusted.
+CLONE_DIR=$(echo ~/.githooks/shared/*hooks-144*)
+echo "echo 'Modified'" >"$CLONE_DIR/pre-commit/hook-2" || exit 1
+if "$GH_TEST_BIN/cli" list | grep -q "\[trusted: commit '"; then
+    echo "! Expected a modified revision not to be trusted"
+    exit 1
+fi
+git -C "$CLONE_DIR" checkout pre-commit/hook-2 || exit 1
+
+"$GH_TEST_BIN/cli" trust shared --reset "$URL" || exit 1
+if "$GH_TEST_BIN/cli" list | grep -q "\[trusted: commit '" ||
+    ! "$GH_TEST_BIN/cli" list | grep -q "untrusted"; then
+    echo "! Expected the trusted revisions to be removed"
+    exit 1
+fi
@@ -0,0 +1,56 @@
+#!/usr/bin/env bash
+# Test:
+#   Trust: show the diff of changed hooks in the trust prompt
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+mkdir -p "$GH_TEST_TMP/test145" &&
+    cd "$GH_TEST_TMP/test145" &&
+    git init || exit 1
+
+mkdir -p .githooks/pre-commit &&
+    echo "echo 'First version'" >.githooks/pre-commit/test &&
+    ACCEPT_CHANGES=Y "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit ||
+    exit 1
+
+if [ "$(find .git/.githooks.checksums/.contents -type f | wc -l)" != "1" ]; then
+    echo "! Expected the trusted content to be kept"
+    exit 1
+fi
+
+echo "echo 'Second version'" >.githooks/pre-commit/test || exit 1
+
+OUT=$(ACCEPT_CHANGES=N GITHOOKS_SKIP_UNTRUSTED_HOOKS=true \
+    "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit 2>&1)
+
+if ! echo "$OUT" | grep -q "Changes since last trusted" ||
+    ! echo "$OUT" | grep -q "^ *-echo 'First version'" ||
+    ! echo "$OUT" | grep -q "^ *+echo 'Second version'"; then
+    echo "! Expected the diff to be shown in the trust prompt"
+    echo "$OUT"
+    exit 1
+fi
+
+# Containerized hooks show the image reference change.
+cat <<EOF2 >.githooks/pre-commit/lint.yaml || exit 1
+version: 3
+cmd: ./lint.sh
+image:
+  reference: lint:1.0
+EOF2
+printf '#!/bin/sh\necho Linting\n' >lint.sh && chmod +x lint.sh || exit 1
+
+ACCEPT_CHANGES=A "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit || exit 1
+
+sed -i 's/lint:1.0/lint:2.0/' .githooks/pre-commit/lint.yaml || exit 1
+
+OUT=$(ACCEPT_CHANGES=N GITHOOKS_SKIP_UNTRUSTED_HOOKS=true \
+    "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit 2>&1)
+
+if ! echo "$OUT" | grep -q "Image reference changed: 'lint:1.0' -> 'lint:2.0'"; then
+    echo "! Expected the image reference change to be shown in the trust prompt"
+    echo "$OUT"
+    exit 1
+fi
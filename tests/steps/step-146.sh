@@ -0,0 +1,93 @@
+#!/usr/bin/env bash
+# Test:
+#   Trust: organization-wide trust policy
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+git config --global githooks.testingTreatFileProtocolAsRemote "true"
+
+unset GIT_CONFIG_NOSYSTEM
+export GIT_CONFIG_SYSTEM="$GH_TEST_TMP/test146.gitconfig"
+git config --file "$GIT_CONFIG_SYSTEM" githooks.trustPolicy "$GH_TEST_TMP/test146-policy.yaml" || exit 1
+
+mkdir -p "$GH_TEST_TMP/shared/hooks-146.git/pre-commit" &&
+    cd "$GH_TEST_TMP/shared/hooks-146.git" &&
+    echo "echo 'Shared hook' >> '$GH_TEST_TMP/test146.out'" >"pre-commit/hook-1" &&
+    git init &&
+    git add . &&
+    git commit -m 'Initial commit' ||
+    exit 1
+
+URL="file://$GH_TEST_TMP/shared/hooks-146.git"
+
+mkdir -p "$GH_TEST_TMP/test146/.githooks/pre-commit" &&
+    cd "$GH_TEST_TMP/test146" &&
+    echo "echo 'Repo hook' >> '$GH_TEST_TMP/test146.out'" >".githooks/pre-commit/hook-1" &&
+    touch ".githooks/trust-all" &&
+    git init &&
+    git remote add origin "https://github.com/other-org/repo.git" &&
+    "$GH_TEST_BIN/cli" shared add --local "$URL" &&
+    "$GH_TEST_BIN/cli" shared update ||
+    exit 1
+
+if ! "$GH_TEST_BIN/cli" trust policy | grep -q "No trust policy"; then
+    echo "! Expected no trust policy"
+    exit 1
+fi
+
+cat <<EOF2 >"$GH_TEST_TMP/test146-policy.yaml"
+version: 1
+trustedShared:
+  - url: "file://$GH_TEST_TMP/shared/*"
+forbidTrustAll:
+  - "https://github.com/other-org/*"
+EOF2
+
+OUT=$("$GH_TEST_BIN/cli" trust policy 2>&1)
+if ! echo "$OUT" | grep -q "is forbidden for remote 'https://github.com/other-org/repo.git'" ||
+    ! echo "$OUT" | grep -q "$URL"; then
+    echo "! Expected the trust policy to be reported"
+    echo "$OUT"
+    exit 1
+fi
+
+if "$GH_TEST_BIN/cli" trust; then
+    echo "! Expected trusting the repository to be forbidden"
+    exit 1
+fi
+
+# Shared hooks are trusted by the policy, repository hooks are not
+# trusted even if all hooks are accepted.
+if TRUST_ALL_HOOKS=Y ACCEPT_CHANGES=N "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit; then
+    echo "! Expected the untrusted repository hook to fail"
+    exit 1
+fi
+
+if [ "$(git config --local githooks.trustAll)" = "true" ]; then
+    echo "! Expected the trust-all setting not to be stored"
+    exit 1
+fi
+
+rm -f "$GH_TEST_TMP/test146.out"
+if ! GITHOOKS_SKIP_UNTRUSTED_HOOKS=true TRUST_ALL_HOOKS=Y ACCEPT_CHANGES=N \
+    "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit ||
+    ! grep -q "Shared hook" "$GH_TEST_TMP/test146.out" ||
+    grep -q "Repo hook" "$GH_TEST_TMP/test146.out"; then
+    echo "! Expected only the shared hook to run"
+    exit 1
+fi
+
+if ! "$GH_TEST_BIN/cli" list | grep -q "\[trusted: policy '"; then
+    echo "! Expected the shared hooks to be listed as trusted by the policy"
+    "$GH_TEST_BIN/cli" list
+    exit 1
+fi
+
+# A broken policy fails.
+echo "version: 2" >"$GH_TEST_TMP/test146-policy.yaml"
+if "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit; then
+    echo "! Expected a broken trust policy to fail"
+    exit 1
+fi
@@ -0,0 +1,75 @@
+#!/usr/bin/env bash
+# Test:
+#   Trust: trust audit log
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+mkdir -p "$GH_TEST_TMP/test147/.githooks/pre-commit" &&
+    cd "$GH_TEST_TMP/test147" &&
+    echo "echo 'Hook 1'" >".githooks/pre-commit/hook-1" &&
+    echo "echo 'Hook 2'" >".githooks/pre-commit/hook-2" &&
+    git init ||
+    exit 1
+
+if ! "$GH_TEST_BIN/cli" trust log | grep -q "No trust audit log entries"; then
+    echo "! Expected an empty trust audit log"
+    exit 1
+fi
+
+# Trust all hooks with the trust prompt.
+ACCEPT_CHANGES=A "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit || exit 1
+
+OUT=$("$GH_TEST_BIN/cli" trust log --current --decision trusted 2>&1)
+if [ "$(echo "$OUT" | grep -c "trusted")" != "2" ] ||
+    ! echo "$OUT" | grep -q "ns:gh-self/pre-commit/hook-1' \[sha256: '"; then
+    echo "! Expected the trusted hooks to be logged"
+    echo "$OUT"
+    exit 1
+fi
+
+"$GH_TEST_BIN/cli" trust hooks --reset --path "ns:gh-self/pre-commit/hook-2" || exit 1
+
+OUT=$("$GH_TEST_BIN/cli" trust log --decision revoked --json 2>&1)
+if ! echo "$OUT" | grep -q '"namespacePath": "ns:gh-self/pre-commit/hook-2"' ||
+    ! echo "$OUT" | grep -q '"decision": "revoked"' ||
+    ! echo "$OUT" | grep -q '"repository": "'"$GH_TEST_TMP/test147"'"'; then
+    echo "! Expected the revoked hook to be logged"
+    echo "$OUT"
+    exit 1
+fi
+
+# Disable the changed hook with the trust prompt.
+ACCEPT_CHANGES=D "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit || exit 1
+
+if ! "$GH_TEST_BIN/cli" trust log --decision disabled --pattern "**/hook-2" | grep -q "hook-2"; then
+    echo "! Expected the disabled hook to be logged"
+    exit 1
+fi
+
+"$GH_TEST_BIN/cli" trust && "$GH_TEST_BIN/cli" trust revoke || exit 1
+
+OUT=$("$GH_TEST_BIN/cli" trust log --since 1h 2>&1)
+if [ "$(echo "$OUT" | grep -c "all hooks")" != "2" ]; then
+    echo "! Expected the trust-all changes to be logged"
+    echo "$OUT"
+    exit 1
+fi
+
+# Revoking again and forgetting an unset trust setting changes nothing.
+"$GH_TEST_BIN/cli" trust revoke && "$GH_TEST_BIN/cli" trust forget &&
+    "$GH_TEST_BIN/cli" trust forget || exit 1
+
+OUT=$("$GH_TEST_BIN/cli" trust log --current 2>&1)
+if [ "$(echo "$OUT" | grep "all hooks" | grep -c "revoked")" != "1" ] ||
+    [ "$(echo "$OUT" | grep "all hooks" | grep -c "forgotten")" != "1" ]; then
+    echo "! Expected one revoked and one forgotten trust-all entry"
+    echo "$OUT"
+    exit 1
+fi
+
+if [ "$("$GH_TEST_BIN/cli" trust log --repository "/other/*" --json)" != "[]" ]; then
+    echo "! Expected no entries for other repositories"
+    exit 1
+fi
@@ -0,0 +1,53 @@
+#!/usr/bin/env bash
+# Test:
+#   Trust: expiring trust of hooks
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+mkdir -p "$GH_TEST_TMP/test148/.githooks/pre-commit" &&
+    cd "$GH_TEST_TMP/test148" &&
+    echo "echo 'Hook 1' >> '$GH_TEST_TMP/test148.out'" >".githooks/pre-commit/hook-1" &&
+    git init ||
+    exit 1
+
+"$GH_TEST_BIN/cli" trust hooks --expire 2s --path "ns:gh-self/pre-commit/hook-1" || exit 1
+
+if ! ACCEPT_CHANGES=N "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit ||
+    ! grep -q "Hook 1" "$GH_TEST_TMP/test148.out"; then
+    echo "! Expected the hook to be trusted"
+    exit 1
+fi
+
+sleep 3
+
+OUT=$(ACCEPT_CHANGES=N "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit 2>&1)
+# shellcheck disable=SC2181
+if [ $? -eq 0 ] || ! echo "$OUT" | grep -q "Trust of hook expired"; then
+    echo "! Expected the trust of the hook to be expired"
+    echo "$OUT"
+    exit 1
+fi
+
+# Trusting again renews the trust, the configured expiry applies.
+ACCEPT_CHANGES=Y "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit || exit 1
+
+"$GH_TEST_BIN/cli" config trust-expiry --set 1s || exit 1
+if ! "$GH_TEST_BIN/cli" config trust-expiry --print | grep -q "expire after '1s'"; then
+    echo "! Expected the trust expiry to be set"
+    exit 1
+fi
+
+sleep 2
+
+if ACCEPT_CHANGES=N "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit; then
+    echo "! Expected the trust of the hook to be expired by the config"
+    exit 1
+fi
+
+"$GH_TEST_BIN/cli" config trust-expiry --reset || exit 1
+if ! ACCEPT_CHANGES=N "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit; then
+    echo "! Expected the hook to be trusted without expiry"
+    exit 1
+fi
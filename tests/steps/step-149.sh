@@ -0,0 +1,68 @@
+#!/usr/bin/env bash
+# Test:
+#   Trust: review all pending hooks
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+mkdir -p "$GH_TEST_TMP/test149/.githooks/pre-commit" &&
+    cd "$GH_TEST_TMP/test149" &&
+    echo "echo 'Hook 1' >> '$GH_TEST_TMP/test149.out'" >".githooks/pre-commit/hook-1" &&
+    echo "echo 'Hook 2' >> '$GH_TEST_TMP/test149.out'" >".githooks/pre-commit/hook-2" &&
+    echo "echo 'Hook 3' >> '$GH_TEST_TMP/test149.out'" >".githooks/pre-commit/hook-3" &&
+    git init ||
+    exit 1
+
+# View hook 1 and trust it, ignore hook 2, skip hook 3 and discard.
+OUT=$(printf 'v\nt\ni\ns\nn\n' | "$GH_TEST_BIN/cli" trust review --stdin 2>&1)
+if ! echo "$OUT" | grep -q "Hook '1/3' \[new\]" ||
+    ! echo "$OUT" | grep -q "echo 'Hook 1'" ||
+    ! echo "$OUT" | grep -q "Discarded all decisions"; then
+    echo "! Expected the review to be discarded"
+    echo "$OUT"
+    exit 1
+fi
+
+if "$GH_TEST_BIN/cli" list | grep "hook-1" | grep -q "'trusted'"; then
+    echo "! Expected hook 1 not to be trusted"
+    exit 1
+fi
+
+# Trust hook 1, ignore hook 2, skip hook 3 and apply.
+printf 't\ni\ns\ny\n' | "$GH_TEST_BIN/cli" trust review --stdin || exit 1
+
+if ! "$GH_TEST_BIN/cli" list | grep "hook-1" | grep -q "'active', 'trusted'" ||
+    ! "$GH_TEST_BIN/cli" list | grep "hook-2" | grep -q "'ignored'" ||
+    ! "$GH_TEST_BIN/cli" list | grep "hook-3" | grep -q "'active', 'untrusted'"; then
+    echo "! Expected hook 1 trusted, hook 2 ignored and hook 3 untrusted"
+    "$GH_TEST_BIN/cli" list
+    exit 1
+fi
+
+if ! grep -q "ns:gh-self/pre-commit/hook-2" .git/.githooks.ignore.yaml; then
+    echo "! Expected hook 2 in the user ignore file"
+    exit 1
+fi
+
+if ! "$GH_TEST_BIN/cli" trust log --current --decision disabled | grep -q "hook-2"; then
+    echo "! Expected an audit entry for hook 2"
+    exit 1
+fi
+
+# Change hook 1 and trust all remaining.
+echo "echo 'Hook 1 changed' >> '$GH_TEST_TMP/test149.out'" >".githooks/pre-commit/hook-1"
+
+OUT=$(printf 'd\na\ny\n' | "$GH_TEST_BIN/cli" trust review --stdin 2>&1)
+if ! echo "$OUT" | grep -q "Hook '1/2' \[changed\]" ||
+    ! echo "$OUT" | grep -q "+echo 'Hook 1 changed'"; then
+    echo "! Expected the diff of the changed hook"
+    echo "$OUT"
+    exit 1
+fi
+
+if ! "$GH_TEST_BIN/cli" trust review --stdin </dev/null | grep -q "Nothing to review"; then
+    echo "! Expected all hooks to be trusted"
+    "$GH_TEST_BIN/cli" list
+    exit 1
+fi
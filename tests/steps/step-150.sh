@@ -0,0 +1,82 @@
+#!/usr/bin/env bash
+# Test:
+#   Ignore: conditional ignore patterns
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+mkdir -p "$GH_TEST_TMP/test150/.githooks/pre-commit" &&
+    cd "$GH_TEST_TMP/test150" &&
+    echo "echo 'Heavy' >> '$GH_TEST_TMP/test150.out'" >".githooks/pre-commit/heavy" &&
+    echo "echo 'Light' >> '$GH_TEST_TMP/test150.out'" >".githooks/pre-commit/light" &&
+    cat <<EOF2 >".githooks/.ignore.yaml" &&
+conditional:
+  - patterns:
+      - "pre-commit/heavy"
+    when:
+      branch: "release/*"
+  - namespace-paths:
+      - "ns:gh-self/pre-commit/light"
+    when:
+      env: GH_TEST_SKIP_LIGHT=yes
+version: 2
+EOF2
+    git init &&
+    git commit --allow-empty -m "Init" ||
+    exit 1
+
+"$GH_TEST_BIN/cli" trust hooks --all || exit 1
+
+rm -f "$GH_TEST_TMP/test150.out"
+"$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit || exit 1
+if ! grep -q "Heavy" "$GH_TEST_TMP/test150.out" ||
+    ! grep -q "Light" "$GH_TEST_TMP/test150.out"; then
+    echo "! Expected all hooks to run on 'main'"
+    exit 1
+fi
+
+git checkout -b release/1.0 || exit 1
+
+rm -f "$GH_TEST_TMP/test150.out"
+GH_TEST_SKIP_LIGHT=yes "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit
+if [ -f "$GH_TEST_TMP/test150.out" ]; then
+    echo "! Expected all hooks to be ignored"
+    cat "$GH_TEST_TMP/test150.out"
+    exit 1
+fi
+
+if ! "$GH_TEST_BIN/cli" list | grep "heavy" | grep -q "'ignored'" ||
+    ! "$GH_TEST_BIN/cli" list | grep "light" | grep -q "'active'"; then
+    echo "! Expected only the heavy hook to be ignored"
+    "$GH_TEST_BIN/cli" list
+    exit 1
+fi
+
+OUT=$("$GH_TEST_BIN/cli" ignore show --repository --only-existing)
+if ! echo "$OUT" | grep -q "when: \[branch: 'release/\*'\], fulfilled: 'true'" ||
+    ! echo "$OUT" | grep -q "when: \[env: 'GH_TEST_SKIP_LIGHT=yes'\], fulfilled: 'false'"; then
+    echo "! Expected the conditions to be shown"
+    echo "$OUT"
+    exit 1
+fi
+
+if ! "$GH_TEST_BIN/cli" ignore show --path "ns:gh-self/pre-commit/heavy" |
+    grep -q "ignored by the repository ignore patterns with condition \[branch: 'release/\*'\]"; then
+    echo "! Expected the condition of the ignored hook"
+    exit 1
+fi
+
+if ! GH_TEST_SKIP_LIGHT=no "$GH_TEST_BIN/cli" ignore show --path "ns:gh-self/pre-commit/light" |
+    grep -q "is not ignored"; then
+    echo "! Expected the light hook not to be ignored"
+    exit 1
+fi
+
+# Adding patterns keeps the conditional patterns.
+"$GH_TEST_BIN/cli" ignore add --repository --pattern "pre-commit/other" || exit 1
+if ! grep -q "release/\*" ".githooks/.ignore.yaml"; then
+    echo "! Expected conditional patterns to be kept"
+    cat ".githooks/.ignore.yaml"
+    exit 1
+fi
@@ -0,0 +1,72 @@
+#!/usr/bin/env bash
+# Test:
+#   Explain: why a hook runs or not
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+mkdir -p "$GH_TEST_TMP/test151/.githooks/pre-commit" &&
+    cd "$GH_TEST_TMP/test151" &&
+    echo "echo 'Hook 1'" >".githooks/pre-commit/hook-1" &&
+    echo "echo 'Hook 2'" >".githooks/pre-commit/hook-2" &&
+    echo "echo 'Keep'" >".githooks/pre-commit/keep" &&
+    cat <<EOF2 >".githooks/pre-commit/.ignore.yaml" &&
+patterns:
+  - "*"
+  - "!keep"
+version: 1
+EOF2
+    git init ||
+    exit 1
+
+"$GH_TEST_BIN/cli" explain "ns:gh-self/pre-commit/does-not-exist" &&
+    echo "! Expected explain to fail for unknown hooks" &&
+    exit 1
+
+OUT=$("$GH_TEST_BIN/cli" explain "ns:gh-self/pre-commit/hook-1")
+if ! echo "$OUT" | grep -q "Repository ignores: ignored: 'true'" ||
+    ! echo "$OUT" | grep -q "pattern 'ns:gh-self/pre-commit/\*' in '.*/pre-commit/.ignore.yaml' ignores the hook" ||
+    ! echo "$OUT" | grep -q "The hook does not run: it is ignored"; then
+    echo "! Expected hook 1 to be ignored"
+    echo "$OUT"
+    exit 1
+fi
+
+OUT=$("$GH_TEST_BIN/cli" explain .githooks/pre-commit/keep)
+if ! echo "$OUT" | grep -q "pattern '!ns:gh-self/pre-commit/keep' in '.*' activates the hook" ||
+    ! echo "$OUT" | grep -q "Untrusted: checksum .* is not in the checksum store" ||
+    ! echo "$OUT" | grep -q "The hook needs to be trusted"; then
+    echo "! Expected hook 'keep' to be activated and untrusted"
+    echo "$OUT"
+    exit 1
+fi
+
+"$GH_TEST_BIN/cli" config skip-untrusted-hooks --enable || exit 1
+if ! "$GH_TEST_BIN/cli" explain "ns:gh-self/pre-commit/keep" |
+    grep -q "does not run: it is untrusted and untrusted hooks are skipped"; then
+    echo "! Expected hook 'keep' to be skipped"
+    exit 1
+fi
+
+"$GH_TEST_BIN/cli" trust hooks --path "ns:gh-self/pre-commit/keep" || exit 1
+OUT=$("$GH_TEST_BIN/cli" explain "ns:gh-self/pre-commit/keep")
+if ! echo "$OUT" | grep -q "Trusted: checksum .* is in the checksum store" ||
+    ! echo "$OUT" | grep -q "The hook runs"; then
+    echo "! Expected hook 'keep' to run"
+    echo "$OUT"
+    exit 1
+fi
+
+"$GH_TEST_BIN/cli" ignore add --path "ns:gh-self/pre-commit/keep" || exit 1
+if ! "$GH_TEST_BIN/cli" explain "ns:gh-self/pre-commit/keep" |
+    grep -q "namespace path 'ns:gh-self/pre-commit/keep' in '.*/.git/.githooks.ignore.yaml' ignores the hook"; then
+    echo "! Expected hook 'keep' to be ignored by the user"
+    exit 1
+fi
+
+"$GH_TEST_BIN/cli" disable || exit 1
+if ! "$GH_TEST_BIN/cli" explain "ns:gh-self/pre-commit/hook-2" | grep -q "Githooks is disabled"; then
+    echo "! Expected Githooks to be disabled"
+    exit 1
+fi
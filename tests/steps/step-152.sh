@@ -0,0 +1,65 @@
+#!/usr/bin/env bash
+# Test:
+#   Runner: skip hooks with `GITHOOKS_SKIP`
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+unset GIT_CONFIG_NOSYSTEM
+export GIT_CONFIG_SYSTEM="$GH_TEST_TMP/test152-system.gitconfig"
+
+mkdir -p "$GH_TEST_TMP/test152/.githooks/pre-commit" &&
+    cd "$GH_TEST_TMP/test152" &&
+    echo "echo 'Slow' >> '$GH_TEST_TMP/test152.out'" >".githooks/pre-commit/slow.sh" &&
+    echo "echo 'Fast' >> '$GH_TEST_TMP/test152.out'" >".githooks/pre-commit/fast.sh" &&
+    echo "echo 'Scan' >> '$GH_TEST_TMP/test152.out'" >".githooks/pre-commit/scan.sh" &&
+    git init ||
+    exit 1
+
+"$GH_TEST_BIN/cli" trust hooks --all || exit 1
+
+OUT=$(GITHOOKS_SKIP="pre-commit/slow.sh, ns:gh-self/**/scan*" \
+    "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit 2>&1)
+# shellcheck disable=SC2181
+if [ $? -ne 0 ] ||
+    ! echo "$OUT" | grep -q "Hook 'ns:gh-self/pre-commit/slow.sh' skipped by env 'GITHOOKS_SKIP'" ||
+    ! echo "$OUT" | grep -q "Hook 'ns:gh-self/pre-commit/scan.sh' skipped by env 'GITHOOKS_SKIP'" ||
+    [ "$(echo "$OUT" | grep -c "Hook 'ns:gh-self/pre-commit/slow.sh' skipped")" != "1" ] ||
+    grep -q "Slow\|Scan" "$GH_TEST_TMP/test152.out" ||
+    ! grep -q "Fast" "$GH_TEST_TMP/test152.out"; then
+    echo "! Expected slow and scan hooks to be skipped"
+    echo "$OUT"
+    exit 1
+fi
+
+if ! GITHOOKS_SKIP="pre-commit/slow.sh" "$GH_TEST_BIN/cli" explain "ns:gh-self/pre-commit/slow.sh" |
+    grep -q "does not run: it is skipped by env 'GITHOOKS_SKIP'"; then
+    echo "! Expected explain to report the skip"
+    exit 1
+fi
+
+# Forbid skipping by the policy.
+git config --file "$GIT_CONFIG_SYSTEM" githooks.trustPolicy "$GH_TEST_TMP/test152-policy.yaml" || exit 1
+cat <<EOF >"$GH_TEST_TMP/test152-policy.yaml"
+forbidSkip:
+  - "ns:*/pre-commit/scan*"
+version: 3
+EOF
+
+rm -f "$GH_TEST_TMP/test152.out"
+OUT=$(GITHOOKS_SKIP="pre-commit/*" "$GH_TEST_BIN/runner" "$(pwd)"/.git/hooks/pre-commit 2>&1)
+# shellcheck disable=SC2181
+if [ $? -ne 0 ] ||
+    [ "$(echo "$OUT" | grep -c "Hook 'ns:gh-self/pre-commit/scan.sh' cannot be skipped")" != "1" ] ||
+    ! grep -q "Scan" "$GH_TEST_TMP/test152.out" ||
+    grep -q "Slow\|Fast" "$GH_TEST_TMP/test152.out"; then
+    echo "! Expected the scan hook not to be skipped"
+    echo "$OUT"
+    exit 1
+fi
+
+if ! "$GH_TEST_BIN/cli" trust policy | grep -q "cannot be skipped by 'GITHOOKS_SKIP'"; then
+    echo "! Expected the policy to report the forbidden skips"
+    exit 1
+fi
@@ -0,0 +1,121 @@
+#!/usr/bin/env bash
+# Test:
+#   Run the install with a declarative install configuration
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+acceptAllTrustPrompts || exit 1
+
+if [ -n "$GH_ON_WINDOWS" ]; then
+    echo "On windows -> skip."
+    exit 249
+fi
+
+mkdir -p "$GH_TEST_TMP/shared/hooks-153.git/pre-commit" &&
+    cd "$GH_TEST_TMP/shared/hooks-153.git" &&
+    git init &&
+    echo "echo 'From shared'" >pre-commit/shared-153 &&
+    git add . &&
+    git commit -m 'Initial commit' ||
+    exit 1
+
+mkdir -p "$GH_TEST_TMP/test153" && cd "$GH_TEST_TMP/test153" || exit 1
+
+cat <<EOF >"install.yaml"
+installMode: core-hooks-path
+maintainedHooks:
+  - "!all"
+  - pre-commit
+sharedRepos:
+  - "file://$GH_TEST_TMP/shared/hooks-153.git"
+update:
+  autoUpdateEnabled: false
+containers:
+  enabled: true
+  manager: docker
+version: 1
+EOF
+
+# Print the effective configuration.
+OUT=$("$GH_TEST_BIN/cli" installer --install-config install.yaml --use-manual --print-config 2>&1)
+# shellcheck disable=SC2181
+if [ $? -ne 0 ] ||
+    ! echo "$OUT" | grep -q "installMode: manual" ||
+    ! echo "$OUT" | grep -q "autoUpdateEnabled: false" ||
+    ! echo "$OUT" | grep -q "hooks-153.git" ||
+    ! echo "$OUT" | grep -q "version: 1"; then
+    echo "! Expected effective configuration with overwritten install mode."
+    echo "$OUT"
+    exit 1
+fi
+
+# Invalid configurations.
+cat <<EOF >"invalid.yaml"
+installMode: core-hooks-path
+unknownKey: true
+version: 1
+EOF
+
+OUT=$("$GH_TEST_BIN/cli" installer --install-config invalid.yaml --print-config 2>&1)
+if [ $? -eq 0 ] || ! echo "$OUT" | grep -q "unknownKey"; then
+    echo "! Expected unknown key to fail."
+    echo "$OUT"
+    exit 1
+fi
+
+cat <<EOF >"invalid.yaml"
+installMode: wrong
+containers:
+  manager: containerd
+deploy:
+  api: bitbucket
+version: 1
+EOF
+
+OUT=$("$GH_TEST_BIN/cli" installer --install-config invalid.yaml --print-config 2>&1)
+if [ $? -eq 0 ] ||
+    ! echo "$OUT" | grep -q "Install mode 'wrong'" ||
+    ! echo "$OUT" | grep -q "Container manager 'containerd'" ||
+    ! echo "$OUT" | grep -q "Deploy api 'bitbucket'"; then
+    echo "! Expected invalid values to fail."
+    echo "$OUT"
+    exit 1
+fi
+
+echo "version: 2" >"invalid.yaml"
+OUT=$("$GH_TEST_BIN/cli" installer --install-config invalid.yaml --print-config 2>&1)
+if [ $? -eq 0 ] || ! echo "$OUT" | grep -q "only supports version"; then
+    echo "! Expected wrong version to fail."
+    echo "$OUT"
+    exit 1
+fi
+
+# Install with the configuration.
+"$GH_TEST_BIN/cli" installer --non-interactive --install-config install.yaml || exit 1
+
+if [ "$(git config --global githooks.useCoreHooksPath)" != "true" ]; then
+    echo "! Expected install mode 'core-hooks-path'."
+    exit 1
+fi
+
+if [ "$(git config --global githooks.maintainedHooks)" != "!all, pre-commit" ]; then
+    echo "! Expected maintained hooks to be set."
+    git config --global githooks.maintainedHooks
+    exit 1
+fi
+
+if ! git config --global githooks.shared | grep -q "hooks-153.git" ||
+    [ "$(git config --global githooks.autoUpdateEnabled)" != "false" ] ||
+    [ "$(git config --global githooks.containerizedHooksEnabled)" != "true" ] ||
+    [ "$(git config --global githooks.containerManager)" != "docker" ]; then
+    echo "! Expected configured settings."
+    git config --global --get-regexp "githooks.*"
+    exit 1
+fi
+
+if ! find ~/.githooks/shared -name "shared-153" | grep -q "shared-153"; then
+    echo "! Expected shared repository to be cloned."
+    exit 1
+fi
@@ -0,0 +1,97 @@
+#!/usr/bin/env bash
+# Test:
+#   Cli tool: roll back an update manually and automatically
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+acceptAllTrustPrompts || exit 1
+
+if [ -n "$GH_ON_WINDOWS" ]; then
+    echo "On windows -> skip."
+    exit 249
+fi
+
+"$GH_TEST_BIN/cli" installer --non-interactive || exit 1
+
+if "$GH_INSTALL_BIN_DIR/cli" update --rollback; then
+    echo "! Expected rollback to fail without a previous version."
+    exit 1
+fi
+
+# Reset to trigger update
+if ! git -C "$GH_TEST_REPO" reset --hard v9.9.1 >/dev/null; then
+    echo "! Could not reset server to trigger update."
+    exit 1
+fi
+
+CURRENT="$(git -C ~/.githooks/release rev-parse HEAD)"
+"$GH_INSTALL_BIN_DIR/cli" update --yes || exit 1
+AFTER="$(git -C ~/.githooks/release rev-parse HEAD)"
+
+if [ "$CURRENT" = "$AFTER" ]; then
+    echo "! Release clone was not updated, but it should have!"
+    exit 1
+fi
+
+if [ ! -f ~/.githooks/rollback/bin/runner ] ||
+    ! grep -q "commitSHA: $CURRENT" ~/.githooks/rollback/.rollback.yaml; then
+    echo "! Expected previous version to be kept."
+    exit 1
+fi
+
+touch ~/.githooks/bin/not-rolled-back || exit 1
+
+OUT=$("$GH_INSTALL_BIN_DIR/cli" update --rollback 2>&1)
+# shellcheck disable=SC2181
+if [ $? -ne 0 ] || ! echo "$OUT" | grep -q "Rolled back from version" ||
+    ! echo "$OUT" | grep -q "Githooks Installer"; then
+    echo "! Expected rollback to succeed and to run the restored installer."
+    echo "$OUT"
+    exit 1
+fi
+
+if [ "$(git -C ~/.githooks/release rev-parse HEAD)" != "$CURRENT" ] ||
+    [ -d ~/.githooks/rollback ]; then
+    echo "! Expected release clone to be rolled back."
+    exit 1
+fi
+
+if [ -f ~/.githooks/bin/not-rolled-back ]; then
+    echo "! Expected binaries not in the previous version to be removed."
+    exit 1
+fi
+
+if [ -z "$(git config --global githooks.updateSkippedVersion)" ]; then
+    echo "! Expected the rolled back version to be skipped."
+    exit 1
+fi
+
+if ! "$GH_INSTALL_BIN_DIR/cli" update --no | grep -q "is not skipped anymore" ||
+    [ -n "$(git config --global githooks.updateSkippedVersion)" ]; then
+    echo "! Expected a manual update to opt in to the rolled back version again."
+    exit 1
+fi
+
+# Update with a broken runner.
+mkdir -p "$GH_TEST_TMP/broken-bin" &&
+    cp "$GH_TEST_BIN/"* "$GH_TEST_TMP/broken-bin/" &&
+    rm "$GH_TEST_TMP/broken-bin/runner" &&
+    printf '#!/bin/sh\nexit 1\n' >"$GH_TEST_TMP/broken-bin/runner" &&
+    chmod +x "$GH_TEST_TMP/broken-bin/runner" || exit 1
+
+OUT=$(GH_TEST_BIN="$GH_TEST_TMP/broken-bin" "$GH_INSTALL_BIN_DIR/cli" update --yes 2>&1)
+# shellcheck disable=SC2181
+if [ $? -eq 0 ] ||
+    ! echo "$OUT" | grep -q "Rolled back to the previous version"; then
+    echo "! Expected update to roll back automatically."
+    echo "$OUT"
+    exit 1
+fi
+
+if [ "$(git -C ~/.githooks/release rev-parse HEAD)" != "$CURRENT" ] ||
+    ! "$GH_INSTALL_BIN_DIR/runner" --version; then
+    echo "! Expected the previous version to be restored."
+    exit 1
+fi
@@ -0,0 +1,102 @@
+#!/usr/bin/env bash
+# Test:
+#   Runner: check the required Githooks version of a repository
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+acceptAllTrustPrompts || exit 1
+
+if [ -n "$GH_ON_WINDOWS" ]; then
+    echo "On windows -> skip."
+    exit 249
+fi
+
+"$GH_TEST_BIN/cli" installer --non-interactive || exit 1
+
+if "$GH_INSTALL_BIN_DIR/cli" installer --update-to "not-a-version"; then
+    echo "! Expected installer to fail with an invalid version."
+    exit 1
+fi
+
+mkdir -p "$GH_TEST_TMP/test155" &&
+    cd "$GH_TEST_TMP/test155" &&
+    git init || exit 1
+
+mkdir -p .githooks/pre-commit &&
+    echo "echo 'Hook ran' >> '$GH_TEST_TMP/test155.out'" >.githooks/pre-commit/test &&
+    cat <<EOF >.githooks/.version.yaml || exit 1
+required: ">= v9.9.1"
+enforce: false
+version: 1
+EOF
+
+# Ignore the required version of an untrusted repository.
+OUT=$(INSTALL_REQUIRED_VERSION="n" git commit --allow-empty -m "Test" 2>&1)
+# shellcheck disable=SC2181
+if [ $? -ne 0 ] ||
+    echo "$OUT" | grep -q "does not match the required version"; then
+    echo "! Expected no check of the required version in an untrusted repository."
+    echo "$OUT"
+    exit 1
+fi
+
+"$GH_INSTALL_BIN_DIR/cli" trust || exit 1
+
+# Warn on a wrong version.
+OUT=$(INSTALL_REQUIRED_VERSION="n" git commit --allow-empty -m "Test" 2>&1)
+# shellcheck disable=SC2181
+if [ $? -ne 0 ] ||
+    ! echo "$OUT" | grep -q "does not match the required version" ||
+    ! grep -q "Hook ran" "$GH_TEST_TMP/test155.out"; then
+    echo "! Expected a warning about the required version."
+    echo "$OUT"
+    exit 1
+fi
+
+# Fail on a wrong version if enforced.
+rm -f "$GH_TEST_TMP/test155.out"
+git config githooks.runnerIsNonInteractive true &&
+    sed -i 's/enforce: false/enforce: true/' .githooks/.version.yaml || exit 1
+
+OUT=$(git commit --allow-empty -m "Test" 2>&1)
+# shellcheck disable=SC2181
+if [ $? -eq 0 ] ||
+    ! echo "$OUT" | grep -q "does not match the required version" ||
+    [ -f "$GH_TEST_TMP/test155.out" ]; then
+    echo "! Expected commit to fail because of the required version."
+    echo "$OUT"
+    exit 1
+fi
+
+# Install the required version.
+git config --unset githooks.runnerIsNonInteractive || exit 1
+
+if ! git -C "$GH_TEST_REPO" reset --hard v9.9.1 >/dev/null; then
+    echo "! Could not reset server to provide the required version."
+    exit 1
+fi
+
+# The required version is only offered once a day.
+git config --global githooks.autoUpdateCheckTimestamp "$(date +%s)" || exit 1
+OUT=$(INSTALL_REQUIRED_VERSION="y" git commit --allow-empty -m "Test" 2>&1)
+# shellcheck disable=SC2181
+if [ $? -ne 0 ] ||
+    ! echo "$OUT" | grep -q "does not match the required version" ||
+    echo "$OUT" | grep -q "successfully installed"; then
+    echo "! Expected the required version not to be offered again the same day."
+    echo "$OUT"
+    exit 1
+fi
+
+git config --global --unset githooks.autoUpdateCheckTimestamp || exit 1
+OUT=$(INSTALL_REQUIRED_VERSION="y" git commit --allow-empty -m "Test" 2>&1)
+# shellcheck disable=SC2181
+if [ $? -ne 0 ] ||
+    ! echo "$OUT" | grep -q "Version 'v9.9.1' successfully installed" ||
+    [ "$(git -C ~/.githooks/release rev-parse HEAD)" != "$(git -C "$GH_TEST_REPO" rev-parse v9.9.1)" ]; then
+    echo "! Expected the required version to be installed."
+    echo "$OUT"
+    exit 1
+fi
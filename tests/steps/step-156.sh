@@ -0,0 +1,74 @@
+#!/usr/bin/env bash
+# Test:
+#   Cli tool: update channels and staged rollouts
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+acceptAllTrustPrompts || exit 1
+
+if [ -n "$GH_ON_WINDOWS" ]; then
+    echo "On windows -> skip."
+    exit 249
+fi
+
+"$GH_TEST_BIN/cli" installer --non-interactive || exit 1
+
+cat <<EOF >~/.githooks/deploy.yaml || exit 1
+version: 2
+updateChannels:
+  channels:
+    old:
+      versions: "< v9.9.1"
+    staged:
+      rollout:
+        percentage: 0
+        period: 1000h
+EOF
+
+if "$GH_INSTALL_BIN_DIR/cli" config update-channel --set "nightly"; then
+    echo "! Expected setting an undefined channel to fail."
+    exit 1
+fi
+
+# Reset to trigger update
+if ! git -C "$GH_TEST_REPO" reset --hard v9.9.1 >/dev/null; then
+    echo "! Could not reset server to trigger update."
+    exit 1
+fi
+
+CURRENT="$(git -C ~/.githooks/release rev-parse HEAD)"
+
+for channel in "old" "staged"; do
+    "$GH_INSTALL_BIN_DIR/cli" config update-channel --set "$channel" || exit 1
+
+    OUT=$("$GH_INSTALL_BIN_DIR/cli" update --yes 2>&1)
+    # shellcheck disable=SC2181
+    if [ $? -ne 0 ] || [ "$(git -C ~/.githooks/release rev-parse HEAD)" != "$CURRENT" ]; then
+        echo "! Expected no update in channel '$channel'."
+        echo "$OUT"
+        exit 1
+    fi
+done
+
+"$GH_INSTALL_BIN_DIR/cli" config update-channel --set "stable" || exit 1
+if ! "$GH_INSTALL_BIN_DIR/cli" config update-channel --print | grep -q "'stable'"; then
+    echo "! Expected update channel 'stable'."
+    exit 1
+fi
+
+OUT=$("$GH_INSTALL_BIN_DIR/cli" update --yes 2>&1)
+# shellcheck disable=SC2181
+if [ $? -ne 0 ] ||
+    ! echo "$OUT" | grep -q "Update Channel: 'stable'" ||
+    [ "$(git -C ~/.githooks/release rev-parse HEAD)" != "$(git -C "$GH_TEST_REPO" rev-parse v9.9.1)" ]; then
+    echo "! Expected update in channel 'stable'."
+    echo "$OUT"
+    exit 1
+fi
+
+if ! grep -q "staged:" ~/.githooks/deploy.yaml; then
+    echo "! Expected update channels to be kept in the deploy settings."
+    exit 1
+fi
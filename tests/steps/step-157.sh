@@ -0,0 +1,104 @@
+#!/usr/bin/env bash
+# Test:
+#   Cli tool: create an install bundle and install from it offline
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+acceptAllTrustPrompts || exit 1
+
+if [ -n "$GH_ON_WINDOWS" ]; then
+    echo "On windows -> skip."
+    exit 249
+fi
+
+mkdir -p "$GH_TEST_TMP/shared/first-shared.git/.githooks/pre-commit" &&
+    echo 'echo "Hello"' >"$GH_TEST_TMP/shared/first-shared.git/.githooks/pre-commit/sample-one" &&
+    (cd "$GH_TEST_TMP/shared/first-shared.git" && git init && git add . && git commit -m 'Testing') ||
+    exit 1
+
+url="file://$GH_TEST_TMP/shared/first-shared.git"
+bundle="$GH_TEST_TMP/githooks-bundle.tar.gz"
+
+if ! "$GH_TEST_BIN/cli" installer bundle --update --shared "$url" --output "$bundle"; then
+    echo "! Failed to create the install bundle."
+    exit 1
+fi
+
+if [ -d ~/.githooks ]; then
+    echo "! Expected creating a bundle to not install anything."
+    exit 1
+fi
+
+# Make the shared repository unreachable.
+mv "$GH_TEST_TMP/shared/first-shared.git" "$GH_TEST_TMP/shared/moved.git" || exit 1
+
+# A tampered release archive must be rejected.
+mkdir -p "$GH_TEST_TMP/tampered" &&
+    tar -xzf "$bundle" -C "$GH_TEST_TMP/tampered" &&
+    echo "tampered" >>"$(find "$GH_TEST_TMP/tampered/bin" -name "githooks.tar.gz")" &&
+    tar -czf "$GH_TEST_TMP/tampered.tar.gz" -C "$GH_TEST_TMP/tampered" . || exit 1
+
+if [ -f "$GH_TEST_TMP/tampered/deploy.yaml" ]; then
+    echo "! Expected the deploy settings to not be bundled."
+    exit 1
+fi
+
+if "$GH_TEST_BIN/cli" installer --non-interactive --from-bundle "$GH_TEST_TMP/tampered.tar.gz"; then
+    echo "! Expected installing from a tampered bundle to fail."
+    exit 1
+fi
+
+if [ -d ~/.githooks/bin ]; then
+    echo "! Expected a tampered bundle to not install anything."
+    exit 1
+fi
+
+# A release archive of another version must be rejected.
+mkdir -p "$GH_TEST_TMP/other" &&
+    tar -xzf "$bundle" -C "$GH_TEST_TMP/other" &&
+    sed -i -E 's/^tag: .*/tag: v0.0.1/' "$GH_TEST_TMP/other/bundle.yaml" &&
+    tar -czf "$GH_TEST_TMP/other.tar.gz" -C "$GH_TEST_TMP/other" . || exit 1
+
+if "$GH_TEST_BIN/cli" installer --non-interactive --from-bundle "$GH_TEST_TMP/other.tar.gz"; then
+    echo "! Expected installing a bundle with a wrong version to fail."
+    exit 1
+fi
+
+if [ -d ~/.githooks/bin ]; then
+    echo "! Expected a bundle with a wrong version to not install anything."
+    exit 1
+fi
+
+if ! "$GH_TEST_BIN/cli" installer --non-interactive --from-bundle "$bundle"; then
+    echo "! Failed to install from the bundle."
+    exit 1
+fi
+
+if [ "$(git config --global githooks.offline)" != "true" ]; then
+    echo "! Expected offline mode to be enabled."
+    exit 1
+fi
+
+if [ "$(git -C ~/.githooks/release config remote.origin.url)" != "$GH_TEST_REPO" ]; then
+    echo "! Expected the release clone to point to the clone url."
+    exit 1
+fi
+
+if [ "$(git -C ~/.githooks/release rev-parse HEAD)" != "$(git -C "$GH_TEST_REPO" rev-parse HEAD)" ]; then
+    echo "! Expected the release clone to be at the bundled version."
+    exit 1
+fi
+
+location=$("$GH_INSTALL_BIN_DIR/cli" shared root-from-url "$url") || exit 1
+if [ ! -f "$location/.githooks/pre-commit/sample-one" ] ||
+    [ "$(git -C "$location" config remote.origin.url)" != "$url" ]; then
+    echo "! Expected the bundled shared repository to be installed."
+    exit 1
+fi
+
+if ! git config --global --get-all githooks.shared | grep -q "first-shared"; then
+    echo "! Expected the bundled shared repository to be configured."
+    exit 1
+fi
@@ -0,0 +1,70 @@
+#!/usr/bin/env bash
+# Test:
+#   Uninstall: dry run and selective uninstall
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+acceptAllTrustPrompts || exit 1
+
+"$GH_TEST_BIN/cli" installer --non-interactive || exit 1
+
+mkdir -p "$GH_TEST_TMP/shared/first-shared.git/.githooks/pre-commit" &&
+    echo 'echo "Hello"' >"$GH_TEST_TMP/shared/first-shared.git/.githooks/pre-commit/sample-one" &&
+    (cd "$GH_TEST_TMP/shared/first-shared.git" && git init && git add . && git commit -m 'Testing') &&
+    "$GH_INSTALL_BIN_DIR/cli" shared add --global "file://$GH_TEST_TMP/shared/first-shared.git" &&
+    "$GH_INSTALL_BIN_DIR/cli" shared update ||
+    exit 1
+
+for repo in repo1 repo2; do
+    mkdir -p "$GH_TEST_TMP/test158/$repo" &&
+        cd "$GH_TEST_TMP/test158/$repo" &&
+        git init &&
+        "$GH_INSTALL_BIN_DIR/cli" install &&
+        mkdir -p .git/.githooks.checksums ||
+        exit 1
+done
+
+cd "$GH_TEST_TMP/test158" || exit 1
+
+OUT=$("$GH_INSTALL_BIN_DIR/cli" uninstaller --non-interactive --dry-run 2>&1)
+# shellcheck disable=SC2181
+if [ $? -ne 0 ] ||
+    ! echo "$OUT" | grep -q "repo1/.git/hooks/pre-commit" ||
+    ! echo "$OUT" | grep -q "repo2/.git/.githooks.checksums" ||
+    ! echo "$OUT" | grep -q "githooks.installDir" ||
+    ! echo "$OUT" | grep -q "\.githooks/shared'"; then
+    echo "! Expected dry run to report all removals."
+    echo "$OUT"
+    exit 1
+fi
+
+if [ ! -d ~/.githooks/bin ] ||
+    ! grep -q "github.com/gabyx/githooks" repo1/.git/hooks/pre-commit ||
+    [ -z "$(git config --global githooks.installDir)" ]; then
+    echo "! Expected dry run to not uninstall anything."
+    exit 1
+fi
+
+"$GH_INSTALL_BIN_DIR/cli" uninstaller --non-interactive --repo repo1 --keep-trust || exit 1
+
+if [ -f repo1/.git/hooks/pre-commit ] || [ ! -d repo1/.git/.githooks.checksums ]; then
+    echo "! Expected Githooks to be uninstalled from 'repo1' keeping the trust store."
+    exit 1
+fi
+
+if ! grep -q "github.com/gabyx/githooks" repo2/.git/hooks/pre-commit ||
+    [ ! -d ~/.githooks/bin ] ||
+    grep -q "repo1" ~/.githooks/registered.yaml; then
+    echo "! Expected only 'repo1' to be uninstalled."
+    exit 1
+fi
+
+"$GH_INSTALL_BIN_DIR/cli" uninstaller --non-interactive --keep-shared || exit 1
+
+if [ -d ~/.githooks/bin ] || [ ! -d ~/.githooks/shared ] ||
+    [ -n "$(git config --global githooks.installDir)" ]; then
+    echo "! Expected the installation to be removed except the shared clones."
+    exit 1
+fi
@@ -0,0 +1,66 @@
+#!/usr/bin/env bash
+# Test:
+#   Cli tool: health check with doctor and fix safe problems
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+acceptAllTrustPrompts || exit 1
+
+"$GH_TEST_BIN/cli" installer --non-interactive || exit 1
+
+mkdir -p "$GH_TEST_TMP/test159" &&
+    cd "$GH_TEST_TMP/test159" &&
+    git init &&
+    "$GH_INSTALL_BIN_DIR/cli" install || exit 1
+
+if ! "$GH_INSTALL_BIN_DIR/cli" doctor; then
+    echo "! Expected no problems after installation."
+    exit 1
+fi
+
+# Break the installation.
+echo "# outdated" >>.git/hooks/pre-commit &&
+    git config --unset githooks.registered &&
+    git config --global githooks.maintainOnlyServerHooks true &&
+    echo "- $GH_TEST_TMP/does-not-exist/.git" >>~/.githooks/registered.yaml ||
+    exit 1
+
+OUT=$("$GH_INSTALL_BIN_DIR/cli" doctor 2>&1)
+# shellcheck disable=SC2181
+if [ $? -eq 0 ] ||
+    ! echo "$OUT" | grep -q "pre-commit' is outdated" ||
+    ! echo "$OUT" | grep -q "is not registered" ||
+    ! echo "$OUT" | grep -q "githooks.maintainOnlyServerHooks" ||
+    ! echo "$OUT" | grep -q "does-not-exist"; then
+    echo "! Expected doctor to report all problems."
+    echo "$OUT"
+    exit 1
+fi
+
+if ! grep -q "# outdated" .git/hooks/pre-commit; then
+    echo "! Expected doctor without '--fix' to not change anything."
+    exit 1
+fi
+
+if ! "$GH_INSTALL_BIN_DIR/cli" doctor --fix; then
+    echo "! Expected doctor to fix all problems."
+    exit 1
+fi
+
+if grep -q "# outdated" .git/hooks/pre-commit ||
+    [ "$(git config githooks.registered)" != "true" ] ||
+    [ -n "$(git config --global githooks.maintainOnlyServerHooks)" ] ||
+    grep -q "does-not-exist" ~/.githooks/registered.yaml; then
+    echo "! Expected doctor to have fixed all problems."
+    exit 1
+fi
+
+OUT=$("$GH_INSTALL_BIN_DIR/cli" doctor 2>&1)
+# shellcheck disable=SC2181
+if [ $? -ne 0 ] || ! echo "$OUT" | grep -q "No problems found"; then
+    echo "! Expected no problems after fixing."
+    echo "$OUT"
+    exit 1
+fi
@@ -0,0 +1,102 @@
+#!/usr/bin/env bash
+# Test:
+#   Run install mode 'repo-hooks-path' and check that only the
+#   installed repository uses Githooks over a local 'core.hooksPath'
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+if echo "${EXTRA_INSTALL_ARGS:-}" | grep -q "use-core-hookspath"; then
+    echo "Using core.hooksPath"
+    exit 249
+fi
+
+acceptAllTrustPrompts || exit 1
+
+"$GH_TEST_BIN/cli" installer --non-interactive --use-repo-hookspath || exit 1
+
+if [ -n "$(git config --global core.hooksPath)" ] ||
+    [ -n "$(git config --global init.templateDir)" ]; then
+    echo "! Global 'core.hooksPath' or 'init.templateDir' should not be set."
+    exit 1
+fi
+
+hooksDir=$(git config --global githooks.pathForUseRepoHooksPath)
+if [ -z "$hooksDir" ] || ! grep -q 'github.com/gabyx/githooks' "$hooksDir/pre-commit"; then
+    echo "! Run-wrappers should be installed in '$hooksDir'."
+    exit 1
+fi
+
+mkdir -p "$GH_TEST_TMP/test160/a" "$GH_TEST_TMP/test160/b" || exit 1
+
+for repo in a b; do
+    cd "$GH_TEST_TMP/test160/$repo" &&
+        git init &&
+        mkdir -p .githooks/pre-commit &&
+        echo "echo '$repo' > '$GH_TEST_TMP/test160/$repo.out'" >.githooks/pre-commit/test &&
+        git add . || exit 1
+done
+
+# Repository 'a' has a previous local 'core.hooksPath'.
+cd "$GH_TEST_TMP/test160/a" &&
+    git config core.hooksPath "$GH_TEST_TMP/test160/previous-hooks" &&
+    "$GH_INSTALL_BIN_DIR/cli" install || exit 1
+
+if [ "$(git config --local core.hooksPath)" != "$hooksDir" ] ||
+    [ "$(git config --local githooks.previousCoreHooksPath)" != "$GH_TEST_TMP/test160/previous-hooks" ]; then
+    echo "! Local 'core.hooksPath' not set correctly."
+    git config --local --list
+    exit 1
+fi
+
+git commit -m "Test" || exit 1
+if [ ! -f "$GH_TEST_TMP/test160/a.out" ]; then
+    echo "! Hook in repository 'a' should have run."
+    exit 1
+fi
+
+# Repository 'b' is not installed and must not run hooks.
+cd "$GH_TEST_TMP/test160/b" && git commit -m "Test" || exit 1
+if [ -f "$GH_TEST_TMP/test160/b.out" ]; then
+    echo "! Hook in repository 'b' should not have run."
+    exit 1
+fi
+
+"$GH_INSTALL_BIN_DIR/cli" install || exit 1
+if [ "$(git config --local core.hooksPath)" != "$hooksDir" ]; then
+    echo "! Local 'core.hooksPath' not set in repository 'b'."
+    exit 1
+fi
+
+# Uninstall restores the previous value in 'a'.
+cd "$GH_TEST_TMP/test160/a" &&
+    "$GH_INSTALL_BIN_DIR/cli" uninstall || exit 1
+
+if [ "$(git config --local core.hooksPath)" != "$GH_TEST_TMP/test160/previous-hooks" ] ||
+    [ -n "$(git config --local githooks.previousCoreHooksPath)" ]; then
+    echo "! Local 'core.hooksPath' not restored in repository 'a'."
+    git config --local --list
+    exit 1
+fi
+
+# The global uninstall unsets the value in the registered repository 'b'.
+OUT=$("$GH_INSTALL_BIN_DIR/cli" uninstaller --dry-run 2>&1) || exit 1
+if ! echo "$OUT" | grep -q "Would unset local 'core.hooksPath'"; then
+    echo "! Expected dry run to report the local 'core.hooksPath'."
+    echo "$OUT"
+    exit 1
+fi
+
+"$GH_INSTALL_BIN_DIR/cli" uninstaller || exit 1
+
+cd "$GH_TEST_TMP/test160/b" || exit 1
+if [ -n "$(git config --local core.hooksPath)" ]; then
+    echo "! Local 'core.hooksPath' not unset in repository 'b'."
+    exit 1
+fi
+
+if [ "$(git -C "$GH_TEST_TMP/test160/a" config --local core.hooksPath)" != "$GH_TEST_TMP/test160/previous-hooks" ]; then
+    echo "! Local 'core.hooksPath' in repository 'a' should be untouched."
+    exit 1
+fi
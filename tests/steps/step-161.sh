@@ -0,0 +1,134 @@
+#!/usr/bin/env bash
+# Test:
+#   Run 'git hooks install' in repositories with other hook managers
+#   and check that they are chained or imported
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+if echo "${EXTRA_INSTALL_ARGS:-}" | grep -q "use-core-hookspath"; then
+    echo "Using core.hooksPath"
+    exit 249
+fi
+
+acceptAllTrustPrompts || exit 1
+
+"$GH_TEST_BIN/cli" installer --non-interactive || exit 1
+
+# Fake 'pre-commit' executable.
+mkdir -p "$GH_TEST_TMP/test161/bin" &&
+    echo "#!/bin/sh" >"$GH_TEST_TMP/test161/bin/pre-commit" &&
+    echo "echo \"\$@\" > '$GH_TEST_TMP/test161/pre-commit.out'" >>"$GH_TEST_TMP/test161/bin/pre-commit" &&
+    chmod +x "$GH_TEST_TMP/test161/bin/pre-commit" || exit 1
+export PATH="$GH_TEST_TMP/test161/bin:$PATH"
+
+# Repository with husky and pre-commit.
+mkdir -p "$GH_TEST_TMP/test161/a" &&
+    cd "$GH_TEST_TMP/test161/a" &&
+    git init &&
+    mkdir -p .husky/_ &&
+    echo "echo 'husky' > '$GH_TEST_TMP/test161/husky.out'" >.husky/pre-commit &&
+    echo "repos: []" >.pre-commit-config.yaml &&
+    git config core.hooksPath .husky/_ &&
+    echo "# File generated by pre-commit: https://pre-commit.com" >.git/hooks/pre-commit &&
+    chmod +x .git/hooks/pre-commit || exit 1
+
+"$GH_INSTALL_BIN_DIR/cli" install --non-interactive --external-hooks chain || exit 1
+
+if [ -n "$(git config --local core.hooksPath)" ] ||
+    [ "$(git config --local githooks.previousCoreHooksPath)" != ".husky/_" ]; then
+    echo "! Local 'core.hooksPath' of husky should be saved and unset."
+    git config --local --list
+    exit 1
+fi
+
+if [ "$(git config --local --get-all githooks.externalHookManagers | tr '\n' ' ')" != "husky pre-commit " ]; then
+    echo "! Hook managers should be chained."
+    git config --local --list
+    exit 1
+fi
+
+if [ -f .git/hooks/pre-commit.replaced.githook ] ||
+    ! grep -q 'github.com/gabyx/githooks' .git/hooks/pre-commit ||
+    ! grep -q "File generated by pre-commit" .git/hooks/pre-commit.external.githook; then
+    echo "! Hook generated by pre-commit should be backed up."
+    exit 1
+fi
+
+OUT=$("$GH_INSTALL_BIN_DIR/cli" list pre-commit 2>&1)
+if ! echo "$OUT" | grep -q "ns:gh-external/husky" ||
+    ! echo "$OUT" | grep -q "ns:gh-external/pre-commit"; then
+    echo "! Expected external hooks to be listed."
+    echo "$OUT"
+    exit 1
+fi
+
+git add . && git commit -m "Test" || exit 1
+
+if [ ! -f "$GH_TEST_TMP/test161/husky.out" ]; then
+    echo "! Chained husky hook should have run."
+    exit 1
+fi
+
+if ! grep -q "hook-impl --config=$GH_TEST_TMP/test161/a/.pre-commit-config.yaml --hook-type=pre-commit" \
+    "$GH_TEST_TMP/test161/pre-commit.out"; then
+    echo "! Chained pre-commit hook should have run."
+    cat "$GH_TEST_TMP/test161/pre-commit.out"
+    exit 1
+fi
+
+"$GH_INSTALL_BIN_DIR/cli" uninstall || exit 1
+
+if [ "$(git config --local core.hooksPath)" != ".husky/_" ]; then
+    echo "! Local 'core.hooksPath' of husky should be restored."
+    git config --local --list
+    exit 1
+fi
+
+if [ -f .git/hooks/pre-commit.external.githook ] ||
+    ! grep -q "File generated by pre-commit" .git/hooks/pre-commit; then
+    echo "! Hook generated by pre-commit should be restored."
+    exit 1
+fi
+
+# Repository with husky which gets imported.
+rm -f "$GH_TEST_TMP/test161/husky.out"
+mkdir -p "$GH_TEST_TMP/test161/b" &&
+    cd "$GH_TEST_TMP/test161/b" &&
+    git init &&
+    mkdir -p .husky &&
+    echo "echo 'husky' > '$GH_TEST_TMP/test161/husky.out'" >.husky/pre-commit &&
+    git config core.hooksPath .husky || exit 1
+
+"$GH_INSTALL_BIN_DIR/cli" install --non-interactive --external-hooks import || exit 1
+
+if [ ! -f .githooks/pre-commit/husky.yaml ] ||
+    ! grep -q "cmd: sh" .githooks/pre-commit/husky.yaml ||
+    [ -n "$(git config --local githooks.externalHookManagers)" ]; then
+    echo "! Husky hooks should be imported."
+    cat .githooks/pre-commit/husky.yaml
+    exit 1
+fi
+
+git add . && git commit -m "Test" || exit 1
+
+if [ ! -f "$GH_TEST_TMP/test161/husky.out" ]; then
+    echo "! Imported husky hook should have run."
+    exit 1
+fi
+
+# Skipping leaves the repository untouched.
+mkdir -p "$GH_TEST_TMP/test161/c" &&
+    cd "$GH_TEST_TMP/test161/c" &&
+    git init &&
+    echo "repos: []" >.pre-commit-config.yaml &&
+    echo "# File generated by pre-commit: https://pre-commit.com" >.git/hooks/pre-commit || exit 1
+
+"$GH_INSTALL_BIN_DIR/cli" install --non-interactive || exit 1
+
+if [ -n "$(git config --local githooks.externalHookManagers)" ] ||
+    ! grep -q "File generated by pre-commit" .git/hooks/pre-commit.replaced.githook; then
+    echo "! Hook managers should be skipped."
+    exit 1
+fi
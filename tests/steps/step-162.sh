@@ -0,0 +1,51 @@
+#!/usr/bin/env bash
+# Test:
+#   Cli tool: roll back an update of an install with `core.hooksPath`
+
+TEST_DIR=$(cd "$(dirname "$0")/.." && pwd)
+# shellcheck disable=SC1091
+. "$TEST_DIR/general.sh"
+
+acceptAllTrustPrompts || exit 1
+
+if [ -n "$GH_ON_WINDOWS" ]; then
+    echo "On windows -> skip."
+    exit 249
+fi
+
+"$GH_TEST_BIN/cli" installer --non-interactive --use-core-hookspath || exit 1
+
+HOOKS_PATH="$(git config --global core.hooksPath)"
+if [ -z "$HOOKS_PATH" ]; then
+    echo "! Expected 'core.hooksPath' to be set."
+    exit 1
+fi
+
+# Reset to trigger update
+if ! git -C "$GH_TEST_REPO" reset --hard v9.9.1 >/dev/null; then
+    echo "! Could not reset server to trigger update."
+    exit 1
+fi
+
+CURRENT="$(git -C ~/.githooks/release rev-parse HEAD)"
+"$GH_INSTALL_BIN_DIR/cli" update --yes || exit 1
+
+OUT=$("$GH_INSTALL_BIN_DIR/cli" update --rollback 2>&1)
+# shellcheck disable=SC2181
+if [ $? -ne 0 ] || ! echo "$OUT" | grep -q "Rolled back from version"; then
+    echo "! Expected rollback to succeed."
+    echo "$OUT"
+    exit 1
+fi
+
+if [ "$(git -C ~/.githooks/release rev-parse HEAD)" != "$CURRENT" ]; then
+    echo "! Expected release clone to be rolled back."
+    exit 1
+fi
+
+if [ "$(git config --global githooks.useCoreHooksPath)" != "true" ] ||
+    [ "$(git config --global core.hooksPath)" != "$HOOKS_PATH" ] ||
+    ! grep -q 'github.com/gabyx/githooks' "$HOOKS_PATH/pre-commit"; then
+    echo "! Expected the install mode 'core-hooks-path' to be kept."
+    exit 1
+fi